@@ -168,6 +168,12 @@ func UpdateChannel() string {
 	return GetString(KeyUpdateChannel)
 }
 
+// EnginePolicy returns the policy for templates whose engine
+// requirements are not met (warn or error)
+func EnginePolicy() string {
+	return GetString(KeyEnginePolicy)
+}
+
 func RegistryDir() string {
 	return GetString(KeyRegistryDir)
 }
@@ -23,6 +23,7 @@ const (
 	KeyDate            = "date"
 	KeyWindowHeight    = "window_height"
 	KeyWindowWidth     = "window_width"
+	KeyEnginePolicy    = "engine_policy"
 	APIGEAR_CONFIG_DIR = "APIGEAR_CONFIG_DIR"
 )
 
@@ -90,6 +91,7 @@ func NewConfig(cfgDir string) (*viper.Viper, error) {
 	nv.SetDefault(KeyVersion, "0.0.0")
 	nv.SetDefault(KeyWindowWidth, 960)
 	nv.SetDefault(KeyWindowHeight, 720)
+	nv.SetDefault(KeyEnginePolicy, "warn")
 	// public repo token for github to avoid rate limit
 	nv.SetDefault(KeyCommit, "none")
 	nv.SetDefault(KeyDate, "unknown")
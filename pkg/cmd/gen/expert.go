@@ -26,6 +26,13 @@ type ExpertOptions struct {
 	Force       bool
 	Watch       bool
 	TemplateDir string
+	Jobs        int
+	Archive     string
+	KeepGoing   bool
+	// FormatCommands enables external formatter commands of the template
+	FormatCommands bool
+	Overlays       []string
+	Params         map[string]string
 }
 
 func NewExpertCommand() *cobra.Command {
@@ -42,6 +49,9 @@ func NewExpertCommand() *cobra.Command {
 				return fmt.Errorf("invalid solution document: %w", err)
 			}
 			runner := sol.NewRunner()
+			runner.Options.Jobs = options.Jobs
+			runner.Options.KeepGoing = options.KeepGoing
+			runner.Options.FormatCommands = options.FormatCommands
 			ctx, cancel := context.WithCancel(context.Background())
 			defer cancel()
 
@@ -66,6 +76,12 @@ func NewExpertCommand() *cobra.Command {
 	cmd.Flags().StringSliceVarP(&options.Features, "features", "f", []string{"all"}, "features to enable")
 	cmd.Flags().BoolVarP(&options.Force, "force", "", false, "force overwrite")
 	cmd.Flags().BoolVarP(&options.Watch, "watch", "", false, "watch for changes")
+	cmd.Flags().StringSliceVarP(&options.Overlays, "overlay", "", []string{}, "overlay dirs shadowing template files and rules of the template")
+	cmd.Flags().StringToStringVarP(&options.Params, "param", "", map[string]string{}, "template params (e.g. --param namespace=demo)")
+	cmd.Flags().StringVarP(&options.Archive, "archive", "", "", "write the generated files into a zip or tar.gz archive instead of the output dir")
+	cmd.Flags().BoolVarP(&options.KeepGoing, "keep-going", "k", false, "report all failing documents instead of stopping at the first failure")
+	cmd.Flags().BoolVarP(&options.FormatCommands, "format-commands", "", false, "run external formatter commands declared in the template rules")
+	cmd.Flags().IntVarP(&options.Jobs, "jobs", "j", 0, "number of documents processed concurrently (0 uses all CPUs)")
 	Must(cmd.MarkFlagRequired("input"))
 	Must(cmd.MarkFlagRequired("output"))
 	Must(cmd.MarkFlagRequired("template"))
@@ -77,13 +93,21 @@ func MakeSolution(options *ExpertOptions) *spec.SolutionDoc {
 	if err != nil {
 		log.Fatal().Err(err).Msg("get current working directory")
 	}
+	// param values from the command line are converted to the declared types
+	params := make(map[string]any, len(options.Params))
+	for k, v := range options.Params {
+		params[k] = v
+	}
 	return &spec.SolutionDoc{
 		RootDir: rootDir,
 		Targets: []*spec.SolutionTarget{
 			{
 				Inputs:   options.Inputs,
 				Output:   options.OutputDir,
+				Archive:  options.Archive,
 				Template: options.TemplateDir,
+				Overlays: options.Overlays,
+				Params:   params,
 				Features: options.Features,
 				Force:    options.Force,
 			},
@@ -12,6 +12,6 @@ func NewRootCommand() *cobra.Command {
 		Short:   "Generate code from APIs",
 		Long:    `generate API SDKs from API descriptions using templates`,
 	}
-	cmd.AddCommand(NewExpertCommand(), NewSolutionCommand())
+	cmd.AddCommand(NewExpertCommand(), NewSolutionCommand(), NewStatusCommand())
 	return cmd
 }
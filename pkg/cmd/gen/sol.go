@@ -2,7 +2,12 @@ package gen
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
 
+	"github.com/apigear-io/cli/pkg/gen"
 	"github.com/apigear-io/cli/pkg/helper"
 	"github.com/apigear-io/cli/pkg/log"
 	"github.com/apigear-io/cli/pkg/sol"
@@ -16,6 +21,10 @@ func NewSolutionCommand() *cobra.Command {
 	var source string
 	var watch bool
 	var force bool
+	var opts sol.RunOptions
+	var diff bool
+	var patch string
+	var set []string
 	var cmd = &cobra.Command{
 		Use:     "solution [solution-file]",
 		Short:   "Generate SDK using a solution document",
@@ -27,26 +36,65 @@ as also the other options. To create a demo module or solution use the 'project
 		RunE: func(cmd *cobra.Command, args []string) error {
 			log.Info().Msgf("generating solution %s", args[0])
 			source = args[0]
-			return RunGenerateSolution(source, watch, force)
+			vars, err := spec.ParseVariables(set)
+			if err != nil {
+				return err
+			}
+			opts.Variables = vars
+			if diff || patch != "" {
+				if watch {
+					return fmt.Errorf("diff mode can not be combined with watch")
+				}
+				err := RunDiffSolution(cmd.OutOrStdout(), source, force, opts, patch)
+				if errors.Is(err, ErrOutputStale) || errors.Is(err, ErrSolutionInvalid) {
+					cmd.SilenceUsage = true
+				}
+				return err
+			}
+			err = RunGenerateSolution(source, watch, force, opts)
+			if errors.Is(err, ErrSolutionInvalid) {
+				cmd.SilenceUsage = true
+			}
+			return err
 		},
 	}
 	cmd.Flags().BoolVarP(&watch, "watch", "", false, "watch solution file for changes")
-	cmd.Flags().BoolVarP(&force, "force", "", false, "force overwrite")
+	cmd.Flags().StringArrayVarP(&opts.WatchInclude, "watch-include", "", nil, "glob pattern of files watched inside template and input dirs (e.g. '*.tpl'), can be repeated")
+	cmd.Flags().StringArrayVarP(&opts.WatchExclude, "watch-exclude", "", nil, "glob pattern of files and dirs ignored by the watch (e.g. 'build'), can be repeated")
+	cmd.Flags().DurationVarP(&opts.WatchDebounce, "debounce", "", tasks.DefaultDebounce, "quiet period after the last change before the solution is generated again")
+	cmd.Flags().BoolVarP(&force, "force", "", false, "force overwrite and generate targets which are up to date")
+	cmd.Flags().BoolVarP(&diff, "diff", "", false, "print a unified diff of the changes instead of writing files, fails when the output is out of date")
+	cmd.Flags().BoolVarP(&opts.DiffRemoved, "include-removed", "", false, "in diff mode report files inside the output dirs which are not generated as removed")
+	cmd.Flags().StringVarP(&patch, "patch", "", "", "write the changes as patch file (implies --diff)")
+	cmd.Flags().StringVarP(&opts.Archive, "archive", "", "", "write all targets into a zip or tar.gz archive instead of the output dirs")
+	cmd.Flags().BoolVarP(&opts.KeepGoing, "keep-going", "k", false, "report all failing documents instead of stopping at the first failure")
+	cmd.Flags().BoolVarP(&opts.FormatCommands, "format-commands", "", false, "run external formatter commands declared in the template rules")
+	cmd.Flags().IntVarP(&opts.Jobs, "jobs", "j", 0, "number of documents processed concurrently, shared by the targets generated in parallel (0 uses all CPUs)")
+	cmd.Flags().StringArrayVarP(&set, "set", "", nil, "set a solution variable (key=value), can be repeated")
+	cmd.Flags().StringVarP(&opts.Profile, "profile", "", "", "apply the named profile of the solution")
+	cmd.Flags().BoolVarP(&opts.SkipHooks, "no-hooks", "", false, "do not run the hook commands of the solution and its targets")
+	cmd.Flags().BoolVarP(&opts.Locked, "locked", "", false, "fail for templates not pinned in apigear.lock and never write the lock file (e.g. in CI)")
+	cmd.Flags().StringVarP(&opts.Report, "report", "", "", "write the results of each run to a report file (e.g. report.json, report.xml or report.sarif)")
+	cmd.Flags().StringVarP(&opts.ReportFormat, "report-format", "", "", "report format: json, junit or sarif (default derived from the report file extension)")
 	return cmd
 }
 
-func RunGenerateSolution(solutionPath string, watch bool, force bool) error {
+// ErrSolutionInvalid is returned when the solution document fails the schema validation
+var ErrSolutionInvalid = errors.New("solution document is not valid")
+
+func RunGenerateSolution(solutionPath string, watch bool, force bool, opts sol.RunOptions) error {
 	result, err := spec.CheckFileAndType(solutionPath, spec.DocumentTypeSolution)
 	if err != nil {
 		return err
 	}
 	if !result.Valid() {
 		for _, err := range result.Errors {
-			log.Warn().Msgf("source %s at %s error %s", solutionPath, err.Field, err.Description)
+			log.Error().Msgf("source %s at %s error %s", solutionPath, err.Field, err.Description)
 		}
-		return nil
+		return fmt.Errorf("%s: %w", solutionPath, ErrSolutionInvalid)
 	}
 	runner := sol.NewRunner()
+	runner.Options = opts
 	runner.OnTask(func(evt *tasks.TaskEvent) {
 		log.Debug().Msgf("[%s] task %s: %v", evt.State, evt.Name, evt.Meta)
 	})
@@ -68,3 +116,35 @@ func RunGenerateSolution(solutionPath string, watch bool, force bool) error {
 	}
 	return nil
 }
+
+// ErrOutputStale is returned in diff mode when the generated output is out of date
+var ErrOutputStale = errors.New("generated output is out of date")
+
+// RunDiffSolution renders the solution without writing any files
+// and prints the differences to the existing output.
+// Optionally the differences are written to a patch file.
+func RunDiffSolution(w io.Writer, solutionPath string, force bool, opts sol.RunOptions, patchFile string) error {
+	// the runner sets the solution root dir as base dir of the diff paths
+	diff := gen.NewDiffWriter("")
+	opts.Diff = diff
+	err := RunGenerateSolution(solutionPath, false, force, opts)
+	if err != nil {
+		return err
+	}
+	patch := diff.Patch()
+	if patchFile != "" {
+		err := os.WriteFile(patchFile, []byte(patch), 0644)
+		if err != nil {
+			return err
+		}
+	}
+	if !diff.IsStale() {
+		log.Info().Msg("generated output is up to date")
+		return nil
+	}
+	for _, c := range diff.Changes() {
+		log.Info().Msgf("%s: %s", c.Status, c.Target)
+	}
+	fmt.Fprint(w, patch)
+	return ErrOutputStale
+}
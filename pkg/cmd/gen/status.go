@@ -0,0 +1,55 @@
+package gen
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/apigear-io/cli/pkg/sol"
+	"github.com/apigear-io/cli/pkg/spec"
+	"github.com/spf13/cobra"
+)
+
+func NewStatusCommand() *cobra.Command {
+	var set []string
+	var profile string
+	var cmd = &cobra.Command{
+		Use:   "status [solution-file]",
+		Short: "List the solution targets which are out of date",
+		Long: `Compares the inputs, templates, target configuration and cli version of each
+solution target with the last successful generation. Stale targets are
+generated again by the next 'generate solution' run, up-to-date targets are skipped.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vars, err := spec.ParseVariables(set)
+			if err != nil {
+				return err
+			}
+			doc, err := sol.ReadSolutionDocWithOptions(args[0], spec.ResolveOptions{
+				Profile:   profile,
+				Variables: vars,
+			})
+			if err != nil {
+				return err
+			}
+			infos, err := sol.SolutionStatus(doc)
+			if err != nil {
+				return err
+			}
+			displayTargetStatus(cmd.OutOrStdout(), infos)
+			return nil
+		},
+	}
+	cmd.Flags().StringArrayVarP(&set, "set", "", nil, "set a solution variable (key=value), can be repeated")
+	cmd.Flags().StringVarP(&profile, "profile", "", "", "apply the named profile of the solution")
+	return cmd
+}
+
+func displayTargetStatus(w io.Writer, infos []*sol.TargetStatusInfo) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "target\tstatus\treason")
+	for _, info := range infos {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", info.Name, info.Status, info.Reason)
+	}
+	tw.Flush()
+}
@@ -22,7 +22,7 @@ func NewCreateCommand() *cobra.Command {
 	if err != nil {
 		log.Error().Err(err).Msg("failed to mark flag required")
 	}
-	cmd.Flags().StringVarP(&lang, "lang", "l", "cpp", "language to init [cpp, go, py, rs, ts, ue]")
+	cmd.Flags().StringVarP(&lang, "lang", "l", "cpp", "language to init [cpp, cs, go, py, rs, ts, ue]")
 	err = cmd.MarkFlagRequired("lang")
 	if err != nil {
 		log.Error().Err(err).Msg("failed to mark flag required")
@@ -2,8 +2,11 @@ package tpl
 
 import (
 	"fmt"
+	"io"
+	"text/tabwriter"
 
 	"github.com/apigear-io/cli/pkg/git"
+	"github.com/apigear-io/cli/pkg/spec"
 	"github.com/pterm/pterm"
 )
 
@@ -65,3 +68,22 @@ func DisplayTemplateInfo(info *git.RepoInfo) {
 	fmt.Printf("Versions: 	%v\n", info.Versions)
 	fmt.Println()
 }
+
+// DisplayTemplateParams writes the params declared by a template as table
+func DisplayTemplateParams(w io.Writer, params []*spec.ParamRule) {
+	if len(params) == 0 {
+		fmt.Fprintln(w, "Params:   	none")
+		return
+	}
+	fmt.Fprintln(w, "Params:")
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "  name\ttype\tdefault\tchoices\tdescription")
+	for _, p := range params {
+		choices := ""
+		if len(p.Enum) > 0 {
+			choices = fmt.Sprint(p.Enum)
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%v\t%s\t%s\n", p.Name, p.Type, p.Zero(), choices, p.Description)
+	}
+	tw.Flush()
+}
@@ -1,7 +1,9 @@
 package tpl
 
 import (
+	"github.com/apigear-io/cli/pkg/helper"
 	"github.com/apigear-io/cli/pkg/repos"
+	"github.com/apigear-io/cli/pkg/tpl"
 	"github.com/spf13/cobra"
 )
 
@@ -9,16 +11,37 @@ func NewInfoCommand() *cobra.Command {
 	var cmd = &cobra.Command{
 		Use:   "info [name]",
 		Short: "display template information from registry",
-		Args:  cobra.ExactArgs(1),
+		Long: `Displays the registry information of a template and the template params,
+when the template is installed. The name can also be a local template dir.`,
+		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			name := args[0]
+			if helper.IsFile(helper.Join(name, "rules.yaml")) {
+				displayParams(cmd, name)
+				return
+			}
 			info, err := repos.Registry.Get(name)
 			if err != nil {
 				cmd.PrintErrln(err)
 				return
 			}
 			DisplayTemplateInfo(info)
+			dir, err := repos.Cache.GetTemplateDir(name)
+			if err != nil {
+				// template is not installed
+				return
+			}
+			displayParams(cmd, dir)
 		},
 	}
 	return cmd
 }
+
+func displayParams(cmd *cobra.Command, dir string) {
+	params, err := tpl.ReadParams(dir)
+	if err != nil {
+		cmd.PrintErrln(err)
+		return
+	}
+	DisplayTemplateParams(cmd.OutOrStdout(), params)
+}
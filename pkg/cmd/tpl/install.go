@@ -1,6 +1,10 @@
 package tpl
 
 import (
+	"fmt"
+
+	"github.com/apigear-io/cli/pkg/gen"
+	"github.com/apigear-io/cli/pkg/log"
 	"github.com/apigear-io/cli/pkg/repos"
 
 	"github.com/spf13/cobra"
@@ -10,20 +14,50 @@ func NewInstallCommand() *cobra.Command {
 	// cmd represents the pkgInstall command
 	var version string
 	var cmd = &cobra.Command{
-		Use:     "install [name]",
-		Short:   "install template into cache by name from registry",
+		Use:   "install [name]",
+		Short: "install template into cache by name from registry",
+		Long: `install template into cache by name from registry.
+The version can be an exact tag (e.g. name@v1.2.0), latest or a
+semver range (e.g. name@^1.2, name@~1.4.0 or "name@>=2 <3"),
+which installs the highest matching version.
+The engine requirements of the template rules are checked using
+the configured engine policy (engine_policy: warn or error).`,
 		Aliases: []string{"i"},
 		Args:    cobra.ExactArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			repoID := args[0]
-			fixedRepoId, err := repos.GetOrInstallTemplateFromRepoID(repoID)
-			cmd.Printf("using template %s\n", fixedRepoId)
+			if !repos.IsRepoID(repoID) {
+				repoID = repos.MakeRepoID(repoID, version)
+			}
+			fixedRepoId, err := repos.Registry.FixRepoId(repoID)
+			if err != nil {
+				return err
+			}
+			installed := repos.Cache.Exists(fixedRepoId)
+			fixedRepoId, err = repos.GetOrInstallTemplateFromRepoID(fixedRepoId)
 			if err != nil {
-				cmd.PrintErrln(err)
-				return
+				return err
 			}
+			dir, err := repos.Cache.GetTemplateDir(fixedRepoId)
+			if err != nil {
+				return err
+			}
+			// warnings are logged by the check
+			_, err = gen.CheckTemplateEngine(dir, "")
+			if err != nil {
+				// do not keep an incompatible template, which was installed by this command
+				if !installed {
+					if rerr := repos.Cache.Remove(fixedRepoId); rerr != nil {
+						log.Warn().Err(rerr).Msgf("remove template %s", fixedRepoId)
+					}
+				}
+				cmd.SilenceUsage = true
+				return fmt.Errorf("template %s is not compatible: %w", fixedRepoId, err)
+			}
+			cmd.Printf("using template %s\n", fixedRepoId)
+			return nil
 		},
 	}
-	cmd.Flags().StringVarP(&version, "version", "v", "latest", "template version to install")
+	cmd.Flags().StringVarP(&version, "version", "v", "latest", "template version or semver range to install")
 	return cmd
 }
@@ -1,9 +1,10 @@
 package tpl
 
 import (
-	"github.com/apigear-io/cli/pkg/gen"
+	"fmt"
+
 	"github.com/apigear-io/cli/pkg/log"
-	"github.com/apigear-io/cli/pkg/model"
+	"github.com/apigear-io/cli/pkg/tpl"
 	"github.com/spf13/cobra"
 )
 
@@ -12,20 +13,24 @@ func NewLintCommand() *cobra.Command {
 	var cmd = &cobra.Command{
 		Use:   "lint",
 		Short: "lint a custom template",
-		Run: func(cmd *cobra.Command, args []string) {
-			// trying to create a generator, it will fail
-			// if the templates in the dir are not valid
-			_, err := gen.New(gen.Options{
-				TemplatesDir: dir,
-				System:       model.NewSystem("test"),
-				Features:     []string{"all"},
-				Force:        true,
-			})
+		Long: `Cross-checks the rules document against the templates dir (missing sources,
+unused templates, unknown required features, invalid target expressions) and executes
+every template against a synthetic API covering all type kinds to catch runtime errors.
+The dir is either the template dir containing the rules document or its templates folder.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			findings, err := tpl.Lint(dir)
 			if err != nil {
-				cmd.Printf("template dir '%s' is not valid: %s\n", dir, err)
-			} else {
-				cmd.Printf("template dir '%s' is valid\n", dir)
+				return err
+			}
+			for _, f := range findings {
+				cmd.Println(f.String())
+			}
+			if tpl.HasLintErrors(findings) {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("template dir '%s' is not valid", dir)
 			}
+			cmd.Printf("template dir '%s' is valid\n", dir)
+			return nil
 		},
 	}
 	cmd.Flags().StringVarP(&dir, "dir", "d", ".", "template directory")
@@ -0,0 +1,61 @@
+package tpl
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/apigear-io/cli/pkg/sol"
+	"github.com/apigear-io/cli/pkg/spec"
+	"github.com/spf13/cobra"
+)
+
+func NewLockCommand() *cobra.Command {
+	var update bool
+	var cmd = &cobra.Command{
+		Use:   "lock [solution-file]",
+		Short: "pin the templates of a solution in the apigear.lock file",
+		Long: `Resolves the templates of all solution targets and records the exact
+repo version and commit in the apigear.lock file next to the solution.
+Generating the solution uses the locked templates. Targets not yet locked are added,
+use --update to resolve all templates again (e.g. to move to the latest version).`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mode := spec.LockAuto
+			if update {
+				mode = spec.LockUpdate
+			}
+			doc, err := sol.ReadSolutionDocWithOptions(args[0], spec.ResolveOptions{Lock: mode})
+			if err != nil {
+				return err
+			}
+			lock, err := doc.LockFile()
+			if err != nil {
+				return err
+			}
+			lock.Prune(doc.LockKeys())
+			if lock.Changed() || !lock.Exists() {
+				if err := lock.Write(); err != nil {
+					return err
+				}
+				cmd.Printf("lock file %s written\n", lock.File())
+			} else {
+				cmd.Printf("lock file %s is up to date\n", lock.File())
+			}
+			displayLockFile(cmd.OutOrStdout(), lock)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVarP(&update, "update", "u", false, "resolve all templates again and update the locked versions")
+	return cmd
+}
+
+func displayLockFile(w io.Writer, lock *spec.LockFile) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "target\ttemplate\trepo\tcommit")
+	for _, name := range lock.Names() {
+		t := lock.Targets[name]
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", name, t.Template, t.Repo, t.Commit)
+	}
+	tw.Flush()
+}
@@ -11,6 +11,8 @@ import "github.com/spf13/cobra"
 // apigear template info  -- get information about a template from the registry
 // apigear template create -- create a new custom template ((--lang language))
 // apigear template import -- import a template from a local directory or git url
+// apigear template test -- test a custom template against golden files
+// apigear template lock -- pin the templates of a solution in apigear.lock
 func NewRootCommand() *cobra.Command {
 	// cmd represents the tpl command
 	cmd := &cobra.Command{
@@ -30,6 +32,8 @@ func NewRootCommand() *cobra.Command {
 	// custom template commands
 	cmd.AddCommand(NewCreateCommand())
 	cmd.AddCommand(NewLintCommand())
+	cmd.AddCommand(NewTestCommand())
 	cmd.AddCommand(NewPublishCommand())
+	cmd.AddCommand(NewLockCommand())
 	return cmd
 }
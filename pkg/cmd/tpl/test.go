@@ -0,0 +1,55 @@
+package tpl
+
+import (
+	"fmt"
+
+	"github.com/apigear-io/cli/pkg/tpl"
+	"github.com/spf13/cobra"
+)
+
+func NewTestCommand() *cobra.Command {
+	var opts tpl.TestOptions
+	var cmd = &cobra.Command{
+		Use:   "test",
+		Short: "test a custom template against golden files",
+		Long: `Renders every fixture module inside the template "tests" folder (e.g. tests/demo.idl)
+and compares the output with the golden directory next to it (e.g. tests/demo.golden).
+Use --update to write the rendered output into the golden directories.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			results, err := tpl.RunTests(opts)
+			if err != nil {
+				return err
+			}
+			failed := 0
+			for _, r := range results {
+				switch {
+				case r.Err != nil:
+					failed++
+					cmd.Printf("FAIL %s: %s\n", r.Name, r.Err)
+				case r.Updated:
+					cmd.Printf("UPDATE %s\n", r.Name)
+				case !r.Passed():
+					failed++
+					cmd.Printf("FAIL %s\n", r.Name)
+					for _, c := range r.Changes {
+						cmd.Printf("  %s: %s\n", c.Status, c.Target)
+					}
+					for _, c := range r.Changes {
+						cmd.Print(c.Diff)
+					}
+				default:
+					cmd.Printf("PASS %s\n", r.Name)
+				}
+			}
+			if failed > 0 {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("%d of %d template tests failed", failed, len(results))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&opts.Dir, "dir", "d", ".", "template directory")
+	cmd.Flags().StringSliceVarP(&opts.Features, "features", "f", []string{"all"}, "features to enable")
+	cmd.Flags().BoolVarP(&opts.Update, "update", "u", false, "update the golden directories with the rendered output")
+	return cmd
+}
@@ -9,14 +9,26 @@ import (
 func NewUpdateCommand() *cobra.Command {
 	// cmd represents the pkgInstall command
 	var cmd = &cobra.Command{
-		Use:   "update",
+		Use:   "update [name@range...]",
 		Short: "update the template registry",
+		Long: `update the template registry.
+Templates given with a semver range (e.g. name@^1.2) are updated to the
+highest version matching the range, which is installed into the cache.`,
 		Run: func(cmd *cobra.Command, args []string) {
 			err := repos.Registry.Update()
 			if err != nil {
 				cmd.PrintErrln(err)
+				return
 			}
 			cmd.Println("template registry updated")
+			for _, repoID := range args {
+				fixedRepoId, err := repos.GetOrInstallTemplateFromRepoID(repoID)
+				if err != nil {
+					cmd.PrintErrln(err)
+					continue
+				}
+				cmd.Printf("template %s updated to %s\n", repoID, fixedRepoId)
+			}
 		},
 	}
 	return cmd
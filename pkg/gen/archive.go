@@ -0,0 +1,161 @@
+package gen
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/apigear-io/cli/pkg/helper"
+)
+
+// ArchiveFormat is the format of an output archive
+type ArchiveFormat string
+
+const (
+	ArchiveZip   ArchiveFormat = "zip"
+	ArchiveTarGz ArchiveFormat = "tar.gz"
+)
+
+// ArchiveFormatFromFile returns the archive format based on the file extension.
+// Supported extensions are .zip, .tar.gz and .tgz.
+func ArchiveFormatFromFile(file string) (ArchiveFormat, error) {
+	name := strings.ToLower(file)
+	switch {
+	case strings.HasSuffix(name, ".zip"):
+		return ArchiveZip, nil
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return ArchiveTarGz, nil
+	}
+	return "", fmt.Errorf("unsupported archive format %s, use .zip, .tar.gz or .tgz", file)
+}
+
+// ArchiveWriter is an output writer which collects the generated files
+// and writes them into a zip or tar.gz archive when closed.
+type ArchiveWriter struct {
+	*MemoryWriter
+	file   string
+	format ArchiveFormat
+}
+
+var _ OutputWriter = (*ArchiveWriter)(nil)
+
+// NewArchiveWriter creates a new archive output writer.
+// The archive format is derived from the file extension.
+// Entries in the archive are relative to the base dir.
+func NewArchiveWriter(file string, baseDir string) (*ArchiveWriter, error) {
+	format, err := ArchiveFormatFromFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return &ArchiveWriter{
+		MemoryWriter: NewMemoryWriter(baseDir),
+		file:         file,
+		format:       format,
+	}, nil
+}
+
+// File returns the archive file path
+func (a *ArchiveWriter) File() string {
+	return a.file
+}
+
+// Close writes the collected files into the archive file.
+// The archive is written into a temporary file, which replaces the
+// archive file on success, so a failed write keeps an existing archive.
+func (a *ArchiveWriter) Close() error {
+	log.Info().Msgf("write archive %s", a.file)
+	dir := filepath.Dir(a.file)
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, "."+filepath.Base(a.file)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	switch a.format {
+	case ArchiveZip:
+		err = a.writeZip(f)
+	case ArchiveTarGz:
+		err = a.writeTarGz(f)
+	}
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write archive %s: %w", a.file, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("write archive %s: %w", a.file, err)
+	}
+	return os.Rename(f.Name(), a.file)
+}
+
+// Discard drops the collected files without writing the archive.
+// An existing archive file is kept unchanged.
+func (a *ArchiveWriter) Discard() {
+	if helper.IsFile(a.file) {
+		log.Warn().Msgf("discard partial archive, keep existing archive %s", a.file)
+	} else {
+		log.Warn().Msgf("discard partial archive %s", a.file)
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.files = map[string][]byte{}
+}
+
+func (a *ArchiveWriter) writeZip(w io.Writer) error {
+	zw := zip.NewWriter(w)
+	now := time.Now()
+	for _, name := range a.Names() {
+		content, err := a.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		fw, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     name,
+			Method:   zip.Deflate,
+			Modified: now,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(content); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func (a *ArchiveWriter) writeTarGz(w io.Writer) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	now := time.Now()
+	for _, name := range a.Names() {
+		content, err := a.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		err = tw.WriteHeader(&tar.Header{
+			Name:    name,
+			Mode:    0644,
+			Size:    int64(len(content)),
+			ModTime: now,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := tw.Write(content); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
@@ -0,0 +1,239 @@
+package gen
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/apigear-io/cli/pkg/helper"
+	"github.com/apigear-io/cli/pkg/spec"
+)
+
+// A template can extend another template and include other templates.
+// The precedence from low to high is: extended template, template, overlays.
+// Included templates do not take part in the precedence, as all their
+// feature and template names are prefixed with the include namespace.
+// Params are not namespaced, params of the including template win.
+
+// TemplateLayer is a templates dir whose templates are parsed
+// with an optional namespace prefixed to all template names.
+type TemplateLayer struct {
+	// Dir is the templates dir
+	Dir string
+	// Namespace is prefixed to the template names (e.g. "common/")
+	Namespace string
+}
+
+// ComposedRules is a rules document with the extended and included templates resolved
+type ComposedRules struct {
+	// Doc is the rules document containing the features of all composed templates
+	Doc *spec.RulesDoc
+	// Layers are the templates dirs of the extended and included templates
+	// in precedence order, the templates dir of the template itself is not part of it
+	Layers []TemplateLayer
+}
+
+// ComposeRules reads the rules document of the template dir, resolves the extended
+// and included templates and merges the rules fragments of the given overlays.
+func ComposeRules(templateDir string, overlays []string) (*ComposedRules, error) {
+	c := &ComposedRules{}
+	doc, err := c.compose(templateDir, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	c.Doc = doc
+	// the own templates dir is passed as generator templates dir
+	own := helper.Join(templateDir, "templates")
+	layers := c.Layers[:0]
+	for _, layer := range c.Layers {
+		if layer.Namespace == "" && layer.Dir == own {
+			continue
+		}
+		layers = append(layers, layer)
+	}
+	c.Layers = layers
+	err = mergeOverlayRules(c.Doc, overlays)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// compose reads the rules of the template dir with all names prefixed by the namespace.
+// The stack contains the template dirs currently composed to detect cycles.
+func (c *ComposedRules) compose(templateDir string, ns string, stack []string) (*spec.RulesDoc, error) {
+	templateDir = filepath.Clean(templateDir)
+	for i, dir := range stack {
+		if dir == templateDir {
+			cycle := append(stack[i:], templateDir)
+			return nil, fmt.Errorf("template composition cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+	stack = append(stack, templateDir)
+	doc, err := ReadRulesDoc(helper.Join(templateDir, "rules.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	doc.Namespace(ns)
+	result := doc
+	if doc.Extends != "" {
+		_, baseDir, err := spec.ResolveTemplateDir(templateDir, doc.Extends)
+		if err != nil {
+			return nil, fmt.Errorf("resolve extended template %s: %w", doc.Extends, err)
+		}
+		log.Debug().Msgf("template %s extends %s", templateDir, baseDir)
+		base, err := c.compose(baseDir, ns, stack)
+		if err != nil {
+			return nil, err
+		}
+		// the template takes precedence over the extended template
+		base.Merge(doc)
+		base.Name = doc.Name
+		result = base
+	}
+	for _, inc := range doc.Includes {
+		_, incDir, err := spec.ResolveTemplateDir(templateDir, inc.Template)
+		if err != nil {
+			return nil, fmt.Errorf("resolve included template %s: %w", inc.Template, err)
+		}
+		log.Debug().Msgf("template %s includes %s as %s", templateDir, incDir, inc.As)
+		included, err := c.compose(incDir, ns+inc.As+"/", stack)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range included.Features {
+			if result.FeatureByName(f.Name) != nil {
+				return nil, fmt.Errorf("included feature %s already defined", f.Name)
+			}
+			result.Features = append(result.Features, f)
+		}
+		// params share one namespace, the including template wins
+		for _, p := range included.Params {
+			if result.ParamByName(p.Name) == nil {
+				result.Params = append(result.Params, p)
+			}
+		}
+		for _, lang := range included.Languages {
+			if !containsLang(result.Languages, lang) {
+				result.Languages = append(result.Languages, lang)
+			}
+		}
+	}
+	c.Layers = append(c.Layers, TemplateLayer{
+		Dir:       helper.Join(templateDir, "templates"),
+		Namespace: ns,
+	})
+	return result, nil
+}
+
+func containsLang(langs []string, lang string) bool {
+	for _, l := range langs {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseLayers parses the templates of the layers into the template set.
+// Later layers replace templates with the same name.
+// Inside a namespaced layer all template names, including the names of
+// defined templates and the template calls referring to them, are prefixed
+// with the namespace.
+func ParseLayers(t *template.Template, layers []TemplateLayer, funcs template.FuncMap) error {
+	trees := map[string]map[string]*parse.Tree{}
+	order := []string{}
+	for _, layer := range layers {
+		if !helper.IsDir(layer.Dir) {
+			continue
+		}
+		if _, ok := trees[layer.Namespace]; !ok {
+			trees[layer.Namespace] = map[string]*parse.Tree{}
+			order = append(order, layer.Namespace)
+		}
+		err := parseLayerTrees(layer.Dir, funcs, trees[layer.Namespace])
+		if err != nil {
+			return err
+		}
+	}
+	for _, ns := range order {
+		for name, tree := range trees[ns] {
+			if ns != "" {
+				WalkTemplateCalls(tree.Root, func(n *parse.TemplateNode) {
+					if _, ok := trees[ns][n.Name]; ok {
+						n.Name = ns + n.Name
+					}
+				})
+				tree.Name = ns + name
+				tree.ParseName = ns + tree.ParseName
+			}
+			if _, err := t.AddParseTree(ns+name, tree); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// parseLayerTrees parses all template files inside the dir and records
+// the parse trees of the files and their defined templates by name
+func parseLayerTrees(dir string, funcs template.FuncMap, trees map[string]*parse.Tree) error {
+	return filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".") || !strings.HasSuffix(d.Name(), ".tpl") {
+			return nil
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		tpl, err := template.New(name).Funcs(funcs).Parse(string(b))
+		if err != nil {
+			return err
+		}
+		for _, tt := range tpl.Templates() {
+			if tt.Tree == nil {
+				continue
+			}
+			// keep an existing template when redefined by an empty one
+			if _, ok := trees[tt.Name()]; ok && parse.IsEmptyTree(tt.Tree.Root) {
+				continue
+			}
+			trees[tt.Name()] = tt.Tree
+		}
+		return nil
+	})
+}
+
+// WalkTemplateCalls calls fn for every template call ({{ template "name" }}) inside the node
+func WalkTemplateCalls(node parse.Node, fn func(*parse.TemplateNode)) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Nodes {
+			WalkTemplateCalls(c, fn)
+		}
+	case *parse.TemplateNode:
+		fn(n)
+	case *parse.IfNode:
+		WalkTemplateCalls(n.List, fn)
+		WalkTemplateCalls(n.ElseList, fn)
+	case *parse.RangeNode:
+		WalkTemplateCalls(n.List, fn)
+		WalkTemplateCalls(n.ElseList, fn)
+	case *parse.WithNode:
+		WalkTemplateCalls(n.List, fn)
+		WalkTemplateCalls(n.ElseList, fn)
+	}
+}
@@ -0,0 +1,245 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/apigear-io/cli/pkg/helper"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// ChangeStatus describes how a generated file differs from the existing output
+type ChangeStatus string
+
+const (
+	ChangeAdded    ChangeStatus = "added"
+	ChangeModified ChangeStatus = "modified"
+	ChangeRemoved  ChangeStatus = "removed"
+)
+
+// FileChange is a difference between the existing output and the rendered content
+type FileChange struct {
+	// Target is the file path relative to the base dir
+	Target string `json:"target"`
+	// Status is the kind of change
+	Status ChangeStatus `json:"status"`
+	// Diff is the unified diff of the change
+	Diff string `json:"diff"`
+}
+
+// DiffWriter is an output writer which does not write any files,
+// instead it records the differences between the existing output
+// and the newly rendered content as unified diffs.
+type DiffWriter struct {
+	mu      sync.Mutex
+	baseDir string
+	changes map[string]*FileChange
+	seen    map[string]bool
+}
+
+var _ OutputWriter = (*DiffWriter)(nil)
+
+// NewDiffWriter creates a new diff writer.
+// Paths in the diffs are relative to the given base dir.
+func NewDiffWriter(baseDir string) *DiffWriter {
+	return &DiffWriter{
+		baseDir: baseDir,
+		changes: make(map[string]*FileChange),
+		seen:    make(map[string]bool),
+	}
+}
+
+func (d *DiffWriter) Write(input []byte, target string) error {
+	return d.record(input, target)
+}
+
+func (d *DiffWriter) Copy(source, target string) error {
+	input, err := os.ReadFile(source)
+	if err != nil {
+		return err
+	}
+	return d.record(input, target)
+}
+
+func (d *DiffWriter) Compare(input []byte, target string) (bool, error) {
+	d.markSeen(target)
+	return CompareContentWithFile(input, target)
+}
+
+func (d *DiffWriter) Exists(target string) bool {
+	return helper.IsFile(target)
+}
+
+// Skip marks a target skipped by the generator as known,
+// so it is not reported as removed
+func (d *DiffWriter) Skip(target string) {
+	d.markSeen(target)
+}
+
+func (d *DiffWriter) markSeen(target string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.seen[filepath.Clean(target)] = true
+}
+
+// record compares the input with the existing target and records the change
+func (d *DiffWriter) record(input []byte, target string) error {
+	d.markSeen(target)
+	var existing []byte
+	status := ChangeAdded
+	if _, err := os.Stat(target); err == nil {
+		existing, err = os.ReadFile(target)
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(existing, input) {
+			return nil
+		}
+		status = ChangeModified
+	}
+	rel := d.relPath(target)
+	from := "a/" + rel
+	if status == ChangeAdded {
+		from = "/dev/null"
+	}
+	diff, err := unifiedDiff(from, "b/"+rel, existing, input)
+	if err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.changes[rel] = &FileChange{Target: rel, Status: status, Diff: diff}
+	return nil
+}
+
+// DetectRemoved records all files inside the given directories,
+// which were not part of the generated output, as removed.
+// Hidden files and directories are ignored.
+func (d *DiffWriter) DetectRemoved(dirs ...string) error {
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(path string, e fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if strings.HasPrefix(e.Name(), ".") && path != dir {
+				if e.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if e.IsDir() {
+				return nil
+			}
+			d.mu.Lock()
+			seen := d.seen[filepath.Clean(path)]
+			d.mu.Unlock()
+			if seen {
+				return nil
+			}
+			existing, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			rel := d.relPath(path)
+			diff, err := unifiedDiff("a/"+rel, "/dev/null", existing, nil)
+			if err != nil {
+				return err
+			}
+			d.mu.Lock()
+			defer d.mu.Unlock()
+			d.changes[rel] = &FileChange{Target: rel, Status: ChangeRemoved, Diff: diff}
+			return nil
+		})
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Changes returns the recorded changes sorted by target
+func (d *DiffWriter) Changes() []*FileChange {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	changes := make([]*FileChange, 0, len(d.changes))
+	for _, c := range d.changes {
+		changes = append(changes, c)
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Target < changes[j].Target
+	})
+	return changes
+}
+
+// IsStale returns true if the existing output differs from the rendered content
+func (d *DiffWriter) IsStale() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.changes) > 0
+}
+
+// Patch returns all changes as one patch which can be applied using git apply
+func (d *DiffWriter) Patch() string {
+	var sb strings.Builder
+	for _, c := range d.Changes() {
+		sb.WriteString(c.Diff)
+	}
+	return sb.String()
+}
+
+// SetBaseDir sets the dir the paths in the diffs are relative to
+func (d *DiffWriter) SetBaseDir(baseDir string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.baseDir = baseDir
+}
+
+func (d *DiffWriter) relPath(target string) string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	rel, err := filepath.Rel(d.baseDir, target)
+	if err != nil {
+		rel = target
+	}
+	return filepath.ToSlash(rel)
+}
+
+// unifiedDiff returns a git style unified diff between a and b
+func unifiedDiff(from, to string, a, b []byte) (string, error) {
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(a),
+		B:        splitLines(b),
+		FromFile: from,
+		ToFile:   to,
+		Context:  3,
+	})
+	if err != nil {
+		return "", err
+	}
+	name := strings.TrimPrefix(to, "b/")
+	if to == "/dev/null" {
+		name = strings.TrimPrefix(from, "a/")
+	}
+	return fmt.Sprintf("diff --git a/%s b/%s\n%s", name, name, diff), nil
+}
+
+// noNewline marks a last line without line ending, as in git diffs
+const noNewline = "\n\\ No newline at end of file\n"
+
+// splitLines splits the content into lines keeping the line endings.
+// A last line without line ending carries the no newline marker,
+// so it differs from the same line with a line ending.
+func splitLines(content []byte) []string {
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		return lines[:len(lines)-1]
+	}
+	lines[len(lines)-1] += noNewline
+	return lines
+}
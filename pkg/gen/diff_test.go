@@ -0,0 +1,66 @@
+package gen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffWriter(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "same.txt"), []byte("same\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "changed.txt"), []byte("a\nb\nc\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "stale.txt"), []byte("stale\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".hidden"), []byte("hidden\n"), 0644))
+
+	d := NewDiffWriter(dir)
+	require.NoError(t, d.Write([]byte("same\n"), filepath.Join(dir, "same.txt")))
+	require.NoError(t, d.Write([]byte("a\nB\nc\n"), filepath.Join(dir, "changed.txt")))
+	require.NoError(t, d.Write([]byte("new\n"), filepath.Join(dir, "sub", "new.txt")))
+	require.NoError(t, d.DetectRemoved(dir))
+
+	require.True(t, d.IsStale())
+	changes := d.Changes()
+	require.Len(t, changes, 3)
+	require.Equal(t, "changed.txt", changes[0].Target)
+	require.Equal(t, ChangeModified, changes[0].Status)
+	require.Contains(t, changes[0].Diff, "--- a/changed.txt\n+++ b/changed.txt\n")
+	require.Contains(t, changes[0].Diff, "-b\n+B\n")
+	require.Equal(t, "stale.txt", changes[1].Target)
+	require.Equal(t, ChangeRemoved, changes[1].Status)
+	require.Contains(t, changes[1].Diff, "+++ /dev/null\n")
+	require.Equal(t, "sub/new.txt", changes[2].Target)
+	require.Equal(t, ChangeAdded, changes[2].Status)
+	require.Contains(t, changes[2].Diff, "--- /dev/null\n+++ b/sub/new.txt\n")
+	require.Contains(t, changes[2].Diff, "+new\n")
+	// nothing is written
+	require.NoFileExists(t, filepath.Join(dir, "sub", "new.txt"))
+}
+
+func TestDiffWriterNoNewline(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a\nb"), 0644))
+	d := NewDiffWriter(dir)
+	require.NoError(t, d.Write([]byte("a\nb\n"), filepath.Join(dir, "a.txt")))
+	require.True(t, d.IsStale())
+	changes := d.Changes()
+	require.Len(t, changes, 1)
+	require.Contains(t, changes[0].Diff, "-b\n\\ No newline at end of file\n+b\n")
+}
+
+func TestDiffWriterUpToDate(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "same.txt"), []byte("same"), 0644))
+	d := NewDiffWriter(dir)
+	isSame, err := d.Compare([]byte("same"), filepath.Join(dir, "same.txt"))
+	require.NoError(t, err)
+	require.True(t, isSame)
+	require.NoError(t, d.DetectRemoved(dir))
+	require.False(t, d.IsStale())
+	require.Empty(t, d.Patch())
+}
@@ -0,0 +1,54 @@
+package gen
+
+import (
+	"errors"
+
+	"github.com/apigear-io/cli/pkg/cfg"
+	"github.com/apigear-io/cli/pkg/gen/filters"
+	"github.com/apigear-io/cli/pkg/model"
+	"github.com/apigear-io/cli/pkg/spec"
+)
+
+// Engine returns the running engine, which is checked against the engines of the rules
+func Engine() spec.EngineInfo {
+	return spec.EngineInfo{
+		Version:  cfg.GetBuildInfo("cli").Version,
+		Filters:  filters.Packs,
+		Features: model.Features,
+	}
+}
+
+// CheckEngine checks the engine requirements of the rules using the engine policy.
+// With the warn policy unmet requirements are logged and returned as warnings,
+// with the error policy they are returned as error.
+// An empty policy uses the configured engine policy.
+func CheckEngine(rules *spec.RulesDoc, engine spec.EngineInfo, policy string) ([]string, error) {
+	if policy == "" {
+		policy = cfg.EnginePolicy()
+	}
+	if err := spec.ValidateEnginePolicy(policy); err != nil {
+		return nil, err
+	}
+	errs := rules.CheckEngine(engine)
+	if len(errs) == 0 {
+		return nil, nil
+	}
+	if policy == spec.EnginePolicyError {
+		return nil, errors.Join(errs...)
+	}
+	warnings := make([]string, 0, len(errs))
+	for _, err := range errs {
+		log.Warn().Msg(err.Error())
+		warnings = append(warnings, err.Error())
+	}
+	return warnings, nil
+}
+
+// CheckTemplateEngine checks the engine requirements of the rules of a template dir
+func CheckTemplateEngine(templateDir string, policy string) ([]string, error) {
+	composed, err := ComposeRules(templateDir, nil)
+	if err != nil {
+		return nil, err
+	}
+	return CheckEngine(composed.Doc, Engine(), policy)
+}
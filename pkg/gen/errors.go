@@ -0,0 +1,122 @@
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/apigear-io/cli/pkg/model"
+	"github.com/apigear-io/cli/pkg/spec"
+)
+
+// templateErrorRe matches template errors, e.g. "template: name.tpl:3:5: message"
+var templateErrorRe = regexp.MustCompile(`^template: (.+?):(\d+):(?:(\d+):)? (.*)$`)
+
+// SplitTemplateError splits a text/template error into the template name,
+// line, column and the plain message. Errors without a template position
+// return an empty name and the full error message.
+func SplitTemplateError(err error) (string, int, int, string) {
+	msg := err.Error()
+	m := templateErrorRe.FindStringSubmatch(msg)
+	if m == nil {
+		return "", 0, 0, msg
+	}
+	line, _ := strconv.Atoi(m[2])
+	col, _ := strconv.Atoi(m[3])
+	return m[1], line, col, m[4]
+}
+
+// RenderError is a document failure enriched with the model context
+// it was rendered for.
+type RenderError struct {
+	// Feature is the name of the feature containing the document rule
+	Feature string
+	// Match is the scope type of the document rule
+	Match spec.ScopeType
+	// Symbol is the fully qualified name of the rendered symbol
+	Symbol string
+	// Source is the document rule source
+	Source string
+	// Target is the document target
+	Target string
+	// Template is the template the error occurred in, if known
+	Template string
+	// Line and Column are the position inside the template, if known
+	Line   int
+	Column int
+	// Err is the underlying error
+	Err error
+	msg string
+}
+
+// newRenderError wraps the error of a document rule with the model context.
+// The position of template execution errors is extracted from the error.
+func newRenderError(feature string, match spec.ScopeType, ctx any, source, target string, err error) *RenderError {
+	name, line, col, msg := "", 0, 0, err.Error()
+	var execErr template.ExecError
+	if errors.As(err, &execErr) {
+		name, line, col, msg = SplitTemplateError(execErr)
+	}
+	return &RenderError{
+		Feature:  feature,
+		Match:    match,
+		Symbol:   scopeSymbol(ctx),
+		Source:   source,
+		Target:   target,
+		Template: name,
+		Line:     line,
+		Column:   col,
+		Err:      err,
+		msg:      msg,
+	}
+}
+
+// Error returns the error in the form template:line:column: message (context)
+func (e *RenderError) Error() string {
+	var sb strings.Builder
+	if e.Template != "" {
+		sb.WriteString(e.Template)
+		if e.Line > 0 {
+			fmt.Fprintf(&sb, ":%d", e.Line)
+		}
+		if e.Column > 0 {
+			fmt.Fprintf(&sb, ":%d", e.Column)
+		}
+		sb.WriteString(": ")
+	}
+	sb.WriteString(e.msg)
+	fmt.Fprintf(&sb, " (feature %s, %s %s, document %s -> %s)", e.Feature, e.Match, e.Symbol, e.Source, e.Target)
+	return sb.String()
+}
+
+func (e *RenderError) Unwrap() error {
+	return e.Err
+}
+
+// scopeSymbol returns the fully qualified name of the symbol of a scope context
+func scopeSymbol(ctx any) string {
+	switch c := ctx.(type) {
+	case model.SystemScope:
+		return c.System.Name
+	case model.ModuleScope:
+		return c.Module.Name
+	case model.ExternScope:
+		return c.Module.Name + "." + c.Extern.Name
+	case model.InterfaceScope:
+		return c.Module.Name + "." + c.Interface.Name
+	case model.StructScope:
+		return c.Module.Name + "." + c.Struct.Name
+	case model.EnumScope:
+		return c.Module.Name + "." + c.Enum.Name
+	case model.OperationScope:
+		return c.Module.Name + "." + c.Interface.Name + "." + c.Operation.Name
+	case model.PropertyScope:
+		return c.Module.Name + "." + c.Interface.Name + "." + c.Property.Name
+	case model.SignalScope:
+		return c.Module.Name + "." + c.Interface.Name + "." + c.Signal.Name
+	}
+	return ""
+}
@@ -0,0 +1,45 @@
+package common
+
+import (
+	"strings"
+
+	"github.com/apigear-io/cli/pkg/spec/rkw"
+)
+
+// EscapePrefixed returns the prefixed identifier with reserved words escaped.
+// After an accessor prefix (e.g. "self.", "Foo::" or "this->") only the name
+// is escaped. Otherwise the prefix is part of the identifier (e.g. "m_"),
+// so the whole identifier is escaped.
+func EscapePrefixed(esc *rkw.Escaper, lang rkw.Lang, prefix string, name string) string {
+	if prefix == "" || strings.HasSuffix(prefix, ".") || strings.HasSuffix(prefix, "::") || strings.HasSuffix(prefix, "->") {
+		return prefix + esc.Escape(lang, name)
+	}
+	return esc.Escape(lang, prefix+name)
+}
+
+// The WithEscaper functions bind the escaper to the first argument of a filter
+// generating identifiers, so the filter can be added to a template func map.
+
+func WithEscaper1[A any](esc *rkw.Escaper, fn func(*rkw.Escaper, A) (string, error)) func(A) (string, error) {
+	return func(a A) (string, error) {
+		return fn(esc, a)
+	}
+}
+
+func WithEscaper2[A, B any](esc *rkw.Escaper, fn func(*rkw.Escaper, A, B) (string, error)) func(A, B) (string, error) {
+	return func(a A, b B) (string, error) {
+		return fn(esc, a, b)
+	}
+}
+
+func WithEscaper3[A, B, C any](esc *rkw.Escaper, fn func(*rkw.Escaper, A, B, C) (string, error)) func(A, B, C) (string, error) {
+	return func(a A, b B, c C) (string, error) {
+		return fn(esc, a, b, c)
+	}
+}
+
+func WithEscaper4[A, B, C, D any](esc *rkw.Escaper, fn func(*rkw.Escaper, A, B, C, D) (string, error)) func(A, B, C, D) (string, error) {
+	return func(a A, b B, c C, d D) (string, error) {
+		return fn(esc, a, b, c, d)
+	}
+}
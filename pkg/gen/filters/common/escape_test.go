@@ -0,0 +1,32 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/apigear-io/cli/pkg/spec/rkw"
+)
+
+func TestEscapePrefixed(t *testing.T) {
+	t.Parallel()
+	var tests = []struct {
+		lang   rkw.Lang
+		prefix string
+		name   string
+		want   string
+	}{
+		{rkw.RS, "", "type", "r#type"},
+		{rkw.RS, "self.", "type", "self.r#type"},
+		{rkw.RS, "m_", "type", "m_type"},
+		{rkw.CPP, "this->", "class", "this->class_"},
+		{rkw.CPP, "Foo::", "class", "Foo::class_"},
+		{rkw.CPP, "m_", "class", "m_class"},
+		{rkw.CPP, "cl", "ass", "class_"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := EscapePrefixed(nil, tt.lang, tt.prefix, tt.name); got != tt.want {
+				t.Errorf("EscapePrefixed(%q, %q) = %q, want %q", tt.prefix, tt.name, got, tt.want)
+			}
+		})
+	}
+}
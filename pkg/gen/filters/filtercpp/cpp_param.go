@@ -5,9 +5,11 @@ import (
 
 	"github.com/apigear-io/cli/pkg/gen/filters/common"
 	"github.com/apigear-io/cli/pkg/model"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
 )
 
-func ToParamString(prefix string, schema *model.Schema, name string) (string, error) {
+func ToParamString(esc *rkw.Escaper, prefix string, schema *model.Schema, name string) (string, error) {
+	name = esc.Escape(rkw.CPP, name)
 	if schema.IsArray {
 		inner := schema.InnerSchema()
 		ret, err := ToReturnString(prefix, &inner)
@@ -72,9 +74,9 @@ func ToParamString(prefix string, schema *model.Schema, name string) (string, er
 	return "xxx", fmt.Errorf("cppParam: unknown schema %s", schema.Dump())
 }
 
-func cppParam(prefix string, node *model.TypedNode) (string, error) {
+func cppParam(esc *rkw.Escaper, prefix string, node *model.TypedNode) (string, error) {
 	if node == nil {
 		return "xxx", fmt.Errorf("cppParam node is nil")
 	}
-	return ToParamString(prefix, &node.Schema, node.Name)
+	return ToParamString(esc, prefix, &node.Schema, node.Name)
 }
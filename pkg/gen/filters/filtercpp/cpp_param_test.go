@@ -36,7 +36,7 @@ func TestParam(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := cppParam("", prop)
+				r, err := cppParam(nil, "", prop)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -65,7 +65,7 @@ func TestParamSymbols(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := cppParam("", prop)
+				r, err := cppParam(nil, "", prop)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -109,7 +109,7 @@ func TestParamPrefix(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := cppParam("MyPrefix::", prop)
+				r, err := cppParam(nil, "MyPrefix::", prop)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -134,7 +134,7 @@ func TestImportedExternParam(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := cppParam("", prop)
+				r, err := cppParam(nil, "", prop)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -159,7 +159,7 @@ func TestImportedExternParamPrefix(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := cppParam("MyPrefix::", prop)
+				r, err := cppParam(nil, "MyPrefix::", prop)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -5,15 +5,16 @@ import (
 	"strings"
 
 	"github.com/apigear-io/cli/pkg/model"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
 )
 
-func cppParams(prefix string, nodes []*model.TypedNode) (string, error) {
+func cppParams(esc *rkw.Escaper, prefix string, nodes []*model.TypedNode) (string, error) {
 	if nodes == nil {
 		return "xxx", fmt.Errorf("cppParams called with nil nodes")
 	}
 	var params []string
 	for _, p := range nodes {
-		r, err := ToParamString(prefix, &p.Schema, p.Name)
+		r, err := ToParamString(esc, prefix, &p.Schema, p.Name)
 		if err != nil {
 			return "xxx", err
 		}
@@ -40,7 +40,7 @@ func TestParams(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				meth := sys.LookupOperation(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, meth)
-				r, err := cppParams("", meth.Params)
+				r, err := cppParams(nil, "", meth.Params)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -69,7 +69,7 @@ func TestParamsSymbols(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				op := sys.LookupOperation(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, op)
-				r, err := cppParams("", op.Params)
+				r, err := cppParams(nil, "", op.Params)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -99,7 +99,7 @@ func TestParamsMultiple(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				op := sys.LookupOperation(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, op)
-				r, err := cppParams("", op.Params)
+				r, err := cppParams(nil, "", op.Params)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -4,15 +4,16 @@ import (
 	"fmt"
 
 	"github.com/apigear-io/cli/pkg/model"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
 )
 
-func ToVarString(node *model.TypedNode) (string, error) {
+func ToVarString(esc *rkw.Escaper, node *model.TypedNode) (string, error) {
 	if node == nil {
 		return "xxx", fmt.Errorf("ToVarString node is nil")
 	}
-	return node.Name, nil
+	return esc.Escape(rkw.CPP, node.Name), nil
 }
 
-func cppVar(node *model.TypedNode) (string, error) {
-	return ToVarString(node)
+func cppVar(esc *rkw.Escaper, node *model.TypedNode) (string, error) {
+	return ToVarString(esc, node)
 }
@@ -39,7 +39,7 @@ func TestVar(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := cppVar(prop)
+				r, err := cppVar(nil, prop)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -68,7 +68,7 @@ func TestVarSymbols(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := cppVar(prop)
+				r, err := cppVar(nil, prop)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
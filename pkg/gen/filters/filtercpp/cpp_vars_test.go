@@ -36,7 +36,7 @@ func TestVars(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				meth := sys.LookupOperation(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, meth)
-				r, err := cppVars(meth.Params)
+				r, err := cppVars(nil, meth.Params)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -66,7 +66,7 @@ func TestVarsSymbols(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupOperation(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := cppVars(prop.Params)
+				r, err := cppVars(nil, prop.Params)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -96,7 +96,7 @@ func TestVarsMultiple(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupOperation(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := cppVars(prop.Params)
+				r, err := cppVars(nil, prop.Params)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
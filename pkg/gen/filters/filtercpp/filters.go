@@ -2,20 +2,23 @@ package filtercpp
 
 import (
 	"text/template"
+
+	"github.com/apigear-io/cli/pkg/gen/filters/common"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
 )
 
 // PopulateFuncMap fills the given FuncMap with the functions from this package.
-func PopulateFuncMap(fm template.FuncMap) {
+func PopulateFuncMap(fm template.FuncMap, esc *rkw.Escaper) {
 	fm["cppNs"] = ns
 	fm["cppNsOpen"] = nsOpen
 	fm["cppNsClose"] = nsClose
 	fm["cppReturn"] = cppReturn
 	fm["cppDefault"] = cppDefault
-	fm["cppParam"] = cppParam
-	fm["cppParams"] = cppParams
+	fm["cppParam"] = common.WithEscaper2(esc, cppParam)
+	fm["cppParams"] = common.WithEscaper2(esc, cppParams)
 	fm["cppGpl"] = cppGpl
-	fm["cppVar"] = cppVar
-	fm["cppVars"] = cppVars
+	fm["cppVar"] = common.WithEscaper1(esc, cppVar)
+	fm["cppVars"] = common.WithEscaper1(esc, cppVars)
 	fm["cppType"] = cppType
 	fm["cppTypeRef"] = cppTypeRef
 	fm["cppExtern"] = cppExtern
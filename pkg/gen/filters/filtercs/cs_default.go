@@ -0,0 +1,72 @@
+package filtercs
+
+import (
+	"fmt"
+
+	"github.com/apigear-io/cli/pkg/gen/filters/common"
+	"github.com/apigear-io/cli/pkg/model"
+)
+
+func ToDefaultString(schema *model.Schema, prefix string) (string, error) {
+	if schema == nil {
+		return "xxx", fmt.Errorf("ToDefaultString schema is nil")
+	}
+	if schema.IsArray {
+		inner := schema.InnerSchema()
+		ret, err := ToReturnString(prefix, &inner)
+		if err != nil {
+			return "xxx", fmt.Errorf("csDefault inner value error: %s", err)
+		}
+		return fmt.Sprintf("new %s[]{}", ret), nil
+	}
+	var text string
+	switch schema.KindType {
+	case model.TypeString:
+		text = "string.Empty"
+	case model.TypeInt:
+		text = "0"
+	case model.TypeInt32:
+		text = "0"
+	case model.TypeInt64:
+		text = "0L"
+	case model.TypeFloat:
+		text = "0.0f"
+	case model.TypeFloat32:
+		text = "0.0f"
+	case model.TypeFloat64:
+		text = "0.0"
+	case model.TypeBool:
+		text = "false"
+	case model.TypeBytes:
+		text = "new byte[]{}"
+	case model.TypeAny:
+		text = "null"
+	case model.TypeEnum:
+		symbol := schema.GetEnum()
+		member := symbol.Members[0]
+		text = fmt.Sprintf("%s%s.%s", prefix, symbol.Name, common.CamelTitleCase(member.Name))
+	case model.TypeStruct:
+		symbol := schema.GetStruct()
+		text = fmt.Sprintf("new %s%s()", prefix, symbol.Name)
+	case model.TypeExtern:
+		xe := parseCsExtern(schema)
+		if xe.Default != "" {
+			text = xe.Default
+		} else {
+			text = fmt.Sprintf("new %s()", xe.Name)
+		}
+	case model.TypeInterface:
+		// interfaces can not be instantiated
+		text = "null"
+	default:
+		return "xxx", fmt.Errorf("csDefault unknown schema %s", schema.Dump())
+	}
+	return text, nil
+}
+
+func csDefault(prefix string, node *model.TypedNode) (string, error) {
+	if node == nil {
+		return "xxx", fmt.Errorf("csDefault node is nil")
+	}
+	return ToDefaultString(&node.Schema, prefix)
+}
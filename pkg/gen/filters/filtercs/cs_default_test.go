@@ -0,0 +1,111 @@
+package filtercs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultFromIdl(t *testing.T) {
+	t.Parallel()
+	syss := loadTestSystems(t)
+	var propTests = []struct {
+		mn string
+		in string
+		pn string
+		rt string
+	}{
+		{"test", "Test1", "propBool", "false"},
+		{"test", "Test1", "propInt", "0"},
+		{"test", "Test1", "propInt32", "0"},
+		{"test", "Test1", "propInt64", "0L"},
+		{"test", "Test1", "propFloat", "0.0f"},
+		{"test", "Test1", "propFloat32", "0.0f"},
+		{"test", "Test1", "propFloat64", "0.0"},
+		{"test", "Test1", "propString", "string.Empty"},
+		{"test", "Test1", "propBytes", "new byte[]{}"},
+		{"test", "Test1", "propAny", "null"},
+		{"test", "Test1", "propBoolArray", "new bool[]{}"},
+		{"test", "Test1", "propIntArray", "new int[]{}"},
+		{"test", "Test1", "propInt32Array", "new int[]{}"},
+		{"test", "Test1", "propInt64Array", "new long[]{}"},
+		{"test", "Test1", "propFloatArray", "new float[]{}"},
+		{"test", "Test1", "propFloat32Array", "new float[]{}"},
+		{"test", "Test1", "propFloat64Array", "new double[]{}"},
+		{"test", "Test1", "propStringArray", "new string[]{}"},
+		{"test", "Test1", "propBytesArray", "new byte[][]{}"},
+		{"test", "Test1", "propAnyArray", "new object[]{}"},
+	}
+	for _, sys := range syss {
+		for _, tt := range propTests {
+			t.Run(tt.pn, func(t *testing.T) {
+				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
+				assert.NotNil(t, prop)
+				r, err := csDefault("", prop)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.rt, r)
+			})
+		}
+	}
+}
+
+func TestDefaultSymbolsFromIdl(t *testing.T) {
+	t.Parallel()
+	syss := loadTestSystems(t)
+	var propTests = []struct {
+		mn  string
+		in  string
+		pn  string
+		val string
+	}{
+		{"test", "Test2", "propEnum", "Enum1.Default"},
+		{"test", "Test2", "propStruct", "new Struct1()"},
+		{"test", "Test2", "propInterface", "null"},
+		{"test", "Test2", "propEnumArray", "new Enum1[]{}"},
+		{"test", "Test2", "propStructArray", "new Struct1[]{}"},
+		{"test", "Test2", "propInterfaceArray", "new Interface1[]{}"},
+	}
+	for _, sys := range syss {
+		for _, tt := range propTests {
+			t.Run(tt.pn, func(t *testing.T) {
+				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
+				assert.NotNil(t, prop)
+				r, err := csDefault("", prop)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.val, r)
+			})
+		}
+	}
+}
+
+func TestDefaultWithErrors(t *testing.T) {
+	t.Parallel()
+	s, err := csDefault("", nil)
+	assert.Error(t, err)
+	assert.Equal(t, "xxx", s)
+}
+
+func TestExternDefault(t *testing.T) {
+	syss := loadExternSystems(t)
+	var propTests = []struct {
+		mn string
+		in string
+		pn string
+		rt string
+	}{
+		{"demo", "Iface1", "prop1", "new XType1()"},
+		{"demo", "Iface1", "prop2", "new XType2()"},
+		{"demo", "Iface1", "prop3", "Demo.X.XType3Factory.Create()"},
+	}
+	for _, sys := range syss {
+		for _, tt := range propTests {
+			t.Run(tt.pn, func(t *testing.T) {
+				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
+				assert.NotNil(t, prop)
+				r, err := csDefault("", prop)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.rt, r)
+			})
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package filtercs
+
+import (
+	"fmt"
+
+	"github.com/apigear-io/cli/pkg/model"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
+)
+
+func ToParamString(esc *rkw.Escaper, prefix string, schema *model.Schema, name string) (string, error) {
+	if schema == nil {
+		return "xxx", fmt.Errorf("ToParamString schema is nil")
+	}
+	name = esc.Escape(rkw.CS, name)
+	if schema.KindType == model.TypeVoid {
+		return "xxx", fmt.Errorf("csParam void is not a parameter type")
+	}
+	ret, err := ToReturnString(prefix, schema)
+	if err != nil {
+		return "xxx", fmt.Errorf("csParam type error: %s", err)
+	}
+	return fmt.Sprintf("%s %s", ret, name), nil
+}
+
+func csParam(esc *rkw.Escaper, prefix string, node *model.TypedNode) (string, error) {
+	if node == nil {
+		return "xxx", fmt.Errorf("csParam node is nil")
+	}
+	return ToParamString(esc, prefix, &node.Schema, node.Name)
+}
@@ -0,0 +1,24 @@
+package filtercs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apigear-io/cli/pkg/model"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
+)
+
+func csParams(esc *rkw.Escaper, prefix string, nodes []*model.TypedNode) (string, error) {
+	if nodes == nil {
+		return "xxx", fmt.Errorf("csParams called with nil nodes")
+	}
+	var params []string
+	for _, p := range nodes {
+		r, err := ToParamString(esc, prefix, &p.Schema, p.Name)
+		if err != nil {
+			return "xxx", err
+		}
+		params = append(params, r)
+	}
+	return strings.Join(params, ", "), nil
+}
@@ -0,0 +1,134 @@
+package filtercs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReturn(t *testing.T) {
+	t.Parallel()
+	syss := loadTestSystems(t)
+	var propTests = []struct {
+		mn string
+		in string
+		pn string
+		rt string
+	}{
+		{"test", "Test1", "propVoid", "void"},
+		{"test", "Test1", "propBool", "bool"},
+		{"test", "Test1", "propInt", "int"},
+		{"test", "Test1", "propInt32", "int"},
+		{"test", "Test1", "propInt64", "long"},
+		{"test", "Test1", "propFloat", "float"},
+		{"test", "Test1", "propFloat32", "float"},
+		{"test", "Test1", "propFloat64", "double"},
+		{"test", "Test1", "propString", "string"},
+		{"test", "Test1", "propBytes", "byte[]"},
+		{"test", "Test1", "propAny", "object"},
+		{"test", "Test1", "propBoolArray", "bool[]"},
+		{"test", "Test1", "propIntArray", "int[]"},
+		{"test", "Test1", "propInt32Array", "int[]"},
+		{"test", "Test1", "propInt64Array", "long[]"},
+		{"test", "Test1", "propFloatArray", "float[]"},
+		{"test", "Test1", "propFloat32Array", "float[]"},
+		{"test", "Test1", "propFloat64Array", "double[]"},
+		{"test", "Test1", "propStringArray", "string[]"},
+		{"test", "Test1", "propBytesArray", "byte[][]"},
+		{"test", "Test1", "propAnyArray", "object[]"},
+	}
+	for _, sys := range syss {
+		for _, tt := range propTests {
+			t.Run(tt.pn, func(t *testing.T) {
+				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
+				assert.NotNil(t, prop)
+				r, err := csReturn("", prop)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.rt, r)
+			})
+		}
+	}
+}
+
+func TestOperationReturn(t *testing.T) {
+	t.Parallel()
+	syss := loadTestSystems(t)
+	var propTests = []struct {
+		mn string
+		in string
+		pn string
+		rt string
+	}{
+		{"test", "Test3", "opVoid", "void"},
+		{"test", "Test3", "opBool", "bool"},
+		{"test", "Test3", "opInt", "int"},
+		{"test", "Test3", "opInt32", "int"},
+		{"test", "Test3", "opInt64", "long"},
+		{"test", "Test3", "opFloat", "float"},
+		{"test", "Test3", "opFloat32", "float"},
+		{"test", "Test3", "opFloat64", "double"},
+		{"test", "Test3", "opString", "string"},
+		{"test", "Test3", "opBoolArray", "bool[]"},
+		{"test", "Test3", "opIntArray", "int[]"},
+		{"test", "Test3", "opInt32Array", "int[]"},
+		{"test", "Test3", "opInt64Array", "long[]"},
+		{"test", "Test3", "opFloatArray", "float[]"},
+		{"test", "Test3", "opFloat32Array", "float[]"},
+		{"test", "Test3", "opFloat64Array", "double[]"},
+		{"test", "Test3", "opStringArray", "string[]"},
+		{"test", "Test4", "opEnum", "Enum1"},
+		{"test", "Test4", "opStruct", "Struct1"},
+		{"test", "Test4", "opInterface", "Interface1"},
+		{"test", "Test4", "opEnumArray", "Enum1[]"},
+		{"test", "Test4", "opStructArray", "Struct1[]"},
+		{"test", "Test4", "opInterfaceArray", "Interface1[]"},
+	}
+	for _, sys := range syss {
+		for _, tt := range propTests {
+			t.Run(tt.pn, func(t *testing.T) {
+				op := sys.LookupOperation(tt.mn, tt.in, tt.pn)
+				assert.NotNil(t, op)
+				r, err := csReturn("", op.Return)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.rt, r)
+			})
+		}
+	}
+}
+
+func TestReturnWithPrefix(t *testing.T) {
+	t.Parallel()
+	syss := loadTestSystems(t)
+	for _, sys := range syss {
+		prop := sys.LookupProperty("test", "Test2", "propStructArray")
+		assert.NotNil(t, prop)
+		r, err := csReturn("Api.", prop)
+		assert.NoError(t, err)
+		assert.Equal(t, "Api.Struct1[]", r)
+	}
+}
+
+func TestExternReturn(t *testing.T) {
+	syss := loadExternSystems(t)
+	var propTests = []struct {
+		mn string
+		in string
+		pn string
+		rt string
+	}{
+		{"demo", "Iface1", "prop1", "XType1"},
+		{"demo", "Iface1", "prop2", "XType2"},
+		{"demo", "Iface1", "prop3", "XType3A"},
+	}
+	for _, sys := range syss {
+		for _, tt := range propTests {
+			t.Run(tt.pn, func(t *testing.T) {
+				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
+				assert.NotNil(t, prop)
+				r, err := csReturn("", prop)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.rt, r)
+			})
+		}
+	}
+}
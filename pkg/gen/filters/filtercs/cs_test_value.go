@@ -0,0 +1,66 @@
+package filtercs
+
+import (
+	"fmt"
+
+	"github.com/apigear-io/cli/pkg/gen/filters/common"
+	"github.com/apigear-io/cli/pkg/model"
+)
+
+// ToTestValueString returns the test value string for a given schema.
+// We intentionally ignore arrays in order to return the test value of the inner type.
+func ToTestValueString(prefix string, schema *model.Schema) (string, error) {
+	if schema == nil {
+		return "", fmt.Errorf("csTestValue schema is nil")
+	}
+	var text string
+	switch schema.KindType {
+	case model.TypeString:
+		text = "\"xyz\""
+	case model.TypeInt, model.TypeInt32:
+		text = "1"
+	case model.TypeInt64:
+		text = "1L"
+	case model.TypeFloat, model.TypeFloat32:
+		text = "1.1f"
+	case model.TypeFloat64:
+		text = "1.1"
+	case model.TypeBool:
+		text = "true"
+	case model.TypeBytes:
+		text = "new byte[]{ 0x01 }"
+	case model.TypeAny:
+		text = "new object()"
+	case model.TypeVoid:
+		text = ""
+	case model.TypeEnum:
+		symbol := schema.GetEnum()
+		member := symbol.Members[0]
+		if len(symbol.Members) > 1 {
+			member = symbol.Members[1]
+		}
+		text = fmt.Sprintf("%s%s.%s", prefix, symbol.Name, common.CamelTitleCase(member.Name))
+	case model.TypeStruct:
+		symbol := schema.GetStruct()
+		text = fmt.Sprintf("new %s%s()", prefix, symbol.Name)
+	case model.TypeExtern:
+		xe := parseCsExtern(schema)
+		if xe.Default != "" {
+			text = xe.Default
+		} else {
+			text = fmt.Sprintf("new %s()", xe.Name)
+		}
+	case model.TypeInterface:
+		text = "null"
+	default:
+		return "xxx", fmt.Errorf("csTestValue unknown schema %s", schema.Dump())
+	}
+	return text, nil
+}
+
+func csTestValue(prefix string, node *model.TypedNode) (string, error) {
+	if node == nil {
+		return "xxx", fmt.Errorf("csTestValue node is nil")
+	}
+	return ToTestValueString(prefix, &node.Schema)
+}
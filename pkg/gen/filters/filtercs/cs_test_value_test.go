@@ -0,0 +1,81 @@
+package filtercs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTestValue(t *testing.T) {
+	t.Parallel()
+	syss := loadTestSystems(t)
+	var propTests = []struct {
+		mn string
+		in string
+		pn string
+		rt string
+	}{
+		{"test", "Test1", "propBool", "true"},
+		{"test", "Test1", "propInt", "1"},
+		{"test", "Test1", "propInt32", "1"},
+		{"test", "Test1", "propInt64", "1L"},
+		{"test", "Test1", "propFloat", "1.1f"},
+		{"test", "Test1", "propFloat32", "1.1f"},
+		{"test", "Test1", "propFloat64", "1.1"},
+		{"test", "Test1", "propString", "\"xyz\""},
+		{"test", "Test1", "propBytes", "new byte[]{ 0x01 }"},
+		{"test", "Test1", "propAny", "new object()"},
+		{"test", "Test1", "propIntArray", "1"},
+		{"test", "Test2", "propEnum", "Enum1.NotDefault"},
+		{"test", "Test2", "propStruct", "new Struct1()"},
+		{"test", "Test2", "propInterface", "null"},
+	}
+	for _, sys := range syss {
+		for _, tt := range propTests {
+			t.Run(tt.pn, func(t *testing.T) {
+				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
+				assert.NotNil(t, prop)
+				r, err := csTestValue("", prop)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.rt, r)
+			})
+		}
+	}
+}
+
+func TestExternTestValue(t *testing.T) {
+	syss := loadExternSystems(t)
+	var propTests = []struct {
+		mn string
+		in string
+		pn string
+		rt string
+	}{
+		{"demo", "Iface1", "prop1", "new XType1()"},
+		{"demo", "Iface1", "prop3", "Demo.X.XType3Factory.Create()"},
+	}
+	for _, sys := range syss {
+		for _, tt := range propTests {
+			t.Run(tt.pn, func(t *testing.T) {
+				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
+				assert.NotNil(t, prop)
+				r, err := csTestValue("", prop)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.rt, r)
+			})
+		}
+	}
+}
+
+func TestExtern(t *testing.T) {
+	syss := loadExternSystems(t)
+	for _, sys := range syss {
+		xe := sys.LookupExtern("demo", "XType3")
+		assert.NotNil(t, xe)
+		assert.Equal(t, CsExtern{
+			Namespace: "Demo.X",
+			Name:      "XType3A",
+			Default:   "Demo.X.XType3Factory.Create()",
+		}, csExtern(xe))
+	}
+}
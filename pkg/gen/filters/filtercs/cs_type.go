@@ -0,0 +1,3 @@
+package filtercs
+
+var csType = csReturn
@@ -0,0 +1,19 @@
+package filtercs
+
+import (
+	"fmt"
+
+	"github.com/apigear-io/cli/pkg/model"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
+)
+
+func ToVarString(esc *rkw.Escaper, node *model.TypedNode) (string, error) {
+	if node == nil {
+		return "xxx", fmt.Errorf("ToVarString node is nil")
+	}
+	return esc.Escape(rkw.CS, node.Name), nil
+}
+
+func csVar(esc *rkw.Escaper, node *model.TypedNode) (string, error) {
+	return ToVarString(esc, node)
+}
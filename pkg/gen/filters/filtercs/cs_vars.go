@@ -0,0 +1,24 @@
+package filtercs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apigear-io/cli/pkg/model"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
+)
+
+func csVars(esc *rkw.Escaper, nodes []*model.TypedNode) (string, error) {
+	if nodes == nil {
+		return "xxx", fmt.Errorf("csVars called with nil nodes")
+	}
+	names := make([]string, len(nodes))
+	for idx, p := range nodes {
+		name, err := ToVarString(esc, p)
+		if err != nil {
+			return "xxx", err
+		}
+		names[idx] = name
+	}
+	return strings.Join(names, ", "), nil
+}
@@ -0,0 +1,28 @@
+package filtercs
+
+import "github.com/apigear-io/cli/pkg/model"
+
+type CsExtern struct {
+	Namespace string
+	Name      string
+	Default   string
+}
+
+func parseCsExtern(schema *model.Schema) CsExtern {
+	xe := schema.GetExtern()
+	return csExtern(xe)
+}
+
+func csExtern(xe *model.Extern) CsExtern {
+	ns := xe.Meta.GetString("cs.namespace")
+	name := xe.Meta.GetString("cs.name")
+	dft := xe.Meta.GetString("cs.default")
+	if name == "" {
+		name = xe.Name
+	}
+	return CsExtern{
+		Namespace: ns,
+		Name:      name,
+		Default:   dft,
+	}
+}
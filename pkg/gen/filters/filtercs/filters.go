@@ -0,0 +1,21 @@
+package filtercs
+
+import (
+	"text/template"
+
+	"github.com/apigear-io/cli/pkg/gen/filters/common"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
+)
+
+// PopulateFuncMap fills the given FuncMap with the functions from this package.
+func PopulateFuncMap(fm template.FuncMap, esc *rkw.Escaper) {
+	fm["csDefault"] = csDefault
+	fm["csReturn"] = csReturn
+	fm["csParam"] = common.WithEscaper2(esc, csParam)
+	fm["csParams"] = common.WithEscaper2(esc, csParams)
+	fm["csVar"] = common.WithEscaper1(esc, csVar)
+	fm["csVars"] = common.WithEscaper1(esc, csVars)
+	fm["csType"] = csType
+	fm["csExtern"] = csExtern
+	fm["csTestValue"] = csTestValue
+}
@@ -50,7 +50,7 @@ func TestExternFuncArgsReturn(t *testing.T) {
 				op := sys.LookupOperation(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, op)
 				assert.Len(t, op.Params, 1)
-				r, err := goParam("", op.Params[0])
+				r, err := goParam(nil, "", op.Params[0])
 				assert.NoError(t, err)
 				assert.Equal(t, tt.fa, r)
 				r, err = goReturn("", op.Return)
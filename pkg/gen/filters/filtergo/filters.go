@@ -2,17 +2,20 @@ package filtergo
 
 import (
 	"text/template"
+
+	"github.com/apigear-io/cli/pkg/gen/filters/common"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
 )
 
 // PopulateFuncMap fills the given FuncMap with the functions from this package.
-func PopulateFuncMap(fm template.FuncMap) {
+func PopulateFuncMap(fm template.FuncMap, esc *rkw.Escaper) {
 	fm["goReturn"] = goReturn
 	fm["goDefault"] = goDefault
-	fm["goParam"] = goParam
-	fm["goParams"] = goParams
+	fm["goParam"] = common.WithEscaper2(esc, goParam)
+	fm["goParams"] = common.WithEscaper2(esc, goParams)
 	fm["goType"] = goType
-	fm["goVar"] = goVar
-	fm["goVars"] = goVars
+	fm["goVar"] = common.WithEscaper1(esc, goVar)
+	fm["goVars"] = common.WithEscaper1(esc, goVars)
 	fm["goPublicVar"] = goPublicVar
 	fm["goPublicVars"] = goPublicVars
 	fm["goDoc"] = goDoc
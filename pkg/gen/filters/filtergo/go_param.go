@@ -4,12 +4,14 @@ import (
 	"fmt"
 
 	"github.com/apigear-io/cli/pkg/model"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
 )
 
-func ToParamString(prefix string, schema *model.Schema, name string) (string, error) {
+func ToParamString(esc *rkw.Escaper, prefix string, schema *model.Schema, name string) (string, error) {
 	if schema == nil {
 		return "xxx", fmt.Errorf("ToParamString schema is nil")
 	}
+	name = esc.Escape(rkw.GO, name)
 	if schema.IsImported() {
 		prefix = fmt.Sprintf("%s.", schema.ShortImportName())
 	}
@@ -75,9 +77,9 @@ func ToParamString(prefix string, schema *model.Schema, name string) (string, er
 	return "xxx", fmt.Errorf("goParam: unknown schema %s", schema.Dump())
 }
 
-func goParam(prefix string, node *model.TypedNode) (string, error) {
+func goParam(esc *rkw.Escaper, prefix string, node *model.TypedNode) (string, error) {
 	if node == nil {
 		return "xxx", fmt.Errorf("goParam called with nil node")
 	}
-	return ToParamString(prefix, &node.Schema, node.Name)
+	return ToParamString(esc, prefix, &node.Schema, node.Name)
 }
@@ -38,7 +38,7 @@ func TestParam(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := goParam("", prop)
+				r, err := goParam(nil, "", prop)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -66,7 +66,7 @@ func TestParamSymbols(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := goParam("", prop)
+				r, err := goParam(nil, "", prop)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -75,7 +75,7 @@ func TestParamSymbols(t *testing.T) {
 }
 
 func TestParamWithErrors(t *testing.T) {
-	s, err := goParam("", nil)
+	s, err := goParam(nil, "", nil)
 	assert.Error(t, err)
 	assert.Equal(t, "xxx", s)
 }
@@ -97,7 +97,7 @@ func TestExternParam(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				op := sys.LookupOperation(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, op)
-				r, err := goParams("", op.Params)
+				r, err := goParams(nil, "", op.Params)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
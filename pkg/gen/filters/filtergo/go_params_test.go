@@ -44,7 +44,7 @@ func TestParams(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				meth := sys.LookupOperation(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, meth)
-				r, err := goParams("", meth.Params)
+				r, err := goParams(nil, "", meth.Params)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -72,7 +72,7 @@ func TestParamsSymbols(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupOperation(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := goParams("", prop.Params)
+				r, err := goParams(nil, "", prop.Params)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -103,7 +103,7 @@ func TestParamsMultiple(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupOperation(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := goParams("", prop.Params)
+				r, err := goParams(nil, "", prop.Params)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -112,7 +112,7 @@ func TestParamsMultiple(t *testing.T) {
 }
 
 func TestParamsWithErrors(t *testing.T) {
-	s, err := goParams("", nil)
+	s, err := goParams(nil, "", nil)
 	assert.Error(t, err)
 	assert.Equal(t, "xxx", s)
 }
@@ -4,14 +4,15 @@ import (
 	"fmt"
 
 	"github.com/apigear-io/cli/pkg/model"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
 	"github.com/ettle/strcase"
 )
 
-func ToVarString(node *model.TypedNode) (string, error) {
+func ToVarString(esc *rkw.Escaper, node *model.TypedNode) (string, error) {
 	if node == nil {
 		return "xxx", fmt.Errorf("ToVarString node is nil")
 	}
-	return node.Name, nil
+	return esc.Escape(rkw.GO, node.Name), nil
 }
 
 func ToPublicVarString(node *model.TypedNode) (string, error) {
@@ -21,8 +22,8 @@ func ToPublicVarString(node *model.TypedNode) (string, error) {
 	return strcase.ToPascal(node.Name), nil
 }
 
-func goVar(node *model.TypedNode) (string, error) {
-	return ToVarString(node)
+func goVar(esc *rkw.Escaper, node *model.TypedNode) (string, error) {
+	return ToVarString(esc, node)
 }
 
 func goPublicVar(node *model.TypedNode) (string, error) {
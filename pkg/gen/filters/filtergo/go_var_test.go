@@ -47,7 +47,7 @@ func TestVar(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := goVar(prop)
+				r, err := goVar(nil, prop)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -75,7 +75,7 @@ func TestVarSymbols(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := goVar(prop)
+				r, err := goVar(nil, prop)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
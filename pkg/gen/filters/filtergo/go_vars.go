@@ -5,15 +5,16 @@ import (
 	"strings"
 
 	"github.com/apigear-io/cli/pkg/model"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
 )
 
-func goVars(nodes []*model.TypedNode) (string, error) {
+func goVars(esc *rkw.Escaper, nodes []*model.TypedNode) (string, error) {
 	if nodes == nil {
 		return "xxx", fmt.Errorf("goNames called with nil nodes")
 	}
 	names := make([]string, len(nodes))
 	for idx, p := range nodes {
-		name, err := ToVarString(p)
+		name, err := ToVarString(esc, p)
 		if err != nil {
 			return "xxx", err
 		}
@@ -44,7 +44,7 @@ func TestVars(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				meth := sys.LookupOperation(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, meth)
-				r, err := goVars(meth.Params)
+				r, err := goVars(nil, meth.Params)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -73,7 +73,7 @@ func TestVarsSymbols(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupOperation(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := goVars(prop.Params)
+				r, err := goVars(nil, prop.Params)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -102,7 +102,7 @@ func TestVarsMultiple(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupOperation(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := goVars(prop.Params)
+				r, err := goVars(nil, prop.Params)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
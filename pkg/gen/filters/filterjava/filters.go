@@ -1,14 +1,19 @@
 package filterjava
 
-import "text/template"
+import (
+	"text/template"
 
-func PopulateFuncMap(fm template.FuncMap) {
+	"github.com/apigear-io/cli/pkg/gen/filters/common"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
+)
+
+func PopulateFuncMap(fm template.FuncMap, esc *rkw.Escaper) {
 	fm["javaDefault"] = javaDefault
 	fm["javaReturn"] = javaReturn
-	fm["javaParam"] = javaParam
-	fm["javaParams"] = javaParams
-	fm["javaVar"] = javaVar
-	fm["javaVars"] = javaVars
+	fm["javaParam"] = common.WithEscaper2(esc, javaParam)
+	fm["javaParams"] = common.WithEscaper2(esc, javaParams)
+	fm["javaVar"] = common.WithEscaper1(esc, javaVar)
+	fm["javaVars"] = common.WithEscaper1(esc, javaVars)
 	fm["javaType"] = javaType
 	fm["javaExtern"] = javaExtern
 	fm["javaAsyncReturn"] = javaAsyncReturn
@@ -4,9 +4,11 @@ import (
 	"fmt"
 
 	"github.com/apigear-io/cli/pkg/model"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
 )
 
-func ToParamString(prefix string, schema *model.Schema, name string) (string, error) {
+func ToParamString(esc *rkw.Escaper, prefix string, schema *model.Schema, name string) (string, error) {
+	name = esc.Escape(rkw.JAVA, name)
 	if schema.IsArray {
 		inner := schema.InnerSchema()
 		ret, err := ToReturnString(prefix, &inner)
@@ -48,9 +50,9 @@ func ToParamString(prefix string, schema *model.Schema, name string) (string, er
 	return "xxx", fmt.Errorf("javaParam unknown schema %s", schema.Dump())
 }
 
-func javaParam(prefix string, node *model.TypedNode) (string, error) {
+func javaParam(esc *rkw.Escaper, prefix string, node *model.TypedNode) (string, error) {
 	if node == nil {
 		return "xxx", fmt.Errorf("javaParam node is nil")
 	}
-	return ToParamString(prefix, &node.Schema, node.Name)
+	return ToParamString(esc, prefix, &node.Schema, node.Name)
 }
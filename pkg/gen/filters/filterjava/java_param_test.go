@@ -40,7 +40,7 @@ func TestParam(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := javaParam("", prop)
+				r, err := javaParam(nil, "", prop)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -69,7 +69,7 @@ func TestParamSymbols(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := javaParam("", prop)
+				r, err := javaParam(nil, "", prop)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -78,7 +78,7 @@ func TestParamSymbols(t *testing.T) {
 }
 
 func TestParamWithErrors(t *testing.T) {
-	s, err := javaParam("", nil)
+	s, err := javaParam(nil, "", nil)
 	assert.Error(t, err)
 	assert.Equal(t, "xxx", s)
 }
@@ -100,7 +100,7 @@ func TestExternParam(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				op := sys.LookupOperation(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, op)
-				r, err := javaParam("", op.Params[0])
+				r, err := javaParam(nil, "", op.Params[0])
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -40,7 +40,7 @@ func TestParams(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				m := sys.LookupOperation(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, m)
-				r, err := javaParams("", m.Params)
+				r, err := javaParams(nil, "", m.Params)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -40,7 +40,7 @@ func TestVar(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := javaVar(prop)
+				r, err := javaVar(nil, prop)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
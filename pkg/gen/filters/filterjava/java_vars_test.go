@@ -40,7 +40,7 @@ func TestVars(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				op := sys.LookupOperation(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, op)
-				r, err := javaVars(op.Params)
+				r, err := javaVars(nil, op.Params)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
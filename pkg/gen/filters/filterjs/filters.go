@@ -2,15 +2,18 @@ package filterjs
 
 import (
 	"text/template"
+
+	"github.com/apigear-io/cli/pkg/gen/filters/common"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
 )
 
 // PopulateFuncMap fills the given FuncMap with the functions from this package.
-func PopulateFuncMap(fm template.FuncMap) {
+func PopulateFuncMap(fm template.FuncMap, esc *rkw.Escaper) {
 	fm["jsReturn"] = jsReturn
 	fm["jsDefault"] = jsDefault
-	fm["jsParam"] = jsParam
-	fm["jsParams"] = jsParams
-	fm["jsVar"] = jsVar
-	fm["jsVars"] = jsVars
+	fm["jsParam"] = common.WithEscaper2(esc, jsParam)
+	fm["jsParams"] = common.WithEscaper2(esc, jsParams)
+	fm["jsVar"] = common.WithEscaper1(esc, jsVar)
+	fm["jsVars"] = common.WithEscaper1(esc, jsVars)
 	fm["jsType"] = jsType
 }
@@ -4,12 +4,14 @@ import (
 	"fmt"
 
 	"github.com/apigear-io/cli/pkg/model"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
 )
 
-func ToParamString(schema *model.Schema, name string, prefix string) (string, error) {
+func ToParamString(esc *rkw.Escaper, schema *model.Schema, name string, prefix string) (string, error) {
 	if schema == nil {
 		return "xxx", fmt.Errorf("jsParam schema is nil")
 	}
+	name = esc.Escape(rkw.JS, name)
 	if schema.IsArray {
 		return name, nil
 	}
@@ -45,9 +47,9 @@ func ToParamString(schema *model.Schema, name string, prefix string) (string, er
 	}
 }
 
-func jsParam(prefix string, node *model.TypedNode) (string, error) {
+func jsParam(esc *rkw.Escaper, prefix string, node *model.TypedNode) (string, error) {
 	if node == nil {
 		return "xxx", fmt.Errorf("jsParam called with nil node")
 	}
-	return ToParamString(&node.Schema, node.Name, prefix)
+	return ToParamString(esc, &node.Schema, node.Name, prefix)
 }
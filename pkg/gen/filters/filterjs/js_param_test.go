@@ -39,7 +39,7 @@ func TestParam(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := jsParam("", prop)
+				r, err := jsParam(nil, "", prop)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -68,7 +68,7 @@ func TestParamSymbols(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := jsParam("", prop)
+				r, err := jsParam(nil, "", prop)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -4,12 +4,13 @@ import (
 	"strings"
 
 	"github.com/apigear-io/cli/pkg/model"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
 )
 
-func jsParams(prefix string, nodes []*model.TypedNode) (string, error) {
+func jsParams(esc *rkw.Escaper, prefix string, nodes []*model.TypedNode) (string, error) {
 	var params []string
 	for _, n := range nodes {
-		r, err := ToParamString(&n.Schema, n.Name, prefix)
+		r, err := ToParamString(esc, &n.Schema, n.Name, prefix)
 		if err != nil {
 			return "xxx", err
 		}
@@ -40,7 +40,7 @@ func TestParams(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				m := sys.LookupOperation(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, m)
-				r, err := jsParams("", m.Params)
+				r, err := jsParams(nil, "", m.Params)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -69,7 +69,7 @@ func TestParamsSymbols(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				m := sys.LookupOperation(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, m)
-				r, err := jsParams("", m.Params)
+				r, err := jsParams(nil, "", m.Params)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -99,7 +99,7 @@ func TestParamsMultiple(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				m := sys.LookupOperation(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, m)
-				r, err := jsParams("", m.Params)
+				r, err := jsParams(nil, "", m.Params)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -43,7 +43,7 @@ func TestVar(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := jsVar(prop)
+				r, err := jsVar(nil, prop)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -72,7 +72,7 @@ func TestVarSymbols(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := jsVar(prop)
+				r, err := jsVar(nil, prop)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -40,7 +40,7 @@ func TestVars(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				meth := sys.LookupOperation(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, meth)
-				r, err := jsVars(meth.Params)
+				r, err := jsVars(nil, meth.Params)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -70,7 +70,7 @@ func TestVarsSymbols(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupOperation(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := jsVars(prop.Params)
+				r, err := jsVars(nil, prop.Params)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -100,7 +100,7 @@ func TestVarsMultiple(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupOperation(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := jsVars(prop.Params)
+				r, err := jsVars(nil, prop.Params)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -2,17 +2,20 @@ package filterpy
 
 import (
 	"text/template"
+
+	"github.com/apigear-io/cli/pkg/gen/filters/common"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
 )
 
 // PopulateFuncMap fills the given FuncMap with the functions from this package.
-func PopulateFuncMap(fm template.FuncMap) {
+func PopulateFuncMap(fm template.FuncMap, esc *rkw.Escaper) {
 	fm["pyReturn"] = pyReturn
 	fm["pyDefault"] = pyDefault
-	fm["pyParam"] = pyParam
-	fm["pyParams"] = pyParams
-	fm["pyFuncParams"] = pyFuncParams
-	fm["pyVar"] = pyVar
-	fm["pyVars"] = pyVars
+	fm["pyParam"] = common.WithEscaper2(esc, pyParam)
+	fm["pyParams"] = common.WithEscaper2(esc, pyParams)
+	fm["pyFuncParams"] = common.WithEscaper2(esc, pyFuncParams)
+	fm["pyVar"] = common.WithEscaper1(esc, pyVar)
+	fm["pyVars"] = common.WithEscaper1(esc, pyVars)
 	fm["pyType"] = pyType
 	fm["pyExtern"] = pyExtern
 	fm["pyTestValue"] = pyTestValue
@@ -5,13 +5,14 @@ import (
 
 	"github.com/apigear-io/cli/pkg/gen/filters/common"
 	"github.com/apigear-io/cli/pkg/model"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
 )
 
-func ToParamString(schema *model.Schema, name string, prefix string) (string, error) {
+func ToParamString(esc *rkw.Escaper, schema *model.Schema, name string, prefix string) (string, error) {
 	if schema == nil {
 		return "xxx", fmt.Errorf("pyParam schema is nil")
 	}
-	name = common.SnakeCaseLower(name)
+	name = esc.Escape(rkw.PY, common.SnakeCaseLower(name))
 	if schema.IsArray {
 		inner := schema.InnerSchema()
 		innerValue, err := ToReturnString(&inner, prefix)
@@ -75,9 +76,9 @@ func ToParamString(schema *model.Schema, name string, prefix string) (string, er
 	}
 }
 
-func pyParam(prefix string, node *model.TypedNode) (string, error) {
+func pyParam(esc *rkw.Escaper, prefix string, node *model.TypedNode) (string, error) {
 	if node == nil {
 		return "xxx", fmt.Errorf("pyParam called with nil node")
 	}
-	return ToParamString(&node.Schema, node.Name, prefix)
+	return ToParamString(esc, &node.Schema, node.Name, prefix)
 }
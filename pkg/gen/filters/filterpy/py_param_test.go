@@ -40,7 +40,7 @@ func TestParam(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := pyParam("", prop)
+				r, err := pyParam(nil, "", prop)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -69,7 +69,7 @@ func TestParamSymbols(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := pyParam("", prop)
+				r, err := pyParam(nil, "", prop)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -94,7 +94,7 @@ func TestExternParam(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				op := sys.LookupOperation(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, op)
-				r, err := pyParams("", op.Params)
+				r, err := pyParams(nil, "", op.Params)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -122,7 +122,7 @@ func TestParamsExterns(t *testing.T) {
 			t.Run(tt.operation_name, func(t *testing.T) {
 				op := sys.LookupOperation(tt.module_name, tt.interface_name, tt.operation_name)
 				assert.NotNil(t, op)
-				r, err := pyParams("", op.Params)
+				r, err := pyParams(nil, "", op.Params)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.result, r)
 			})
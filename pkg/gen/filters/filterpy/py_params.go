@@ -4,12 +4,13 @@ import (
 	"strings"
 
 	"github.com/apigear-io/cli/pkg/model"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
 )
 
-func pyParams(prefix string, nodes []*model.TypedNode) (string, error) {
+func pyParams(esc *rkw.Escaper, prefix string, nodes []*model.TypedNode) (string, error) {
 	params := []string{"self"}
 	for _, n := range nodes {
-		r, err := ToParamString(&n.Schema, n.Name, prefix)
+		r, err := ToParamString(esc, &n.Schema, n.Name, prefix)
 		if err != nil {
 			return "xxx", err
 		}
@@ -18,10 +19,10 @@ func pyParams(prefix string, nodes []*model.TypedNode) (string, error) {
 	return strings.Join(params, ", "), nil
 }
 
-func pyFuncParams(prefix string, nodes []*model.TypedNode) (string, error) {
+func pyFuncParams(esc *rkw.Escaper, prefix string, nodes []*model.TypedNode) (string, error) {
 	params := []string{}
 	for _, n := range nodes {
-		r, err := ToParamString(&n.Schema, n.Name, prefix)
+		r, err := ToParamString(esc, &n.Schema, n.Name, prefix)
 		if err != nil {
 			return "xxx", err
 		}
@@ -5,15 +5,16 @@ import (
 
 	"github.com/apigear-io/cli/pkg/gen/filters/common"
 	"github.com/apigear-io/cli/pkg/model"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
 )
 
-func ToVarString(node *model.TypedNode) (string, error) {
+func ToVarString(esc *rkw.Escaper, node *model.TypedNode) (string, error) {
 	if node == nil {
 		return "xxx", fmt.Errorf("pyVar node is nil")
 	}
-	return common.SnakeCaseLower(node.Name), nil
+	return esc.Escape(rkw.PY, common.SnakeCaseLower(node.Name)), nil
 }
 
-func pyVar(node *model.TypedNode) (string, error) {
-	return ToVarString(node)
+func pyVar(esc *rkw.Escaper, node *model.TypedNode) (string, error) {
+	return ToVarString(esc, node)
 }
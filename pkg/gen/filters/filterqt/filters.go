@@ -2,16 +2,19 @@ package filterqt
 
 import (
 	"text/template"
+
+	"github.com/apigear-io/cli/pkg/gen/filters/common"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
 )
 
 // PopulateFuncMap fills the given FuncMap with the functions from this package.
-func PopulateFuncMap(fm template.FuncMap) {
+func PopulateFuncMap(fm template.FuncMap, esc *rkw.Escaper) {
 	fm["qtReturn"] = qtReturn
 	fm["qtDefault"] = qtDefault
-	fm["qtParam"] = qtParam
-	fm["qtParams"] = qtParams
-	fm["qtVar"] = qtVar
-	fm["qtVars"] = qtVars
+	fm["qtParam"] = common.WithEscaper2(esc, qtParam)
+	fm["qtParams"] = common.WithEscaper2(esc, qtParams)
+	fm["qtVar"] = common.WithEscaper1(esc, qtVar)
+	fm["qtVars"] = common.WithEscaper1(esc, qtVars)
 	fm["qtType"] = qtType
 	fm["qtNamespace"] = qtNamespace
 	fm["qtExtern"] = qtExtern
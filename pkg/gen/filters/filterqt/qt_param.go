@@ -4,9 +4,11 @@ import (
 	"fmt"
 
 	"github.com/apigear-io/cli/pkg/model"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
 )
 
-func ToParamString(prefix string, schema *model.Schema, name string) (string, error) {
+func ToParamString(esc *rkw.Escaper, prefix string, schema *model.Schema, name string) (string, error) {
+	name = esc.Escape(rkw.QT, name)
 	if schema.IsArray {
 		inner := schema.InnerSchema()
 		ret, err := ToReturnString(prefix, &inner)
@@ -36,7 +38,7 @@ func ToParamString(prefix string, schema *model.Schema, name string) (string, er
 	ex := schema.LookupExtern(schema.Import, schema.Type)
 	if ex != nil {
 		exQt := qtExtern(schema.GetExtern())
-		namespace :=""
+		namespace := ""
 		if exQt.NameSpace != "" {
 			namespace = fmt.Sprintf("%s::", exQt.NameSpace)
 		}
@@ -69,9 +71,9 @@ func ToParamString(prefix string, schema *model.Schema, name string) (string, er
 	return "xxx", fmt.Errorf("qtParam unknown schema %s", schema.Dump())
 }
 
-func qtParam(prefix string, node *model.TypedNode) (string, error) {
+func qtParam(esc *rkw.Escaper, prefix string, node *model.TypedNode) (string, error) {
 	if node == nil {
 		return "xxx", fmt.Errorf("qtParam node is nil")
 	}
-	return ToParamString(prefix, &node.Schema, node.Name)
+	return ToParamString(esc, prefix, &node.Schema, node.Name)
 }
@@ -40,7 +40,7 @@ func TestParams(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				meth := sys.LookupOperation(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, meth)
-				r, err := qtParams("", meth.Params)
+				r, err := qtParams(nil, "", meth.Params)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -69,7 +69,7 @@ func TestParamsSymbols(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				op := sys.LookupOperation(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, op)
-				r, err := qtParams("", op.Params)
+				r, err := qtParams(nil, "", op.Params)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -99,7 +99,7 @@ func TestParamsMultiple(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				op := sys.LookupOperation(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, op)
-				r, err := qtParams("", op.Params)
+				r, err := qtParams(nil, "", op.Params)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -127,7 +127,7 @@ func TestParamsExterns(t *testing.T) {
 			t.Run(tt.operation_name, func(t *testing.T) {
 				op := sys.LookupOperation(tt.module_name, tt.interface_name, tt.operation_name)
 				assert.NotNil(t, op)
-				r, err := qtParams("", op.Params)
+				r, err := qtParams(nil, "", op.Params)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.result, r)
 			})
@@ -4,15 +4,16 @@ import (
 	"fmt"
 
 	"github.com/apigear-io/cli/pkg/model"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
 )
 
-func ToVarString(node *model.TypedNode) (string, error) {
+func ToVarString(esc *rkw.Escaper, node *model.TypedNode) (string, error) {
 	if node == nil {
 		return "xxx", fmt.Errorf("qtVar node is nil")
 	}
-	return node.Name, nil
+	return esc.Escape(rkw.QT, node.Name), nil
 }
 
-func qtVar(node *model.TypedNode) (string, error) {
-	return ToVarString(node)
+func qtVar(esc *rkw.Escaper, node *model.TypedNode) (string, error) {
+	return ToVarString(esc, node)
 }
@@ -2,19 +2,22 @@ package filterrs
 
 import (
 	"text/template"
+
+	"github.com/apigear-io/cli/pkg/gen/filters/common"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
 )
 
 // PopulateFuncMap fills the given FuncMap with the functions from this package.
-func PopulateFuncMap(fm template.FuncMap) {
+func PopulateFuncMap(fm template.FuncMap, esc *rkw.Escaper) {
 	fm["rsNs"] = ns
 	fm["rsNsOpen"] = nsOpen
 	fm["rsNsClose"] = nsClose
 	fm["rsReturn"] = rsReturn
 	fm["rsDefault"] = rsDefault
-	fm["rsParam"] = rsParam
-	fm["rsParams"] = rsParams
-	fm["rsVar"] = rsVar
-	fm["rsVars"] = rsVars
+	fm["rsParam"] = common.WithEscaper3(esc, rsParam)
+	fm["rsParams"] = common.WithEscaper4(esc, rsParams)
+	fm["rsVar"] = common.WithEscaper2(esc, rsVar)
+	fm["rsVars"] = common.WithEscaper2(esc, rsVars)
 	fm["rsType"] = rsType
 	fm["rsTypeRef"] = rsTypeRef
 	fm["rsExtern"] = rsExtern
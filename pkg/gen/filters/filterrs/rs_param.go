@@ -4,10 +4,11 @@ import (
 	"fmt"
 
 	"github.com/apigear-io/cli/pkg/model"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
 )
 
-func ToParamString(prefixVarName string, prefixComplexType string, schema *model.Schema, node *model.TypedNode) (string, error) {
-	name, err := ToVarString(prefixVarName, node)
+func ToParamString(esc *rkw.Escaper, prefixVarName string, prefixComplexType string, schema *model.Schema, node *model.TypedNode) (string, error) {
+	name, err := ToVarString(esc, prefixVarName, node)
 	if err != nil {
 		return "xxx", fmt.Errorf("rsParam inner value error: %s", err)
 	}
@@ -56,9 +57,9 @@ func ToParamString(prefixVarName string, prefixComplexType string, schema *model
 	return "xxx", fmt.Errorf("rsParam unknown schema %s", schema.Dump())
 }
 
-func rsParam(prefixVarName string, prefixComplexType string, node *model.TypedNode) (string, error) {
+func rsParam(esc *rkw.Escaper, prefixVarName string, prefixComplexType string, node *model.TypedNode) (string, error) {
 	if node == nil {
 		return "xxx", fmt.Errorf("rsParam node is nil")
 	}
-	return ToParamString(prefixVarName, prefixComplexType, &node.Schema, node)
+	return ToParamString(esc, prefixVarName, prefixComplexType, &node.Schema, node)
 }
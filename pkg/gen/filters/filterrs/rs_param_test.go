@@ -36,7 +36,7 @@ func TestParam(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := rsParam("", "", prop)
+				r, err := rsParam(nil, "", "", prop)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -65,7 +65,7 @@ func TestParamSymbols(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := rsParam("", "", prop)
+				r, err := rsParam(nil, "", "", prop)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
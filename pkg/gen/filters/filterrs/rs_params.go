@@ -5,15 +5,16 @@ import (
 	"strings"
 
 	"github.com/apigear-io/cli/pkg/model"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
 )
 
-func rsParams(prefixVarName string, prefixComplexType string, separator string, nodes []*model.TypedNode) (string, error) {
+func rsParams(esc *rkw.Escaper, prefixVarName string, prefixComplexType string, separator string, nodes []*model.TypedNode) (string, error) {
 	if nodes == nil {
 		return "xxx", fmt.Errorf("rsParams called with nil nodes")
 	}
 	var params []string
 	for _, p := range nodes {
-		r, err := ToParamString(prefixVarName, prefixComplexType, &p.Schema, p)
+		r, err := ToParamString(esc, prefixVarName, prefixComplexType, &p.Schema, p)
 		if err != nil {
 			return "xxx", err
 		}
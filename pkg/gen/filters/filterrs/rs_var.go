@@ -5,15 +5,16 @@ import (
 
 	"github.com/apigear-io/cli/pkg/gen/filters/common"
 	"github.com/apigear-io/cli/pkg/model"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
 )
 
-func ToVarString(prefix string, node *model.TypedNode) (string, error) {
+func ToVarString(esc *rkw.Escaper, prefix string, node *model.TypedNode) (string, error) {
 	if node == nil {
 		return "xxx", fmt.Errorf("rsVar node is nil")
 	}
-	return fmt.Sprintf("%s%s", prefix, common.SnakeCaseLower(node.Name)), nil
+	return common.EscapePrefixed(esc, rkw.RS, prefix, common.SnakeCaseLower(node.Name)), nil
 }
 
-func rsVar(prefix string, node *model.TypedNode) (string, error) {
-	return ToVarString(prefix, node)
+func rsVar(esc *rkw.Escaper, prefix string, node *model.TypedNode) (string, error) {
+	return ToVarString(esc, prefix, node)
 }
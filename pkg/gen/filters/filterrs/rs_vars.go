@@ -5,15 +5,16 @@ import (
 	"strings"
 
 	"github.com/apigear-io/cli/pkg/model"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
 )
 
-func rsVars(prefix string, nodes []*model.TypedNode) (string, error) {
+func rsVars(esc *rkw.Escaper, prefix string, nodes []*model.TypedNode) (string, error) {
 	if nodes == nil {
 		return "xxx", fmt.Errorf("rsVars called with nil nodes")
 	}
 	names := make([]string, len(nodes))
 	for idx, p := range nodes {
-		name, err := ToVarString(prefix, p)
+		name, err := ToVarString(esc, prefix, p)
 		if err != nil {
 			return "xxx", err
 		}
@@ -0,0 +1,28 @@
+package filterswift
+
+import "github.com/apigear-io/cli/pkg/model"
+
+type SwiftExtern struct {
+	Module  string
+	Name    string
+	Default string
+}
+
+func parseSwiftExtern(schema *model.Schema) SwiftExtern {
+	xe := schema.GetExtern()
+	return swiftExtern(xe)
+}
+
+func swiftExtern(xe *model.Extern) SwiftExtern {
+	mod := xe.Meta.GetString("swift.module")
+	name := xe.Meta.GetString("swift.name")
+	dft := xe.Meta.GetString("swift.default")
+	if name == "" {
+		name = xe.Name
+	}
+	return SwiftExtern{
+		Module:  mod,
+		Name:    name,
+		Default: dft,
+	}
+}
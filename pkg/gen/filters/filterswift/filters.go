@@ -0,0 +1,23 @@
+package filterswift
+
+import (
+	"text/template"
+
+	"github.com/apigear-io/cli/pkg/gen/filters/common"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
+)
+
+// PopulateFuncMap fills the given FuncMap with the functions from this package.
+func PopulateFuncMap(fm template.FuncMap, esc *rkw.Escaper) {
+	fm["swiftDefault"] = swiftDefault
+	fm["swiftReturn"] = swiftReturn
+	fm["swiftParam"] = common.WithEscaper2(esc, swiftParam)
+	fm["swiftParams"] = common.WithEscaper2(esc, swiftParams)
+	fm["swiftVar"] = common.WithEscaper1(esc, swiftVar)
+	fm["swiftVars"] = common.WithEscaper1(esc, swiftVars)
+	fm["swiftType"] = swiftType
+	fm["swiftExtern"] = swiftExtern
+	fm["swiftAsyncReturn"] = swiftAsyncReturn
+	fm["swiftCompletion"] = swiftCompletion
+	fm["swiftTestValue"] = swiftTestValue
+}
@@ -0,0 +1,50 @@
+package filterswift
+
+import (
+	"fmt"
+
+	"github.com/apigear-io/cli/pkg/model"
+)
+
+// ToAsyncReturnString returns the signature suffix of an async function,
+// e.g. "async throws -> Int" or "async throws" for void.
+func ToAsyncReturnString(prefix string, schema *model.Schema) (string, error) {
+	if schema == nil {
+		return "xxx", fmt.Errorf("ToAsyncReturnString schema is nil")
+	}
+	if schema.KindType == model.TypeVoid {
+		return "async throws", nil
+	}
+	ret, err := ToReturnString(prefix, schema)
+	if err != nil {
+		return "xxx", err
+	}
+	return fmt.Sprintf("async throws -> %s", ret), nil
+}
+
+func swiftAsyncReturn(prefix string, node *model.TypedNode) (string, error) {
+	if node == nil {
+		return "xxx", fmt.Errorf("swiftAsyncReturn node is nil")
+	}
+	return ToAsyncReturnString(prefix, &node.Schema)
+}
+
+// ToCompletionString returns the completion handler type of a callback based
+// async function, e.g. "@escaping (Result<Int, Error>) -> Void".
+func ToCompletionString(prefix string, schema *model.Schema) (string, error) {
+	if schema == nil {
+		return "xxx", fmt.Errorf("ToCompletionString schema is nil")
+	}
+	ret, err := ToReturnString(prefix, schema)
+	if err != nil {
+		return "xxx", err
+	}
+	return fmt.Sprintf("@escaping (Result<%s, Error>) -> Void", ret), nil
+}
+
+func swiftCompletion(prefix string, node *model.TypedNode) (string, error) {
+	if node == nil {
+		return "xxx", fmt.Errorf("swiftCompletion node is nil")
+	}
+	return ToCompletionString(prefix, &node.Schema)
+}
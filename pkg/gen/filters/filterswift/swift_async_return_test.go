@@ -0,0 +1,51 @@
+package filterswift
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsyncReturn(t *testing.T) {
+	t.Parallel()
+	syss := loadTestSystems(t)
+	var opTests = []struct {
+		mn string
+		in string
+		pn string
+		rt string
+		cb string
+	}{
+		{"test", "Test3", "opVoid", "async throws", "@escaping (Result<Void, Error>) -> Void"},
+		{"test", "Test3", "opBool", "async throws -> Bool", "@escaping (Result<Bool, Error>) -> Void"},
+		{"test", "Test3", "opInt32", "async throws -> Int32", "@escaping (Result<Int32, Error>) -> Void"},
+		{"test", "Test3", "opString", "async throws -> String", "@escaping (Result<String, Error>) -> Void"},
+		{"test", "Test3", "opFloatArray", "async throws -> [Float]", "@escaping (Result<[Float], Error>) -> Void"},
+		{"test", "Test4", "opStruct", "async throws -> Struct1", "@escaping (Result<Struct1, Error>) -> Void"},
+		{"test", "Test4", "opEnumArray", "async throws -> [Enum1]", "@escaping (Result<[Enum1], Error>) -> Void"},
+	}
+	for _, sys := range syss {
+		for _, tt := range opTests {
+			t.Run(tt.pn, func(t *testing.T) {
+				op := sys.LookupOperation(tt.mn, tt.in, tt.pn)
+				assert.NotNil(t, op)
+				r, err := swiftAsyncReturn("", op.Return)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.rt, r)
+				r, err = swiftCompletion("", op.Return)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.cb, r)
+			})
+		}
+	}
+}
+
+func TestAsyncReturnWithErrors(t *testing.T) {
+	t.Parallel()
+	s, err := swiftAsyncReturn("", nil)
+	assert.Error(t, err)
+	assert.Equal(t, "xxx", s)
+	s, err = swiftCompletion("", nil)
+	assert.Error(t, err)
+	assert.Equal(t, "xxx", s)
+}
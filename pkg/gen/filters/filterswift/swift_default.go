@@ -0,0 +1,58 @@
+package filterswift
+
+import (
+	"fmt"
+
+	"github.com/apigear-io/cli/pkg/gen/filters/common"
+	"github.com/apigear-io/cli/pkg/model"
+)
+
+func ToDefaultString(schema *model.Schema, prefix string) (string, error) {
+	if schema == nil {
+		return "xxx", fmt.Errorf("ToDefaultString schema is nil")
+	}
+	if schema.IsArray {
+		return "[]", nil
+	}
+	var text string
+	switch schema.KindType {
+	case model.TypeString:
+		text = "\"\""
+	case model.TypeInt, model.TypeInt32, model.TypeInt64:
+		text = "0"
+	case model.TypeFloat, model.TypeFloat32, model.TypeFloat64:
+		text = "0.0"
+	case model.TypeBool:
+		text = "false"
+	case model.TypeBytes:
+		text = "Data()"
+	case model.TypeAny:
+		text = "nil"
+	case model.TypeEnum:
+		symbol := schema.GetEnum()
+		member := symbol.Members[0]
+		text = fmt.Sprintf("%s%s.%s", prefix, symbol.Name, common.CamelLowerCase(member.Name))
+	case model.TypeStruct:
+		symbol := schema.GetStruct()
+		text = fmt.Sprintf("%s%s()", prefix, symbol.Name)
+	case model.TypeExtern:
+		xe := parseSwiftExtern(schema)
+		if xe.Default != "" {
+			text = xe.Default
+		} else {
+			text = fmt.Sprintf("%s()", xe.Name)
+		}
+	case model.TypeInterface:
+		text = "nil"
+	default:
+		return "xxx", fmt.Errorf("swiftDefault unknown schema %s", schema.Dump())
+	}
+	return text, nil
+}
+
+func swiftDefault(prefix string, node *model.TypedNode) (string, error) {
+	if node == nil {
+		return "xxx", fmt.Errorf("swiftDefault node is nil")
+	}
+	return ToDefaultString(&node.Schema, prefix)
+}
@@ -0,0 +1,79 @@
+package filterswift
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefault(t *testing.T) {
+	t.Parallel()
+	syss := loadTestSystems(t)
+	var propTests = []struct {
+		mn string
+		in string
+		pn string
+		rt string
+	}{
+		{"test", "Test1", "propBool", "false"},
+		{"test", "Test1", "propInt", "0"},
+		{"test", "Test1", "propInt32", "0"},
+		{"test", "Test1", "propInt64", "0"},
+		{"test", "Test1", "propFloat", "0.0"},
+		{"test", "Test1", "propFloat32", "0.0"},
+		{"test", "Test1", "propFloat64", "0.0"},
+		{"test", "Test1", "propString", "\"\""},
+		{"test", "Test1", "propBytes", "Data()"},
+		{"test", "Test1", "propAny", "nil"},
+		{"test", "Test1", "propBoolArray", "[]"},
+		{"test", "Test1", "propStringArray", "[]"},
+		{"test", "Test2", "propEnum", "Enum1.default"},
+		{"test", "Test2", "propStruct", "Struct1()"},
+		{"test", "Test2", "propInterface", "nil"},
+		{"test", "Test2", "propEnumArray", "[]"},
+		{"test", "Test2", "propStructArray", "[]"},
+	}
+	for _, sys := range syss {
+		for _, tt := range propTests {
+			t.Run(tt.pn, func(t *testing.T) {
+				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
+				assert.NotNil(t, prop)
+				r, err := swiftDefault("", prop)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.rt, r)
+			})
+		}
+	}
+}
+
+func TestDefaultWithErrors(t *testing.T) {
+	t.Parallel()
+	s, err := swiftDefault("", nil)
+	assert.Error(t, err)
+	assert.Equal(t, "xxx", s)
+}
+
+func TestExternDefault(t *testing.T) {
+	syss := loadExternSystems(t)
+	var propTests = []struct {
+		mn string
+		in string
+		pn string
+		rt string
+	}{
+		{"demo", "Iface1", "prop1", "XType1()"},
+		{"demo", "Iface1", "prop2", "XType2()"},
+		{"demo", "Iface1", "prop3", "XType3A.make()"},
+	}
+	for _, sys := range syss {
+		for _, tt := range propTests {
+			t.Run(tt.pn, func(t *testing.T) {
+				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
+				assert.NotNil(t, prop)
+				r, err := swiftDefault("", prop)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.rt, r)
+			})
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package filterswift
+
+import (
+	"fmt"
+
+	"github.com/apigear-io/cli/pkg/model"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
+)
+
+func ToParamString(esc *rkw.Escaper, prefix string, schema *model.Schema, name string) (string, error) {
+	if schema == nil {
+		return "xxx", fmt.Errorf("ToParamString schema is nil")
+	}
+	name = esc.Escape(rkw.SWIFT, name)
+	if schema.KindType == model.TypeVoid {
+		return "xxx", fmt.Errorf("swiftParam void is not a parameter type")
+	}
+	ret, err := ToReturnString(prefix, schema)
+	if err != nil {
+		return "xxx", fmt.Errorf("swiftParam type error: %s", err)
+	}
+	return fmt.Sprintf("%s: %s", name, ret), nil
+}
+
+func swiftParam(esc *rkw.Escaper, prefix string, node *model.TypedNode) (string, error) {
+	if node == nil {
+		return "xxx", fmt.Errorf("swiftParam node is nil")
+	}
+	return ToParamString(esc, prefix, &node.Schema, node.Name)
+}
@@ -0,0 +1,81 @@
+package filterswift
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParam(t *testing.T) {
+	t.Parallel()
+	syss := loadTestSystems(t)
+	var propTests = []struct {
+		mn string
+		in string
+		pn string
+		rt string
+	}{
+		{"test", "Test1", "propBool", "propBool: Bool"},
+		{"test", "Test1", "propInt", "propInt: Int"},
+		{"test", "Test1", "propInt32", "propInt32: Int32"},
+		{"test", "Test1", "propInt64", "propInt64: Int64"},
+		{"test", "Test1", "propFloat", "propFloat: Float"},
+		{"test", "Test1", "propFloat32", "propFloat32: Float"},
+		{"test", "Test1", "propFloat64", "propFloat64: Double"},
+		{"test", "Test1", "propString", "propString: String"},
+		{"test", "Test1", "propBytes", "propBytes: Data"},
+		{"test", "Test1", "propAny", "propAny: Any?"},
+		{"test", "Test1", "propBoolArray", "propBoolArray: [Bool]"},
+		{"test", "Test1", "propIntArray", "propIntArray: [Int]"},
+		{"test", "Test1", "propStringArray", "propStringArray: [String]"},
+		{"test", "Test1", "prop_Bool", "prop_Bool: Bool"},
+		{"test", "Test1", "prop_bool", "prop_bool: Bool"},
+		{"test", "Test1", "prop_1", "prop_1: Bool"},
+		{"test", "Test2", "propEnum", "propEnum: Enum1"},
+		{"test", "Test2", "propStruct", "propStruct: Struct1"},
+		{"test", "Test2", "propInterface", "propInterface: Interface1?"},
+		{"test", "Test2", "propEnumArray", "propEnumArray: [Enum1]"},
+	}
+	for _, sys := range syss {
+		for _, tt := range propTests {
+			t.Run(tt.pn, func(t *testing.T) {
+				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
+				assert.NotNil(t, prop)
+				r, err := swiftParam(nil, "", prop)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.rt, r)
+			})
+		}
+	}
+}
+
+func TestParamWithErrors(t *testing.T) {
+	s, err := swiftParam(nil, "", nil)
+	assert.Error(t, err)
+	assert.Equal(t, "xxx", s)
+}
+
+func TestExternParam(t *testing.T) {
+	syss := loadExternSystems(t)
+	var propTests = []struct {
+		mn string
+		in string
+		pn string
+		rt string
+	}{
+		{"demo", "Iface1", "func1", "arg1: XType1"},
+		{"demo", "Iface1", "func2", "arg1: XType2"},
+		{"demo", "Iface1", "func3", "arg1: XType3A"},
+	}
+	for _, sys := range syss {
+		for _, tt := range propTests {
+			t.Run(tt.pn, func(t *testing.T) {
+				op := sys.LookupOperation(tt.mn, tt.in, tt.pn)
+				assert.NotNil(t, op)
+				r, err := swiftParam(nil, "", op.Params[0])
+				assert.NoError(t, err)
+				assert.Equal(t, tt.rt, r)
+			})
+		}
+	}
+}
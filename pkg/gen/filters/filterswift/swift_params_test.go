@@ -0,0 +1,39 @@
+package filterswift
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParams(t *testing.T) {
+	t.Parallel()
+	table := []struct {
+		mn string
+		in string
+		pn string
+		rt string
+	}{
+		{"test", "Test3", "opBool", "param1: Bool"},
+		{"test", "Test3", "opInt", "param1: Int"},
+		{"test", "Test3", "opInt64", "param1: Int64"},
+		{"test", "Test3", "opFloat64", "param1: Double"},
+		{"test", "Test3", "opString", "param1: String"},
+		{"test", "Test3", "opStringArray", "param1: [String]"},
+		{"test", "Test3", "op_Bool", "param_Bool: Bool"},
+		{"test", "Test5", "opIntInt", "param1: Int, param2: Int"},
+		{"test", "Test5", "opStringString", "param1: String, param2: String"},
+	}
+	syss := loadTestSystems(t)
+	for _, sys := range syss {
+		for _, tt := range table {
+			t.Run(tt.pn, func(t *testing.T) {
+				m := sys.LookupOperation(tt.mn, tt.in, tt.pn)
+				assert.NotNil(t, m)
+				r, err := swiftParams(nil, "", m.Params)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.rt, r)
+			})
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package filterswift
+
+import (
+	"fmt"
+
+	"github.com/apigear-io/cli/pkg/model"
+)
+
+func ToReturnString(prefix string, schema *model.Schema) (string, error) {
+	if schema == nil {
+		return "xxx", fmt.Errorf("ToReturnString schema is nil")
+	}
+	var text string
+	switch schema.KindType {
+	case model.TypeString:
+		text = "String"
+	case model.TypeInt:
+		text = "Int"
+	case model.TypeInt32:
+		text = "Int32"
+	case model.TypeInt64:
+		text = "Int64"
+	case model.TypeFloat:
+		text = "Float"
+	case model.TypeFloat32:
+		text = "Float"
+	case model.TypeFloat64:
+		text = "Double"
+	case model.TypeBool:
+		text = "Bool"
+	case model.TypeBytes:
+		text = "Data"
+	case model.TypeAny:
+		text = "Any?"
+	case model.TypeEnum:
+		symbol := schema.GetEnum()
+		text = fmt.Sprintf("%s%s", prefix, symbol.Name)
+	case model.TypeStruct:
+		symbol := schema.GetStruct()
+		text = fmt.Sprintf("%s%s", prefix, symbol.Name)
+	case model.TypeExtern:
+		xe := parseSwiftExtern(schema)
+		text = xe.Name
+	case model.TypeInterface:
+		// interfaces are protocols, which are optional references
+		symbol := schema.GetInterface()
+		text = fmt.Sprintf("%s%s?", prefix, symbol.Name)
+	case model.TypeVoid:
+		text = "Void"
+	default:
+		return "xxx", fmt.Errorf("swiftReturn unknown schema %s", schema.Dump())
+	}
+	if schema.IsArray {
+		text = fmt.Sprintf("[%s]", text)
+	}
+	return text, nil
+}
+
+func swiftReturn(prefix string, node *model.TypedNode) (string, error) {
+	if node == nil {
+		return "xxx", fmt.Errorf("swiftReturn node is nil")
+	}
+	return ToReturnString(prefix, &node.Schema)
+}
@@ -0,0 +1,134 @@
+package filterswift
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReturn(t *testing.T) {
+	t.Parallel()
+	syss := loadTestSystems(t)
+	var propTests = []struct {
+		mn string
+		in string
+		pn string
+		rt string
+	}{
+		{"test", "Test1", "propVoid", "Void"},
+		{"test", "Test1", "propBool", "Bool"},
+		{"test", "Test1", "propInt", "Int"},
+		{"test", "Test1", "propInt32", "Int32"},
+		{"test", "Test1", "propInt64", "Int64"},
+		{"test", "Test1", "propFloat", "Float"},
+		{"test", "Test1", "propFloat32", "Float"},
+		{"test", "Test1", "propFloat64", "Double"},
+		{"test", "Test1", "propString", "String"},
+		{"test", "Test1", "propBytes", "Data"},
+		{"test", "Test1", "propAny", "Any?"},
+		{"test", "Test1", "propBoolArray", "[Bool]"},
+		{"test", "Test1", "propIntArray", "[Int]"},
+		{"test", "Test1", "propInt32Array", "[Int32]"},
+		{"test", "Test1", "propInt64Array", "[Int64]"},
+		{"test", "Test1", "propFloatArray", "[Float]"},
+		{"test", "Test1", "propFloat32Array", "[Float]"},
+		{"test", "Test1", "propFloat64Array", "[Double]"},
+		{"test", "Test1", "propStringArray", "[String]"},
+		{"test", "Test1", "propBytesArray", "[Data]"},
+		{"test", "Test1", "propAnyArray", "[Any?]"},
+		{"test", "Test2", "propEnum", "Enum1"},
+		{"test", "Test2", "propStruct", "Struct1"},
+		{"test", "Test2", "propInterface", "Interface1?"},
+		{"test", "Test2", "propEnumArray", "[Enum1]"},
+		{"test", "Test2", "propStructArray", "[Struct1]"},
+		{"test", "Test2", "propInterfaceArray", "[Interface1?]"},
+	}
+	for _, sys := range syss {
+		for _, tt := range propTests {
+			t.Run(tt.pn, func(t *testing.T) {
+				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
+				assert.NotNil(t, prop)
+				r, err := swiftReturn("", prop)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.rt, r)
+			})
+		}
+	}
+}
+
+func TestOperationReturn(t *testing.T) {
+	t.Parallel()
+	syss := loadTestSystems(t)
+	var propTests = []struct {
+		mn string
+		in string
+		pn string
+		rt string
+	}{
+		{"test", "Test3", "opVoid", "Void"},
+		{"test", "Test3", "opBool", "Bool"},
+		{"test", "Test3", "opInt", "Int"},
+		{"test", "Test3", "opInt64", "Int64"},
+		{"test", "Test3", "opFloat64", "Double"},
+		{"test", "Test3", "opString", "String"},
+		{"test", "Test3", "opStringArray", "[String]"},
+		{"test", "Test4", "opEnum", "Enum1"},
+		{"test", "Test4", "opStructArray", "[Struct1]"},
+	}
+	for _, sys := range syss {
+		for _, tt := range propTests {
+			t.Run(tt.pn, func(t *testing.T) {
+				op := sys.LookupOperation(tt.mn, tt.in, tt.pn)
+				assert.NotNil(t, op)
+				r, err := swiftReturn("", op.Return)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.rt, r)
+			})
+		}
+	}
+}
+
+func TestReturnWithErrors(t *testing.T) {
+	t.Parallel()
+	s, err := swiftReturn("", nil)
+	assert.Error(t, err)
+	assert.Equal(t, "xxx", s)
+}
+
+func TestExternReturn(t *testing.T) {
+	syss := loadExternSystems(t)
+	var propTests = []struct {
+		mn string
+		in string
+		pn string
+		rt string
+	}{
+		{"demo", "Iface1", "prop1", "XType1"},
+		{"demo", "Iface1", "prop2", "XType2"},
+		{"demo", "Iface1", "prop3", "XType3A"},
+	}
+	for _, sys := range syss {
+		for _, tt := range propTests {
+			t.Run(tt.pn, func(t *testing.T) {
+				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
+				assert.NotNil(t, prop)
+				r, err := swiftReturn("", prop)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.rt, r)
+			})
+		}
+	}
+}
+
+func TestExtern(t *testing.T) {
+	syss := loadExternSystems(t)
+	for _, sys := range syss {
+		xe := sys.LookupExtern("demo", "XType3")
+		assert.NotNil(t, xe)
+		assert.Equal(t, SwiftExtern{
+			Module:  "DemoX",
+			Name:    "XType3A",
+			Default: "XType3A.make()",
+		}, swiftExtern(xe))
+	}
+}
@@ -0,0 +1,62 @@
+package filterswift
+
+import (
+	"fmt"
+
+	"github.com/apigear-io/cli/pkg/gen/filters/common"
+	"github.com/apigear-io/cli/pkg/model"
+)
+
+// ToTestValueString returns the test value string for a given schema.
+// We intentionally ignore arrays in order to return the test value of the inner type.
+func ToTestValueString(prefix string, schema *model.Schema) (string, error) {
+	if schema == nil {
+		return "", fmt.Errorf("swiftTestValue schema is nil")
+	}
+	var text string
+	switch schema.KindType {
+	case model.TypeString:
+		text = "\"xyz\""
+	case model.TypeInt, model.TypeInt32, model.TypeInt64:
+		text = "1"
+	case model.TypeFloat, model.TypeFloat32, model.TypeFloat64:
+		text = "1.1"
+	case model.TypeBool:
+		text = "true"
+	case model.TypeBytes:
+		text = "Data([0x01])"
+	case model.TypeAny:
+		text = "\"xyz\""
+	case model.TypeVoid:
+		text = ""
+	case model.TypeEnum:
+		symbol := schema.GetEnum()
+		member := symbol.Members[0]
+		if len(symbol.Members) > 1 {
+			member = symbol.Members[1]
+		}
+		text = fmt.Sprintf("%s%s.%s", prefix, symbol.Name, common.CamelLowerCase(member.Name))
+	case model.TypeStruct:
+		symbol := schema.GetStruct()
+		text = fmt.Sprintf("%s%s()", prefix, symbol.Name)
+	case model.TypeExtern:
+		xe := parseSwiftExtern(schema)
+		if xe.Default != "" {
+			text = xe.Default
+		} else {
+			text = fmt.Sprintf("%s()", xe.Name)
+		}
+	case model.TypeInterface:
+		text = "nil"
+	default:
+		return "xxx", fmt.Errorf("swiftTestValue unknown schema %s", schema.Dump())
+	}
+	return text, nil
+}
+
+func swiftTestValue(prefix string, node *model.TypedNode) (string, error) {
+	if node == nil {
+		return "xxx", fmt.Errorf("swiftTestValue node is nil")
+	}
+	return ToTestValueString(prefix, &node.Schema)
+}
@@ -0,0 +1,41 @@
+package filterswift
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTestValue(t *testing.T) {
+	t.Parallel()
+	syss := loadTestSystems(t)
+	var propTests = []struct {
+		mn string
+		in string
+		pn string
+		rt string
+	}{
+		{"test", "Test1", "propBool", "true"},
+		{"test", "Test1", "propInt", "1"},
+		{"test", "Test1", "propInt64", "1"},
+		{"test", "Test1", "propFloat", "1.1"},
+		{"test", "Test1", "propFloat64", "1.1"},
+		{"test", "Test1", "propString", "\"xyz\""},
+		{"test", "Test1", "propBytes", "Data([0x01])"},
+		{"test", "Test1", "propIntArray", "1"},
+		{"test", "Test2", "propEnum", "Enum1.notDefault"},
+		{"test", "Test2", "propStruct", "Struct1()"},
+		{"test", "Test2", "propInterface", "nil"},
+	}
+	for _, sys := range syss {
+		for _, tt := range propTests {
+			t.Run(tt.pn, func(t *testing.T) {
+				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
+				assert.NotNil(t, prop)
+				r, err := swiftTestValue("", prop)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.rt, r)
+			})
+		}
+	}
+}
@@ -0,0 +1,3 @@
+package filterswift
+
+var swiftType = swiftReturn
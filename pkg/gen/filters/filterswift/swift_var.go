@@ -0,0 +1,19 @@
+package filterswift
+
+import (
+	"fmt"
+
+	"github.com/apigear-io/cli/pkg/model"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
+)
+
+func ToVarString(esc *rkw.Escaper, node *model.TypedNode) (string, error) {
+	if node == nil {
+		return "xxx", fmt.Errorf("ToVarString node is nil")
+	}
+	return esc.Escape(rkw.SWIFT, node.Name), nil
+}
+
+func swiftVar(esc *rkw.Escaper, node *model.TypedNode) (string, error) {
+	return ToVarString(esc, node)
+}
@@ -2,15 +2,18 @@ package filterts
 
 import (
 	"text/template"
+
+	"github.com/apigear-io/cli/pkg/gen/filters/common"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
 )
 
 // PopulateFuncMap fills the given FuncMap with the functions from this package.
-func PopulateFuncMap(fm template.FuncMap) {
+func PopulateFuncMap(fm template.FuncMap, esc *rkw.Escaper) {
 	fm["tsReturn"] = tsReturn
 	fm["tsDefault"] = tsDefault
-	fm["tsParam"] = tsParam
-	fm["tsParams"] = tsParams
-	fm["tsVar"] = tsVar
-	fm["tsVars"] = tsVars
+	fm["tsParam"] = common.WithEscaper2(esc, tsParam)
+	fm["tsParams"] = common.WithEscaper2(esc, tsParams)
+	fm["tsVar"] = common.WithEscaper1(esc, tsVar)
+	fm["tsVars"] = common.WithEscaper1(esc, tsVars)
 	fm["tsType"] = tsType
 }
@@ -2,19 +2,22 @@ package filterue
 
 import (
 	"text/template"
+
+	"github.com/apigear-io/cli/pkg/gen/filters/common"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
 )
 
 // PopulateFuncMap fills the given FuncMap with the functions from this package.
-func PopulateFuncMap(fm template.FuncMap) {
-	fm["ueParam"] = ueParam
-	fm["ueParams"] = ueParams
+func PopulateFuncMap(fm template.FuncMap, esc *rkw.Escaper) {
+	fm["ueParam"] = common.WithEscaper2(esc, ueParam)
+	fm["ueParams"] = common.WithEscaper2(esc, ueParams)
 	fm["ueReturn"] = ueReturn
 	fm["ueDefault"] = ueDefault
 	fm["ueTestValue"] = ueTestValue
 	fm["ueConstType"] = ueConstType
 	fm["ueType"] = ueType
-	fm["ueVar"] = ueVar
-	fm["ueVars"] = ueVars
+	fm["ueVar"] = common.WithEscaper2(esc, ueVar)
+	fm["ueVars"] = common.WithEscaper2(esc, ueVars)
 	fm["ueIsStdSimpleType"] = ueIsStdSimpleType
 	fm["ueExtern"] = ueExtern
 }
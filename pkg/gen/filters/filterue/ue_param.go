@@ -3,15 +3,17 @@ package filterue
 import (
 	"fmt"
 
+	"github.com/apigear-io/cli/pkg/gen/filters/common"
 	"github.com/apigear-io/cli/pkg/model"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
 	"github.com/ettle/strcase"
 )
 
-func ToParamString(schema *model.Schema, name string, prefix string) (string, error) {
+func ToParamString(esc *rkw.Escaper, schema *model.Schema, name string, prefix string) (string, error) {
 	if schema == nil {
 		return "xxx", fmt.Errorf("ueParam schema is nil")
 	}
-	name = strcase.ToPascal(name)
+	name = common.EscapePrefixed(esc, rkw.UE, prefix, strcase.ToPascal(name))
 	moduleId := strcase.ToPascal(schema.Module.Name)
 	if schema.Import != "" {
 		moduleId = strcase.ToPascal(schema.Import)
@@ -23,49 +25,49 @@ func ToParamString(schema *model.Schema, name string, prefix string) (string, er
 		if err != nil {
 			return "xxx", fmt.Errorf("ueParam inner value error: %s", err)
 		}
-		return fmt.Sprintf("const TArray<%s>& %s%s", innerValue, prefix, name), nil
+		return fmt.Sprintf("const TArray<%s>& %s", innerValue, name), nil
 	}
 	switch t {
 	case "string":
-		return fmt.Sprintf("const FString& %s%s", prefix, name), nil
+		return fmt.Sprintf("const FString& %s", name), nil
 	case "int":
-		return fmt.Sprintf("int32 %s%s", prefix, name), nil
+		return fmt.Sprintf("int32 %s", name), nil
 	case "int32":
-		return fmt.Sprintf("int32 %s%s", prefix, name), nil
+		return fmt.Sprintf("int32 %s", name), nil
 	case "int64":
-		return fmt.Sprintf("int64 %s%s", prefix, name), nil
+		return fmt.Sprintf("int64 %s", name), nil
 	case "float":
-		return fmt.Sprintf("float %s%s", prefix, name), nil
+		return fmt.Sprintf("float %s", name), nil
 	case "float32":
-		return fmt.Sprintf("float %s%s", prefix, name), nil
+		return fmt.Sprintf("float %s", name), nil
 	case "float64":
-		return fmt.Sprintf("double %s%s", prefix, name), nil
+		return fmt.Sprintf("double %s", name), nil
 	case "bool":
-		return fmt.Sprintf("bool b%s%s", prefix, name), nil
+		return fmt.Sprintf("bool b%s", name), nil
 	}
 
 	e := schema.LookupEnum(schema.Import, schema.Type)
 	if e != nil {
-		return fmt.Sprintf("E%s%s %s%s", moduleId, e.Name, prefix, name), nil
+		return fmt.Sprintf("E%s%s %s", moduleId, e.Name, name), nil
 	}
 	s := schema.LookupStruct(schema.Import, schema.Type)
 	if s != nil {
-		return fmt.Sprintf("const F%s%s& %s%s", moduleId, s.Name, prefix, name), nil
+		return fmt.Sprintf("const F%s%s& %s", moduleId, s.Name, name), nil
 	}
 	ex := schema.LookupExtern(schema.Import, schema.Type)
 	if ex != nil {
-		return fmt.Sprintf("const %s& %s%s", ueExtern(schema.GetExtern()).Name, prefix, name), nil
+		return fmt.Sprintf("const %s& %s", ueExtern(schema.GetExtern()).Name, name), nil
 	}
 	i := schema.LookupInterface(schema.Import, schema.Type)
 	if i != nil {
-		return fmt.Sprintf("const TScriptInterface<I%s%sInterface>& %s%s", moduleId, i.Name, prefix, name), nil
+		return fmt.Sprintf("const TScriptInterface<I%s%sInterface>& %s", moduleId, i.Name, name), nil
 	}
 	return "xxx", fmt.Errorf("ueParam: unknown schema %s", schema.Dump())
 }
 
-func ueParam(prefix string, node *model.TypedNode) (string, error) {
+func ueParam(esc *rkw.Escaper, prefix string, node *model.TypedNode) (string, error) {
 	if node == nil {
 		return "xxx", fmt.Errorf("ueParam called with nil node")
 	}
-	return ToParamString(&node.Schema, node.Name, prefix)
+	return ToParamString(esc, &node.Schema, node.Name, prefix)
 }
@@ -40,7 +40,7 @@ func TestParam(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := ueParam("", prop)
+				r, err := ueParam(nil, "", prop)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -69,7 +69,7 @@ func TestParamSymbols(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupProperty(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := ueParam("", prop)
+				r, err := ueParam(nil, "", prop)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -79,7 +79,7 @@ func TestParamSymbols(t *testing.T) {
 
 func TestParamWithErrors(t *testing.T) {
 	t.Parallel()
-	s, err := ueParam("", nil)
+	s, err := ueParam(nil, "", nil)
 	assert.Error(t, err)
 	assert.Equal(t, "xxx", s)
 }
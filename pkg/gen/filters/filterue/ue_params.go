@@ -5,15 +5,16 @@ import (
 	"strings"
 
 	"github.com/apigear-io/cli/pkg/model"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
 )
 
-func ueParams(prefix string, nodes []*model.TypedNode) (string, error) {
+func ueParams(esc *rkw.Escaper, prefix string, nodes []*model.TypedNode) (string, error) {
 	if nodes == nil {
 		return "", fmt.Errorf("useParams called with nil nodes")
 	}
 	var params []string
 	for _, p := range nodes {
-		r, err := ToParamString(&p.Schema, p.Name, prefix)
+		r, err := ToParamString(esc, &p.Schema, p.Name, prefix)
 		if err != nil {
 			return "xxx", err
 		}
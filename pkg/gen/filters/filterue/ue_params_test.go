@@ -40,7 +40,7 @@ func TestParams(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				meth := sys.LookupOperation(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, meth)
-				r, err := ueParams("", meth.Params)
+				r, err := ueParams(nil, "", meth.Params)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -70,7 +70,7 @@ func TestParamsSymbols(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupOperation(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := ueParams("", prop.Params)
+				r, err := ueParams(nil, "", prop.Params)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -100,7 +100,7 @@ func TestParamsMultiple(t *testing.T) {
 			t.Run(tt.pn, func(t *testing.T) {
 				prop := sys.LookupOperation(tt.mn, tt.in, tt.pn)
 				assert.NotNil(t, prop)
-				r, err := ueParams("", prop.Params)
+				r, err := ueParams(nil, "", prop.Params)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.rt, r)
 			})
@@ -110,7 +110,7 @@ func TestParamsMultiple(t *testing.T) {
 
 func TestParamsWithErrors(t *testing.T) {
 	t.Parallel()
-	s, err := ueParams("", nil)
+	s, err := ueParams(nil, "", nil)
 	assert.Error(t, err)
 	assert.Equal(t, "", s)
 }
@@ -3,11 +3,13 @@ package filterue
 import (
 	"fmt"
 
+	"github.com/apigear-io/cli/pkg/gen/filters/common"
 	"github.com/apigear-io/cli/pkg/model"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
 	"github.com/ettle/strcase"
 )
 
-func ToVarString(prefix string, node *model.TypedNode) (string, error) {
+func ToVarString(esc *rkw.Escaper, prefix string, node *model.TypedNode) (string, error) {
 	if node == nil {
 		return "xxx", fmt.Errorf("ueVar node is nil")
 	}
@@ -16,12 +18,12 @@ func ToVarString(prefix string, node *model.TypedNode) (string, error) {
 	if !schema.IsArray && schema.KindType == model.TypeBool {
 		text = "b"
 	}
-	return fmt.Sprintf("%s%s%s", text, prefix, strcase.ToPascal(node.Name)), nil
+	return common.EscapePrefixed(esc, rkw.UE, text+prefix, strcase.ToPascal(node.Name)), nil
 }
 
-func ueVar(prefix string, node *model.TypedNode) (string, error) {
+func ueVar(esc *rkw.Escaper, prefix string, node *model.TypedNode) (string, error) {
 	if node == nil {
 		return "xxx", fmt.Errorf("ueVar node is nil")
 	}
-	return ToVarString(prefix, node)
+	return ToVarString(esc, prefix, node)
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/apigear-io/cli/pkg/gen/filters/common"
 	"github.com/apigear-io/cli/pkg/gen/filters/filtercpp"
+	"github.com/apigear-io/cli/pkg/gen/filters/filtercs"
 	"github.com/apigear-io/cli/pkg/gen/filters/filtergo"
 	"github.com/apigear-io/cli/pkg/gen/filters/filterjava"
 	"github.com/apigear-io/cli/pkg/gen/filters/filterjni"
@@ -12,24 +13,39 @@ import (
 	"github.com/apigear-io/cli/pkg/gen/filters/filterpy"
 	"github.com/apigear-io/cli/pkg/gen/filters/filterqt"
 	"github.com/apigear-io/cli/pkg/gen/filters/filterrs"
+	"github.com/apigear-io/cli/pkg/gen/filters/filterswift"
 	"github.com/apigear-io/cli/pkg/gen/filters/filterts"
 	"github.com/apigear-io/cli/pkg/gen/filters/filterue"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
 )
 
+// Packs are the names of the filter packs populated into the func map,
+// templates can require them in the engines section of their rules
+var Packs = []string{"common", "cpp", "cs", "go", "java", "jni", "js", "py", "qt", "rs", "swift", "ts", "ue"}
+
+// PopulateFuncMap returns the built-in filters, which escape reserved words natively
 func PopulateFuncMap() template.FuncMap {
+	return PopulateFuncMapWithEscaper(rkw.DefaultEscaper)
+}
+
+// PopulateFuncMapWithEscaper returns the built-in filters,
+// which escape reserved words in identifiers by the given escaper
+func PopulateFuncMapWithEscaper(esc *rkw.Escaper) template.FuncMap {
 	fm := make(template.FuncMap)
 
 	common.PopulateFuncMap(fm)
-	filtercpp.PopulateFuncMap(fm)
-	filtergo.PopulateFuncMap(fm)
-	filterts.PopulateFuncMap(fm)
-	filterpy.PopulateFuncMap(fm)
-	filterue.PopulateFuncMap(fm)
-	filterqt.PopulateFuncMap(fm)
-	filterjs.PopulateFuncMap(fm)
-	filterrs.PopulateFuncMap(fm)
-	filterjava.PopulateFuncMap(fm)
+	filtercpp.PopulateFuncMap(fm, esc)
+	filtergo.PopulateFuncMap(fm, esc)
+	filterts.PopulateFuncMap(fm, esc)
+	filterpy.PopulateFuncMap(fm, esc)
+	filterue.PopulateFuncMap(fm, esc)
+	filterqt.PopulateFuncMap(fm, esc)
+	filterjs.PopulateFuncMap(fm, esc)
+	filterrs.PopulateFuncMap(fm, esc)
+	filterjava.PopulateFuncMap(fm, esc)
 	filterjni.PopulateFuncMap(fm)
+	filtercs.PopulateFuncMap(fm, esc)
+	filterswift.PopulateFuncMap(fm, esc)
 
 	return fm
 }
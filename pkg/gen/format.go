@@ -0,0 +1,121 @@
+package gen
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/apigear-io/cli/pkg/helper"
+	"github.com/apigear-io/cli/pkg/spec"
+	"github.com/goccy/go-yaml"
+)
+
+// Rendered documents can be formatted before they are compared with the
+// existing file and written. The format of a document rule overrides the
+// format of its feature. External formatter commands are only run when
+// enabled in the generator options, as they execute programs named by the template.
+
+// FormatCommandTimeout is the time an external formatter command may take
+const FormatCommandTimeout = 30 * time.Second
+
+// documentFormat returns the format of the document rule, falling back to the feature format
+func documentFormat(f *spec.FeatureRule, doc spec.DocumentRule) string {
+	if doc.Format != "" {
+		return doc.Format
+	}
+	return f.Format
+}
+
+// FormatDocument applies the built-in formatter to the rendered document
+func FormatDocument(name string, input []byte) ([]byte, error) {
+	switch name {
+	case "", spec.FormatNone:
+		return input, nil
+	case spec.FormatGo:
+		return format.Source(input)
+	case spec.FormatJSON:
+		return formatJSON(input)
+	case spec.FormatYAML:
+		return formatYAML(input)
+	}
+	return nil, fmt.Errorf("unknown format %s", name)
+}
+
+// formatDocument formats the rendered document of the target
+func (g *generator) formatDocument(name string, input []byte, target string) ([]byte, error) {
+	if !strings.HasPrefix(name, spec.FormatExecPrefix) {
+		return FormatDocument(name, input)
+	}
+	command := strings.TrimSpace(strings.TrimPrefix(name, spec.FormatExecPrefix))
+	if !g.opts.FormatCommands {
+		log.Warn().Msgf("skip format command %s for %s: format commands are not enabled", command, target)
+		return input, nil
+	}
+	return runFormatCommand(command, input, helper.Join(g.opts.OutputDir, target))
+}
+
+// formatJSON indents the json document using two spaces
+func formatJSON(input []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	err := json.Indent(&buf, bytes.TrimSpace(input), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// formatYAML re-encodes all yaml documents keeping the order of the keys.
+// Comments are not preserved.
+func formatYAML(input []byte) ([]byte, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(input), yaml.UseOrderedMap())
+	docs := [][]byte{}
+	for {
+		var v any
+		err := dec.Decode(&v)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		b, err := yaml.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, b)
+	}
+	return bytes.Join(docs, []byte("---\n")), nil
+}
+
+// runFormatCommand pipes the document through the command.
+// The command is split by spaces and run without a shell,
+// the target path is passed as APIGEAR_TARGET environment variable.
+func runFormatCommand(command string, input []byte, target string) ([]byte, error) {
+	args := strings.Fields(command)
+	ctx, cancel := context.WithTimeout(context.Background(), FormatCommandTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Env = append(os.Environ(), "APIGEAR_TARGET="+target)
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg != "" {
+			return nil, fmt.Errorf("format command %s: %w: %s", command, err, msg)
+		}
+		return nil, fmt.Errorf("format command %s: %w", command, err)
+	}
+	return stdout.Bytes(), nil
+}
@@ -2,10 +2,14 @@ package gen
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"text/template"
 	"time"
 
@@ -26,6 +30,8 @@ type GeneratorStats struct {
 	RunStart     time.Time     `json:"run_start"`
 	RunEnd       time.Time     `json:"run_end"`
 	Duration     time.Duration `json:"duration"`
+	// Documents are the results of the processed documents, sorted by target
+	Documents []DocumentResult `json:"documents"`
 }
 
 func (g *GeneratorStats) Start() {
@@ -35,6 +41,10 @@ func (g *GeneratorStats) Start() {
 func (g *GeneratorStats) Stop() {
 	g.RunEnd = time.Now()
 	g.Duration = g.RunEnd.Sub(g.RunStart).Truncate(time.Millisecond)
+	sort.Strings(g.FilesTouched)
+	sort.SliceStable(g.Documents, func(i, j int) bool {
+		return g.Documents[i].Target < g.Documents[j].Target
+	})
 	log.Info().Msgf("generated %d files in %s. (%d write, %d skip, %d copy)", g.TotalFiles(), g.Duration, g.FilesWritten, g.FilesSkipped, g.FilesCopied)
 }
 
@@ -47,6 +57,12 @@ type Options struct {
 	OutputDir string
 	// TemplatesDir is the directory where templates are located
 	TemplatesDir string
+	// Layers are the templates dirs of extended and included templates,
+	// which are parsed before the templates dir (see ComposeRules)
+	Layers []TemplateLayer
+	// Overlays are dirs whose templates folder shadows template files
+	// of the templates dir with the same relative path. Later overlays win.
+	Overlays []string
 	// System is the root system model
 	System *model.System
 	// Features is a list of features defined by user
@@ -59,6 +75,18 @@ type Options struct {
 	DryRun bool
 	// Meta is a map of metadata
 	Meta map[string]any
+	// Jobs is the number of documents rendered concurrently.
+	// Zero uses the number of CPUs.
+	Jobs int
+	// KeepGoing reports all failing documents instead of stopping at the first failure
+	KeepGoing bool
+	// Params are the template parameter values,
+	// which are validated against the params declared in the rules
+	Params map[string]any
+	// FormatCommands enables external formatter commands declared in the rules
+	FormatCommands bool
+	// Context is checked between documents, a canceled context stops the generation
+	Context context.Context
 }
 
 // generator applies template transformation on a set of files define in rules
@@ -67,6 +95,16 @@ type generator struct {
 	opts             Options
 	ComputedFeatures map[string]bool
 	Stats            GeneratorStats
+	// mu guards the stats while documents are rendered concurrently
+	mu   sync.Mutex
+	jobs []*documentJob
+	// errs collects the failures when keep going is enabled
+	errs []error
+	// params are the resolved template parameters
+	params map[string]any
+	// funcs are the built-in and script filters,
+	// also used for document targets and conditions
+	funcs template.FuncMap
 }
 
 func New(opts Options) (*generator, error) {
@@ -87,20 +125,53 @@ func New(opts Options) (*generator, error) {
 			FilesTouched: []string{},
 		},
 	}
-	g.Template.Funcs(filters.PopulateFuncMap())
-	err := g.ParseTemplatesDir(opts.TemplatesDir)
+	// script filters are loaded from the template dirs in precedence order
+	templateDirs := []string{}
+	for _, layer := range opts.Layers {
+		templateDirs = append(templateDirs, filepath.Dir(layer.Dir))
+	}
+	templateDirs = append(templateDirs, filepath.Dir(opts.TemplatesDir))
+	templateDirs = append(templateDirs, opts.Overlays...)
+	funcs, err := LoadFuncMap(templateDirs...)
+	if err != nil {
+		return nil, err
+	}
+	g.funcs = funcs
+	g.Template.Funcs(funcs)
+	err = ParseLayers(g.Template, opts.Layers, funcs)
 	if err != nil {
 		return nil, err
 	}
+	err = g.ParseTemplatesDir(opts.TemplatesDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, overlay := range opts.Overlays {
+		dir := helper.Join(overlay, "templates")
+		if !helper.IsDir(dir) {
+			continue
+		}
+		log.Debug().Msgf("parsing overlay templates dir: %s", dir)
+		// templates with the same name replace the base templates
+		err := g.ParseTemplatesDir(dir)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return g, nil
 }
 
 func (g *generator) ParseTemplate(path string) error {
+	return g.parseTemplate(g.opts.TemplatesDir, path)
+}
+
+// parseTemplate parses the template file named by its path relative to the dir
+func (g *generator) parseTemplate(dir string, path string) error {
 	b, err := os.ReadFile(path)
 	if err != nil {
 		return err
 	}
-	tplName, err := filepath.Rel(g.opts.TemplatesDir, path)
+	tplName, err := filepath.Rel(dir, path)
 	if err != nil {
 		return err
 	}
@@ -108,6 +179,8 @@ func (g *generator) ParseTemplate(path string) error {
 	return err
 }
 
+// ParseTemplatesDir parses all template files inside the dir.
+// Template names are relative to the dir.
 func (g *generator) ParseTemplatesDir(dir string) error {
 	log.Debug().Msgf("parsing templates dir: %s", dir)
 	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
@@ -125,7 +198,7 @@ func (g *generator) ParseTemplatesDir(dir string) error {
 		if !strings.HasSuffix(filepath.Base(path), ".tpl") {
 			return nil
 		}
-		return g.ParseTemplate(path)
+		return g.parseTemplate(dir, path)
 	})
 	if err != nil {
 		return err
@@ -151,7 +224,24 @@ func (g *generator) ProcessRules(doc *spec.RulesDoc) error {
 	if err != nil {
 		return err
 	}
+	g.params, err = doc.ResolveParams(g.opts.Params)
+	if err != nil {
+		return err
+	}
+	// the filters escape reserved words by the escaper of the rules,
+	// the system may be shared with other generators and is not modified
+	escaper, err := doc.Escape.Escaper()
+	if err != nil {
+		return err
+	}
+	escaped := filters.PopulateFuncMapWithEscaper(escaper)
+	for name, fn := range escaped {
+		g.funcs[name] = fn
+	}
+	g.Template.Funcs(escaped)
 	g.ComputedFeatures = doc.FeatureNamesMap()
+	g.jobs = nil
+	g.errs = nil
 	for _, f := range doc.Features {
 		if f.Skip {
 			continue
@@ -161,97 +251,51 @@ func (g *generator) ProcessRules(doc *spec.RulesDoc) error {
 			return err
 		}
 	}
+	if err := g.renderJobs(); err != nil {
+		g.errs = append(g.errs, err)
+	}
+	return errors.Join(g.errs...)
+}
+
+// collect records the error when keep going is enabled,
+// otherwise the error is returned to stop processing
+func (g *generator) collect(err error) error {
+	if err == nil || !g.opts.KeepGoing {
+		return err
+	}
+	g.errs = append(g.errs, err)
 	return nil
 }
 
 // processFeature processes a feature rule
 func (g *generator) processFeature(f *spec.FeatureRule) error {
 	log.Debug().Msgf("processing feature %s", f.Name)
-	// process system
-	ctx := model.SystemScope{
-		System:   g.opts.System,
-		Features: g.ComputedFeatures,
-		Meta:     g.opts.Meta,
-	}
-	scopes := f.FindScopesByMatch(spec.ScopeSystem)
-	for _, scope := range scopes {
-		err := g.processScope(scope, ctx)
+	return WalkScopes(g.opts.System, g.ComputedFeatures, g.opts.Meta, g.params, func(match spec.ScopeType, ctx any) error {
+		return g.processScopes(f, match, ctx)
+	})
+}
+
+// processScopes processes all scopes of the feature matching the given type with the given context
+func (g *generator) processScopes(f *spec.FeatureRule, match spec.ScopeType, ctx any) error {
+	for _, scope := range f.FindScopesByMatch(match) {
+		err := g.processScope(f, scope, ctx)
 		if err != nil {
 			return err
 		}
 	}
-	for _, module := range g.opts.System.Modules {
-		// process module
-		scopes := f.FindScopesByMatch(spec.ScopeModule)
-		ctx := model.ModuleScope{
-			System:   g.opts.System,
-			Module:   module,
-			Features: g.ComputedFeatures,
-			Meta:     g.opts.Meta,
-		}
-		for _, scope := range scopes {
-			err := g.processScope(scope, ctx)
-			if err != nil {
-				return err
-			}
-		}
-		for _, iface := range module.Interfaces {
-			// process interface
-			ctx := model.InterfaceScope{
-				System:    g.opts.System,
-				Module:    module,
-				Interface: iface,
-				Features:  g.ComputedFeatures,
-				Meta:      g.opts.Meta,
-			}
-			scopes := f.FindScopesByMatch(spec.ScopeInterface)
-			for _, scope := range scopes {
-				err := g.processScope(scope, ctx)
-				if err != nil {
-					return err
-				}
-			}
-		}
-		for _, struct_ := range module.Structs {
-			// process struct
-			ctx := model.StructScope{
-				System:   g.opts.System,
-				Module:   module,
-				Struct:   struct_,
-				Features: g.ComputedFeatures,
-				Meta:     g.opts.Meta,
-			}
-			scopes := f.FindScopesByMatch(spec.ScopeStruct)
-			for _, scope := range scopes {
-				err := g.processScope(scope, ctx)
-				if err != nil {
-					return err
-				}
-			}
-		}
-		for _, enum := range module.Enums {
-			// process enum
-			ctx := model.EnumScope{
-				System:   g.opts.System,
-				Module:   module,
-				Enum:     enum,
-				Features: g.ComputedFeatures,
-				Meta:     g.opts.Meta,
-			}
-			scopes := f.FindScopesByMatch(spec.ScopeEnum)
-			for _, scope := range scopes {
-				err := g.processScope(scope, ctx)
-				if err != nil {
-					return err
-				}
-			}
-		}
-	}
 	return nil
 }
 
 // processScope processes a scope rule (e.g. system, modules, ...) with the given context
-func (g *generator) processScope(scope *spec.ScopeRule, ctx any) error {
+func (g *generator) processScope(f *spec.FeatureRule, scope *spec.ScopeRule, ctx any) error {
+	ok, err := EvalConditionWithFuncs(scope.When, ctx, g.funcs)
+	if err != nil {
+		return g.collect(fmt.Errorf("eval scope condition %s (feature %s, %s %s): %w", scope.When, f.Name, scope.Match, scopeSymbol(ctx), err))
+	}
+	if !ok {
+		log.Debug().Msgf("skip scope %s: condition %s not met", scope.Match, scope.When)
+		return nil
+	}
 	prefix := scope.Prefix
 	for _, doc := range scope.Documents {
 		// clean doc target
@@ -262,49 +306,59 @@ func (g *generator) processScope(scope *spec.ScopeRule, ctx any) error {
 		if prefix != "" {
 			doc.Target = prefix + doc.Target
 		}
-		err := g.processDocument(doc, ctx)
-		if err != nil {
+		err := g.processDocument(f, scope.Match, doc, ctx)
+		if err := g.collect(err); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-// processDocument processes a document rule with the given context
-func (g *generator) processDocument(doc spec.DocumentRule, ctx any) error {
+// processDocument resolves the document target using the given context
+// and queues the document for rendering
+func (g *generator) processDocument(f *spec.FeatureRule, match spec.ScopeType, doc spec.DocumentRule, ctx any) error {
 	log.Debug().Msgf("processing document %s", doc.Source)
+	ok, err := EvalConditionWithFuncs(doc.When, ctx, g.funcs)
+	if err != nil {
+		return newRenderError(f.Name, match, ctx, doc.Source, doc.Target, fmt.Errorf("eval document condition %s: %s", doc.When, err))
+	}
+	if !ok {
+		log.Debug().Msgf("skip document %s: condition %s not met", doc.Source, doc.When)
+		return nil
+	}
 	// the source file to render
 	var source = filepath.Clean(doc.Source)
 	// the docTarget destination file
 	var docTarget = filepath.Clean(doc.Target)
 	// either user can force an overwrite or the target or the rules document
 	// transform the target name using the context
-	target, err := RenderString(docTarget, ctx)
+	target, err := RenderStringWithFuncs(docTarget, ctx, g.funcs)
 	if err != nil {
-		return fmt.Errorf("render rules target %s: %s", docTarget, err)
-	}
-	if doc.Raw {
-		// copy the source to the target
-		err := g.CopyFile(source, target)
-		if err != nil {
-			log.Warn().Msgf("copy file %s to %s: %s", source, target, err)
-			return err
-		}
-	} else {
-		// render the source file to the target
-		err := g.RenderFile(source, target, ctx, doc.Preserve)
-		if err != nil {
-			return err
-		}
+		return newRenderError(f.Name, match, ctx, doc.Source, docTarget, fmt.Errorf("render rules target %s: %s", docTarget, err))
 	}
+	g.jobs = append(g.jobs, &documentJob{
+		feature:  f.Name,
+		match:    match,
+		source:   source,
+		target:   target,
+		ctx:      ctx,
+		raw:      doc.Raw,
+		preserve: doc.Preserve,
+		format:   documentFormat(f, doc),
+	})
 	return nil
 }
 
 // Renders a string using the given context
 func RenderString(s string, ctx any) (string, error) {
+	return RenderStringWithFuncs(s, ctx, filters.PopulateFuncMap())
+}
+
+// RenderStringWithFuncs renders a string using the given context and filters
+func RenderStringWithFuncs(s string, ctx any, funcs template.FuncMap) (string, error) {
 	var buf = bytes.NewBuffer(nil)
 	t := template.New("target")
-	t.Funcs(filters.PopulateFuncMap())
+	t.Funcs(funcs)
 	_, err := t.Parse(s)
 	if err != nil {
 		log.Warn().Msgf("render string: %s: %s", s, err)
@@ -318,72 +372,130 @@ func RenderString(s string, ctx any) (string, error) {
 	return buf.String(), nil
 }
 
+// CopyFile copies the raw source document to the target
 func (g *generator) CopyFile(source, target string) error {
+	g.mu.Lock()
 	g.Stats.FilesCopied++
+	g.mu.Unlock()
 	if g.opts.DryRun {
 		log.Info().Msgf("dry run: copying file %s to %s", source, target)
+		g.mu.Lock()
 		g.Stats.FilesTouched = append(g.Stats.FilesTouched, target)
+		g.mu.Unlock()
 		return nil
 	}
-	source = helper.Join(g.opts.TemplatesDir, source)
+	source = g.sourcePath(source)
 	target = helper.Join(g.opts.OutputDir, target)
 	return g.opts.Output.Copy(source, target)
 }
 
-func (g *generator) RenderFile(source, target string, ctx any, preserve bool) error {
-	// var force = doc.Force
-	// var transform = doc.Transform
+// sourcePath returns the path of a raw document source,
+// searching the overlays, the templates dir and the layers by precedence
+func (g *generator) sourcePath(source string) string {
+	for i := len(g.opts.Overlays) - 1; i >= 0; i-- {
+		path := helper.Join(g.opts.Overlays[i], "templates", source)
+		if helper.IsFile(path) {
+			return path
+		}
+	}
+	path := helper.Join(g.opts.TemplatesDir, source)
+	if helper.IsFile(path) {
+		return path
+	}
+	for i := len(g.opts.Layers) - 1; i >= 0; i-- {
+		layer := g.opts.Layers[i]
+		if !strings.HasPrefix(source, layer.Namespace) {
+			continue
+		}
+		layerPath := helper.Join(layer.Dir, strings.TrimPrefix(source, layer.Namespace))
+		if helper.IsFile(layerPath) {
+			return layerPath
+		}
+	}
+	return path
+}
+
+// RenderFile renders the source template using the context and
+// writes the document formatted by the given formatter to the target
+func (g *generator) RenderFile(source, target string, ctx any, preserve bool, format string) error {
+	_, _, err := g.renderFile(source, target, ctx, preserve, format)
+	return err
+}
+
+// renderFile renders the document and returns the action taken and its reason
+func (g *generator) renderFile(source, target string, ctx any, preserve bool, format string) (DocumentAction, string, error) {
 	log.Debug().Msgf("render %s -> %s", source, target)
 	// render the template using the context
 	buf := bytes.NewBuffer(nil)
 	err := g.Template.ExecuteTemplate(buf, source, ctx)
 	if err != nil {
 		log.Warn().Msgf("exec template %s: %s", source, err)
-		return fmt.Errorf("render template %s: %w", source, err)
+		return DocumentFailed, "", fmt.Errorf("render template %s: %w", source, err)
+	}
+	// format before writing, so unchanged documents are still skipped
+	output, err := g.formatDocument(format, buf.Bytes(), target)
+	if err != nil {
+		log.Warn().Msgf("format %s: %s", target, err)
+		return DocumentFailed, "", fmt.Errorf("format %s: %w", target, err)
 	}
 	// write the file
 	log.Debug().Msgf("write %s", target)
-	err = g.WriteFile(buf.Bytes(), target, preserve)
+	action, reason, err := g.writeFile(output, target, preserve)
 	if err != nil {
 		log.Warn().Msgf("write file %s: %s", target, err)
-		return fmt.Errorf("write file %s: %w", target, err)
+		return DocumentFailed, "", fmt.Errorf("write file %s: %w", target, err)
 	}
-	return nil
+	return action, reason, nil
 }
 
+// WriteFile writes the document to the target unless it is preserved or unchanged
 func (g *generator) WriteFile(input []byte, target string, preserve bool) error {
+	_, _, err := g.writeFile(input, target, preserve)
+	return err
+}
+
+// writeFile writes the document and returns the action taken and its reason
+func (g *generator) writeFile(input []byte, target string, preserve bool) (DocumentAction, string, error) {
 	target = helper.Join(g.opts.OutputDir, target)
 	if g.opts.Force {
-		return g.WriteToOutput(input, target)
+		return DocumentWritten, ReasonForce, g.WriteToOutput(input, target)
 	}
 	log.Info().Msgf("write file %s", target)
 
-	if helper.IsFile(target) {
-		if preserve {
-			g.SkipFile(target, "preserve")
-			return nil
-		}
-		isSame, err := g.opts.Output.Compare(input, target)
-		if err != nil {
-			return err
-		}
-		if isSame {
-			g.SkipFile(target, "same content")
-			return nil
-		}
+	if !g.opts.Output.Exists(target) {
+		return DocumentWritten, ReasonNew, g.WriteToOutput(input, target)
 	}
-	return g.WriteToOutput(input, target)
+	if preserve {
+		g.SkipFile(target, ReasonPreserve)
+		return DocumentSkipped, ReasonPreserve, nil
+	}
+	isSame, err := g.opts.Output.Compare(input, target)
+	if err != nil {
+		return DocumentFailed, "", err
+	}
+	if isSame {
+		g.SkipFile(target, ReasonSameContent)
+		return DocumentSkipped, ReasonSameContent, nil
+	}
+	return DocumentWritten, ReasonChanged, g.WriteToOutput(input, target)
 }
 
 func (g *generator) SkipFile(target string, reason string) {
 	log.Debug().Msgf("skip %s: %s", target, reason)
+	if r, ok := g.opts.Output.(SkipRecorder); ok {
+		r.Skip(target)
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
 	g.Stats.FilesSkipped++
 }
 
 func (g *generator) WriteToOutput(input []byte, target string) error {
 	log.Debug().Msgf("write file %s", target)
+	g.mu.Lock()
 	g.Stats.FilesTouched = append(g.Stats.FilesTouched, target)
 	g.Stats.FilesWritten++
+	g.mu.Unlock()
 	if g.opts.DryRun {
 		log.Info().Msgf("dry run: writing file %s", target)
 		return nil
@@ -1,10 +1,16 @@
 package gen
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/apigear-io/cli/pkg/helper"
+	"github.com/apigear-io/cli/pkg/idl"
 	"github.com/apigear-io/cli/pkg/model"
 	"github.com/apigear-io/cli/pkg/spec"
 
@@ -117,3 +123,425 @@ func TestDocumentPreserve(t *testing.T) {
 		})
 	}
 }
+
+func TestDocumentResults(t *testing.T) {
+	t.Parallel()
+	g := createGenerator(t)
+	r := readRules(t, "testdata/test-preserve.rules.yaml")
+	actions := func() []string {
+		result := []string{}
+		for _, doc := range g.Stats.Documents {
+			result = append(result, fmt.Sprintf("%s %s (%s)", doc.Target, doc.Action, doc.Reason))
+		}
+		return result
+	}
+	require.NoError(t, g.ProcessRules(r))
+	require.Equal(t, []string{"system-preserve.txt written (new)", "system.txt written (new)"}, actions())
+	require.Equal(t, "system.name.tpl", g.Stats.Documents[0].Source)
+	require.Equal(t, "core", g.Stats.Documents[0].Feature)
+	require.NoError(t, os.WriteFile(filepath.Join(g.opts.OutputDir, "system.txt"), []byte("changed"), 0644))
+	require.NoError(t, g.ProcessRules(r))
+	require.Equal(t, []string{"system-preserve.txt skipped (preserve)", "system.txt written (changed)"}, actions())
+	require.NoError(t, g.ProcessRules(r))
+	require.Equal(t, []string{"system-preserve.txt skipped (preserve)", "system.txt skipped (same content)"}, actions())
+	g.opts.Force = true
+	require.NoError(t, g.ProcessRules(r))
+	require.Equal(t, []string{"system-preserve.txt written (force)", "system.txt written (force)"}, actions())
+}
+
+func TestWhenCondition(t *testing.T) {
+	t.Parallel()
+	sys := model.NewSystem("test")
+	mod := model.NewModule("demo", "1.0")
+	remote := model.NewInterface("Remote")
+	remote.Meta = model.Meta{"remote": true}
+	mod.Interfaces = append(mod.Interfaces, remote, model.NewInterface("Local"))
+	internal := model.NewStruct("Internal")
+	internal.Meta = model.Meta{"internal": true}
+	mod.Structs = append(mod.Structs, internal, model.NewStruct("Public"))
+	sys.AddModule(mod)
+	out := NewMockOutput()
+	g, err := New(Options{
+		System:       sys,
+		Force:        true,
+		TemplatesDir: "testdata/templates",
+		OutputDir:    "testdata/output",
+		Output:       out,
+	})
+	require.NoError(t, err)
+	err = g.ProcessRules(readRules(t, "testdata/test-when.rules.yaml"))
+	require.NoError(t, err)
+	var files []string
+	for target := range out.Writes {
+		files = append(files, filepath.Base(target))
+	}
+	require.ElementsMatch(t, []string{
+		"Remote.remote.txt", "Remote.txt", "Local.txt", "Local.local.txt", "Public.txt",
+	}, files)
+}
+
+func TestEvalCondition(t *testing.T) {
+	t.Parallel()
+	ctx := model.InterfaceScope{Interface: model.NewInterface("Demo")}
+	ctx.Interface.Meta = model.Meta{"remote": true, "kind": "rpc"}
+	tt := []struct {
+		cond string
+		want bool
+	}{
+		{"", true},
+		{".Interface.Meta.remote", true},
+		{"not .Interface.Meta.remote", false},
+		{".Interface.Meta.missing", false},
+		{`eq .Interface.Meta.kind "rpc"`, true},
+		{`{{ .Interface.Meta.remote }}`, true},
+		{`{{ .Interface.Meta.missing }}`, false},
+	}
+	for _, tr := range tt {
+		ok, err := EvalCondition(tr.cond, ctx)
+		require.NoError(t, err, tr.cond)
+		require.Equal(t, tr.want, ok, tr.cond)
+	}
+	_, err := EvalCondition(".Interface.Unknown", ctx)
+	require.Error(t, err)
+}
+
+func TestMemberScopes(t *testing.T) {
+	t.Parallel()
+	sys, err := idl.LoadIdlFromFiles("test", []string{"testdata/members.idl"})
+	require.NoError(t, err)
+	require.NoError(t, sys.Validate())
+	out := NewMockOutput()
+	g, err := New(Options{
+		System:       sys,
+		Force:        true,
+		TemplatesDir: "testdata/templates",
+		OutputDir:    "testdata/output",
+		Output:       out,
+	})
+	require.NoError(t, err)
+	err = g.ProcessRules(readRules(t, "testdata/test-members.rules.yaml"))
+	require.NoError(t, err)
+	want := map[string]string{
+		"Counter/op_increment.txt": "increment",
+		"Counter/op_reset.txt":     "reset",
+		"Counter/remote_reset.txt": "reset",
+		"prop_count.txt":           "count",
+		"sig_changed.txt":          "changed",
+		"ext_XType.txt":            "XType",
+	}
+	require.Len(t, out.Writes, len(want))
+	for file, content := range want {
+		target := helper.Join("testdata", "output", file)
+		require.Equal(t, content, out.Writes[target], file)
+	}
+}
+
+func TestEscapeReservedWords(t *testing.T) {
+	t.Parallel()
+	// the system is shared by all generators
+	sys, err := idl.LoadIdlFromFiles("test", []string{"testdata/reserved.idl"})
+	require.NoError(t, err)
+	require.NoError(t, sys.Validate())
+	render := func(doc *spec.RulesDoc) (map[string]string, error) {
+		out := NewMockOutput()
+		g, err := New(Options{
+			System:       sys,
+			Force:        true,
+			TemplatesDir: "testdata/templates",
+			OutputDir:    "testdata/output",
+			Output:       out,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return out.Writes, g.ProcessRules(doc)
+	}
+	run := func(rules string) map[string]string {
+		writes, err := render(readRules(t, rules))
+		require.NoError(t, err)
+		return writes
+	}
+	native := strings.Join([]string{
+		"",
+		"self_: i32 | int self | self: int | `self`: Int",
+		"object: i32 | int @object | object: int | object: Int",
+		"r#in: i32 | int @in | in_: int | `in`: Int",
+		"",
+	}, "\n")
+	writes := run("testdata/test-escape.rules.yaml")
+	require.Equal(t, native, writes[helper.Join("testdata", "output", "params.txt")])
+	// after an accessor prefix the name is escaped, otherwise the whole identifier
+	want := strings.Join([]string{
+		"",
+		"self.self_ | m_self | InSelf | int32 InSelf",
+		"self.object | m_object | InObject | int32 InObject",
+		"self.r#in | m_in | InIn | int32 InIn",
+		"",
+	}, "\n")
+	require.Equal(t, want, writes[helper.Join("testdata", "output", "vars.txt")])
+	suffix := strings.Join([]string{
+		"",
+		"self_arg: i32 | int self | self: int | self_arg: Int",
+		"object: i32 | int @object | object: int | object: Int",
+		"in_arg: i32 | int @in | in_arg: int | in_arg: Int",
+		"",
+	}, "\n")
+	writes = run("testdata/test-escape-suffix.rules.yaml")
+	require.Equal(t, suffix, writes[helper.Join("testdata", "output", "params.txt")])
+	want = strings.Join([]string{
+		"",
+		"self.self_arg | m_self | InSelf | int32 InSelf",
+		"self.object | m_object | InObject | int32 InObject",
+		"self.in_arg | m_in | InIn | int32 InIn",
+		"",
+	}, "\n")
+	require.Equal(t, want, writes[helper.Join("testdata", "output", "vars.txt")])
+	// the escaping of one generator does not leak into the others
+	docs := []*spec.RulesDoc{}
+	for i := 0; i < 8; i++ {
+		docs = append(docs, readRules(t, "testdata/test-escape.rules.yaml"), readRules(t, "testdata/test-escape-suffix.rules.yaml"))
+	}
+	results := make([]map[string]string, len(docs))
+	errs := make([]error, len(docs))
+	var wg sync.WaitGroup
+	for i, doc := range docs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = render(doc)
+		}()
+	}
+	wg.Wait()
+	for i := range docs {
+		require.NoError(t, errs[i])
+		want := native
+		if i%2 == 1 {
+			want = suffix
+		}
+		require.Equal(t, want, results[i][helper.Join("testdata", "output", "params.txt")])
+	}
+}
+
+func TestParallelRendering(t *testing.T) {
+	t.Parallel()
+	sys := model.NewSystem("test")
+	mod := model.NewModule("demo", "1.0")
+	for i := 0; i < 50; i++ {
+		mod.Interfaces = append(mod.Interfaces, model.NewInterface(fmt.Sprintf("Iface%02d", i)))
+	}
+	sys.AddModule(mod)
+	run := func(jobs int) (*generator, *MockOutput) {
+		out := NewMockOutput()
+		g, err := New(Options{
+			System:       sys,
+			Force:        true,
+			TemplatesDir: "testdata/templates",
+			OutputDir:    "testdata/output",
+			Output:       out,
+			Jobs:         jobs,
+		})
+		require.NoError(t, err)
+		err = g.ProcessRules(readRules(t, "testdata/test-overwrite.rules.yaml"))
+		require.NoError(t, err)
+		return g, out
+	}
+	g1, out1 := run(1)
+	g8, out8 := run(8)
+	require.Len(t, out8.Writes, 50)
+	require.Equal(t, out1.Writes, out8.Writes)
+	require.Equal(t, g1.Stats.FilesTouched, g8.Stats.FilesTouched)
+	require.Equal(t, 100, g8.Stats.FilesWritten)
+	// the last document rule for a target wins
+	for _, content := range out8.Writes {
+		require.Equal(t, "demo", content)
+	}
+}
+
+func TestCanceledRendering(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, keepGoing := range []bool{false, true} {
+		out := NewMockOutput()
+		g, err := New(Options{
+			System:       model.NewSystem("test"),
+			Force:        true,
+			TemplatesDir: "testdata/templates",
+			OutputDir:    "testdata/output",
+			Output:       out,
+			KeepGoing:    keepGoing,
+			Context:      ctx,
+		})
+		require.NoError(t, err)
+		err = g.ProcessRules(readRules(t, "testdata/test.rules.yaml"))
+		require.ErrorIs(t, err, context.Canceled)
+		require.Empty(t, out.Writes)
+	}
+}
+
+func TestRenderErrorContext(t *testing.T) {
+	t.Parallel()
+	sys, err := idl.LoadIdlFromFiles("test", []string{"testdata/members.idl"})
+	require.NoError(t, err)
+	require.NoError(t, sys.Validate())
+	run := func(keepGoing bool) (error, *MockOutput) {
+		out := NewMockOutput()
+		g, err := New(Options{
+			System:       sys,
+			Force:        true,
+			TemplatesDir: "testdata/templates",
+			OutputDir:    "testdata/output",
+			Output:       out,
+			Jobs:         1,
+			KeepGoing:    keepGoing,
+		})
+		require.NoError(t, err)
+		return g.ProcessRules(readRules(t, "testdata/test-errors.rules.yaml")), out
+	}
+	// stops at the first failure
+	err, _ = run(false)
+	require.Error(t, err)
+	var rerr *RenderError
+	require.ErrorAs(t, err, &rerr)
+	require.Equal(t, "broken", rerr.Feature)
+	require.Equal(t, spec.ScopeProperty, rerr.Match)
+	require.Equal(t, "demo.Counter.count", rerr.Symbol)
+	require.Contains(t, err.Error(), "render rules target {{.Property.Nope}}.txt")
+	// keep going reports all failures and renders the rest
+	err, out := run(true)
+	require.Error(t, err)
+	msg := err.Error()
+	require.Contains(t, msg, "broken.field.tpl:2:13: executing \"broken.field.tpl\" at <.Operation.Nope>")
+	require.Contains(t, msg, "(feature broken, operation demo.Counter.increment, document broken.field.tpl -> Counter_increment.txt)")
+	require.Contains(t, msg, "(feature broken, operation demo.Counter.reset, document broken.field.tpl -> Counter_reset.txt)")
+	require.Contains(t, msg, "(feature broken, property demo.Counter.count, document property.name.tpl -> {{.Property.Nope}}.txt)")
+	require.Equal(t, "changed", out.Writes[helper.Join("testdata", "output", "sig_changed.txt")])
+}
+
+func TestOverlays(t *testing.T) {
+	t.Parallel()
+	out := NewMockOutput()
+	g, err := New(Options{
+		System:       model.NewSystem("test"),
+		Force:        true,
+		TemplatesDir: "testdata/templates",
+		Overlays:     []string{"testdata/overlay"},
+		OutputDir:    "testdata/output",
+		Output:       out,
+	})
+	require.NoError(t, err)
+	rules, err := ReadRulesDoc("testdata/test.rules.yaml")
+	require.NoError(t, err)
+	err = mergeOverlayRules(rules, []string{"testdata/overlay"})
+	require.NoError(t, err)
+	require.Len(t, rules.Features, 2)
+	err = g.ProcessRules(rules)
+	require.NoError(t, err)
+	// the overlay template shadows the base template
+	require.Equal(t, "overlay system: test\n", out.Writes[helper.Join("testdata", "output", "system.txt")])
+	// raw documents are copied from the overlay
+	require.Equal(t, helper.Join("testdata", "output", "extra.txt"), out.Copies[helper.Join("testdata", "overlay", "templates", "extra.txt")])
+}
+
+func TestComposeRules(t *testing.T) {
+	t.Parallel()
+	composed, err := ComposeRules("testdata/compose/child", nil)
+	require.NoError(t, err)
+	require.Equal(t, "child", composed.Doc.Name)
+	require.Equal(t, []string{"cpp"}, composed.Doc.Languages)
+	names := []string{}
+	for _, f := range composed.Doc.Features {
+		names = append(names, f.Name)
+	}
+	require.Equal(t, []string{"api", "docs", "common/license"}, names)
+	require.Equal(t, []TemplateLayer{
+		{Dir: helper.Join("testdata", "compose", "base", "templates")},
+		{Dir: helper.Join("testdata", "compose", "common", "templates"), Namespace: "common/"},
+	}, composed.Layers)
+	out := NewMockOutput()
+	g, err := New(Options{
+		System:       model.NewSystem("test"),
+		Force:        true,
+		TemplatesDir: "testdata/compose/child/templates",
+		Layers:       composed.Layers,
+		OutputDir:    "testdata/output",
+		Output:       out,
+	})
+	require.NoError(t, err)
+	err = g.ProcessRules(composed.Doc)
+	require.NoError(t, err)
+	output := func(name string) string {
+		return out.Writes[helper.Join("testdata", "output", name)]
+	}
+	// the template takes precedence over the extended template
+	require.Equal(t, "child api\n", output("api.txt"))
+	require.Equal(t, "child header docs\n", output("docs.txt"))
+	// included templates are namespaced
+	require.Equal(t, "common notice\n", output("LICENSE"))
+	require.Equal(t, helper.Join("testdata", "output", "NOTICE"), out.Copies[helper.Join("testdata", "compose", "common", "templates", "NOTICE")])
+}
+
+func TestComposeRulesCycle(t *testing.T) {
+	t.Parallel()
+	_, err := ComposeRules("testdata/compose/cycle-a", nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "template composition cycle")
+}
+
+func TestParams(t *testing.T) {
+	t.Parallel()
+	run := func(params map[string]any) (*MockOutput, error) {
+		out := NewMockOutput()
+		g, err := New(Options{
+			System:       model.NewSystem("test"),
+			Force:        true,
+			TemplatesDir: "testdata/templates",
+			OutputDir:    "testdata/output",
+			Output:       out,
+			Params:       params,
+		})
+		require.NoError(t, err)
+		return out, g.ProcessRules(readRules(t, "testdata/test-params.rules.yaml"))
+	}
+	out, err := run(nil)
+	require.NoError(t, err)
+	require.Equal(t, "demo:8080:", out.Writes[helper.Join("testdata", "output", "demo.txt")])
+	out, err = run(map[string]any{"namespace": "acme", "port": "9000", "tests": true})
+	require.NoError(t, err)
+	require.Equal(t, "acme:9000:tests", out.Writes[helper.Join("testdata", "output", "acme.txt")])
+	_, err = run(map[string]any{"namespaces": "acme"})
+	require.ErrorContains(t, err, "unknown template param namespaces")
+}
+
+func TestFormatDocuments(t *testing.T) {
+	t.Parallel()
+	outDir := t.TempDir()
+	run := func(formatCommands bool) (*generator, error) {
+		g, err := New(Options{
+			System:         model.NewSystem("demo"),
+			TemplatesDir:   "testdata/templates",
+			OutputDir:      outDir,
+			FormatCommands: formatCommands,
+		})
+		require.NoError(t, err)
+		return g, g.ProcessRules(readRules(t, "testdata/test-format.rules.yaml"))
+	}
+	read := func(name string) string {
+		b, err := os.ReadFile(filepath.Join(outDir, name))
+		require.NoError(t, err)
+		return string(b)
+	}
+	g, err := run(true)
+	require.NoError(t, err)
+	require.Equal(t, 5, g.Stats.FilesWritten)
+	require.Equal(t, "package demo\n\nfunc Hello() string { return \"hello\" }\n", read("format.go"))
+	require.Equal(t, "{\n  \"name\": \"demo\",\n  \"tags\": [\n    \"a\",\n    \"b\"\n  ]\n}\n", read("format.json"))
+	require.Equal(t, "name: demo\ntags:\n- a\n- b\n", read("format.yaml"))
+	require.Equal(t, "NAME:   DEMO\nTAGS: [A,   B]\n", read("format.txt"))
+	require.Equal(t, "{\"name\":\"demo\",\"tags\":[\"a\",\"b\"]}\n", read("raw.json"))
+	// formatted documents are unchanged on the next run, disabled commands keep the document
+	g, err = run(false)
+	require.NoError(t, err)
+	require.Equal(t, 4, g.Stats.FilesSkipped)
+	require.Equal(t, "name:   demo\ntags: [a,   b]\n", read("format.txt"))
+}
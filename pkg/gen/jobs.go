@@ -0,0 +1,162 @@
+package gen
+
+import (
+	"errors"
+	"time"
+
+	"github.com/apigear-io/cli/pkg/helper"
+	"github.com/apigear-io/cli/pkg/spec"
+)
+
+// documentJob is a document with a resolved target waiting to be rendered
+type documentJob struct {
+	// feature and match identify the document rule
+	feature  string
+	match    spec.ScopeType
+	source   string
+	target   string
+	ctx      any
+	raw      bool
+	preserve bool
+	// format is the formatter applied to the rendered document
+	format string
+}
+
+// DocumentAction is the outcome of a document.
+// The generator never removes files, so there is no pruned action.
+type DocumentAction string
+
+const (
+	DocumentWritten DocumentAction = "written"
+	DocumentSkipped DocumentAction = "skipped"
+	DocumentCopied  DocumentAction = "copied"
+	DocumentFailed  DocumentAction = "failed"
+)
+
+// Reasons of a document action
+const (
+	// ReasonNew is a written document whose target did not exist
+	ReasonNew = "new"
+	// ReasonChanged is a written document whose content changed
+	ReasonChanged = "changed"
+	// ReasonForce is a document written because of the force option
+	ReasonForce = "force"
+	// ReasonPreserve is an existing document preserved by the rules
+	ReasonPreserve = "preserve"
+	// ReasonSameContent is a document skipped as its content did not change
+	ReasonSameContent = "same content"
+	// ReasonRaw is a raw document copied without rendering
+	ReasonRaw = "raw"
+)
+
+// DocumentResult is the outcome of a single document
+type DocumentResult struct {
+	Feature string `json:"feature"`
+	// Source is the template of the document
+	Source string `json:"source"`
+	// Target is the document path relative to the output dir
+	Target   string         `json:"target"`
+	Action   DocumentAction `json:"action"`
+	Reason   string         `json:"reason"`
+	Duration time.Duration  `json:"duration"`
+	Error    string         `json:"error,omitempty"`
+}
+
+// groupJobsByTarget groups the jobs by target keeping the order of first appearance.
+// Jobs writing to the same target stay in rules order inside their group.
+func groupJobsByTarget(jobs []*documentJob) [][]*documentJob {
+	index := map[string]int{}
+	groups := [][]*documentJob{}
+	for _, job := range jobs {
+		i, ok := index[job.target]
+		if !ok {
+			i = len(groups)
+			index[job.target] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], job)
+	}
+	return groups
+}
+
+// renderJobs renders the queued documents using a bounded worker pool.
+// Documents with the same target are rendered sequentially by the same worker,
+// so the last document rule wins as in a sequential run.
+func (g *generator) renderJobs() error {
+	groups := groupJobsByTarget(g.jobs)
+	g.jobs = nil
+	if g.opts.KeepGoing {
+		// render all documents and report every failure
+		err := helper.RunParallelAll(len(groups), g.opts.Jobs, func(i int) error {
+			var errs []error
+			for _, job := range groups[i] {
+				if g.canceled() != nil {
+					break
+				}
+				errs = append(errs, g.renderJob(job))
+			}
+			return errors.Join(errs...)
+		})
+		// the cancellation is reported once
+		return errors.Join(err, g.canceled())
+	}
+	return helper.RunParallel(len(groups), g.opts.Jobs, func(i int) error {
+		for _, job := range groups[i] {
+			if err := g.canceled(); err != nil {
+				return err
+			}
+			err := g.renderJob(job)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// canceled returns the error of the canceled context of the options
+func (g *generator) canceled() error {
+	if g.opts.Context == nil {
+		return nil
+	}
+	return g.opts.Context.Err()
+}
+
+// renderJob copies or renders a single document and records its result.
+// Failures are reported with the model context of the document.
+func (g *generator) renderJob(job *documentJob) error {
+	start := time.Now()
+	action, reason, err := g.copyOrRender(job)
+	result := DocumentResult{
+		Feature:  job.feature,
+		Source:   job.source,
+		Target:   job.target,
+		Action:   action,
+		Reason:   reason,
+		Duration: time.Since(start),
+	}
+	if err != nil {
+		err = newRenderError(job.feature, job.match, job.ctx, job.source, job.target, err)
+		result.Action = DocumentFailed
+		result.Error = err.Error()
+	}
+	g.mu.Lock()
+	g.Stats.Documents = append(g.Stats.Documents, result)
+	g.mu.Unlock()
+	return err
+}
+
+// copyOrRender copies raw documents and renders template documents
+func (g *generator) copyOrRender(job *documentJob) (DocumentAction, string, error) {
+	if job.raw {
+		// copy the source to the target
+		err := g.CopyFile(job.source, job.target)
+		if err != nil {
+			log.Warn().Msgf("copy file %s to %s: %s", job.source, job.target, err)
+			return DocumentFailed, "", err
+		}
+		return DocumentCopied, ReasonRaw, nil
+	}
+	// render the source file to the target
+	return g.renderFile(job.source, job.target, job.ctx, job.preserve, job.format)
+}
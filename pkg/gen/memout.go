@@ -0,0 +1,121 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing/fstest"
+)
+
+// MemoryWriter is an output writer which keeps all generated files in memory.
+// File names are relative to the base dir and use forward slashes.
+type MemoryWriter struct {
+	mu      sync.RWMutex
+	baseDir string
+	files   map[string][]byte
+}
+
+var _ OutputWriter = (*MemoryWriter)(nil)
+
+// NewMemoryWriter creates a new in-memory output writer.
+func NewMemoryWriter(baseDir string) *MemoryWriter {
+	return &MemoryWriter{
+		baseDir: baseDir,
+		files:   make(map[string][]byte),
+	}
+}
+
+func (m *MemoryWriter) Write(input []byte, target string) error {
+	name, err := m.name(target)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files[name] = bytes.Clone(input)
+	return nil
+}
+
+func (m *MemoryWriter) Copy(source, target string) error {
+	input, err := os.ReadFile(source)
+	if err != nil {
+		return err
+	}
+	return m.Write(input, target)
+}
+
+func (m *MemoryWriter) Compare(input []byte, target string) (bool, error) {
+	name, err := m.name(target)
+	if err != nil {
+		return false, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	content, ok := m.files[name]
+	return ok && bytes.Equal(content, input), nil
+}
+
+func (m *MemoryWriter) Exists(target string) bool {
+	name, err := m.name(target)
+	if err != nil {
+		return false
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.files[name]
+	return ok
+}
+
+// Names returns the sorted names of all generated files
+func (m *MemoryWriter) Names() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	names := make([]string, 0, len(m.files))
+	for name := range m.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ReadFile returns the content of the generated file with the given name
+func (m *MemoryWriter) ReadFile(name string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	content, ok := m.files[name]
+	if !ok {
+		return nil, fmt.Errorf("read %s: %w", name, fs.ErrNotExist)
+	}
+	return bytes.Clone(content), nil
+}
+
+// FS returns a read-only snapshot of the generated files as file system
+func (m *MemoryWriter) FS() fs.FS {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	fsys := fstest.MapFS{}
+	for name, content := range m.files {
+		fsys[name] = &fstest.MapFile{Data: bytes.Clone(content), Mode: 0644}
+	}
+	return fsys
+}
+
+// name returns the file name of the target relative to the base dir.
+// Targets outside the base dir are rejected, as their names would
+// escape the extraction dir of an archive.
+func (m *MemoryWriter) name(target string) (string, error) {
+	rel, err := filepath.Rel(m.baseDir, target)
+	if err != nil {
+		return "", fmt.Errorf("target %s is outside of %s", target, m.baseDir)
+	}
+	rel = filepath.Clean(rel)
+	if filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("target %s is outside of %s", target, m.baseDir)
+	}
+	return filepath.ToSlash(rel), nil
+}
@@ -3,14 +3,24 @@ package gen
 import (
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/apigear-io/cli/pkg/helper"
 )
 
+// OutputWriter writes the generated documents.
+// Documents are rendered concurrently, so implementations must be safe for concurrent use.
 type OutputWriter interface {
 	Write(input []byte, target string) error
 	Copy(source, target string) error
 	Compare(input []byte, target string) (bool, error)
+	Exists(target string) bool
+}
+
+// SkipRecorder is implemented by output writers which need to know
+// about targets skipped by the generator (e.g. preserved files)
+type SkipRecorder interface {
+	Skip(target string)
 }
 
 type fsWriter struct {
@@ -41,7 +51,12 @@ func (f *fsWriter) Compare(input []byte, target string) (bool, error) {
 	return CompareContentWithFile(input, target)
 }
 
+func (f *fsWriter) Exists(target string) bool {
+	return helper.IsFile(target)
+}
+
 type MockOutput struct {
+	mu       sync.Mutex
 	Writes   map[string]string
 	Copies   map[string]string
 	Compares map[string]bool
@@ -58,15 +73,25 @@ func NewMockOutput() *MockOutput {
 }
 
 func (m *MockOutput) Write(input []byte, target string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.Writes[target] = string(input)
 	return nil
 }
 
 func (m *MockOutput) Copy(source, target string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.Copies[source] = target
 	return nil
 }
 
 func (m *MockOutput) Compare(input []byte, target string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	return m.Compares[target], nil
 }
+
+func (m *MockOutput) Exists(target string) bool {
+	return helper.IsFile(target)
+}
@@ -0,0 +1,125 @@
+package gen
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/apigear-io/cli/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+func generateInto(t *testing.T, out OutputWriter, outDir string) {
+	t.Helper()
+	g, err := New(Options{
+		System:       model.NewSystem("test"),
+		TemplatesDir: "testdata/templates",
+		OutputDir:    outDir,
+		Output:       out,
+	})
+	require.NoError(t, err)
+	err = g.ProcessRules(readRules(t, "testdata/test-preserve.rules.yaml"))
+	require.NoError(t, err)
+}
+
+func TestMemoryWriter(t *testing.T) {
+	t.Parallel()
+	outDir := t.TempDir()
+	out := NewMemoryWriter(outDir)
+	generateInto(t, out, outDir)
+	require.Equal(t, []string{"system-preserve.txt", "system.txt"}, out.Names())
+	content, err := out.ReadFile("system.txt")
+	require.NoError(t, err)
+	require.Equal(t, "system: test\n", string(content))
+	content, err = fs.ReadFile(out.FS(), "system-preserve.txt")
+	require.NoError(t, err)
+	require.Equal(t, "system: test\n", string(content))
+	_, err = out.ReadFile("missing.txt")
+	require.ErrorIs(t, err, fs.ErrNotExist)
+	// nothing is written to the file system
+	entries, err := os.ReadDir(outDir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestArchiveWriterZip(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	file := filepath.Join(dir, "dist", "sdk.zip")
+	out, err := NewArchiveWriter(file, filepath.Join(dir, "out"))
+	require.NoError(t, err)
+	generateInto(t, out, filepath.Join(dir, "out"))
+	require.NoError(t, out.Close())
+	r, err := zip.OpenReader(file)
+	require.NoError(t, err)
+	defer r.Close()
+	require.Len(t, r.File, 2)
+	require.Equal(t, "system-preserve.txt", r.File[0].Name)
+	require.Equal(t, "system.txt", r.File[1].Name)
+	f, err := r.File[1].Open()
+	require.NoError(t, err)
+	content, err := io.ReadAll(f)
+	require.NoError(t, err)
+	require.Equal(t, "system: test\n", string(content))
+	require.NoDirExists(t, filepath.Join(dir, "out"))
+}
+
+func TestArchiveWriterTarGz(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	file := filepath.Join(dir, "sdk.tar.gz")
+	out, err := NewArchiveWriter(file, filepath.Join(dir, "out"))
+	require.NoError(t, err)
+	generateInto(t, out, filepath.Join(dir, "out"))
+	require.NoError(t, out.Close())
+	f, err := os.Open(file)
+	require.NoError(t, err)
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	tr := tar.NewReader(gr)
+	names := []string{}
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, h.Name)
+	}
+	require.Equal(t, []string{"system-preserve.txt", "system.txt"}, names)
+}
+
+func TestArchiveWriterOutsideBaseDir(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	out, err := NewArchiveWriter(filepath.Join(dir, "sdk.zip"), filepath.Join(dir, "out"))
+	require.NoError(t, err)
+	err = out.Write([]byte("x"), filepath.Join(dir, "x", "evil.txt"))
+	require.ErrorContains(t, err, "is outside of")
+	require.False(t, out.Exists(filepath.Join(dir, "x", "evil.txt")))
+	require.NoError(t, out.Write([]byte("x"), filepath.Join(dir, "out", "..x", "ok.txt")))
+	require.NoError(t, out.Close())
+	r, err := zip.OpenReader(filepath.Join(dir, "sdk.zip"))
+	require.NoError(t, err)
+	defer r.Close()
+	require.Len(t, r.File, 1)
+	require.Equal(t, "..x/ok.txt", r.File[0].Name)
+}
+
+func TestArchiveFormat(t *testing.T) {
+	t.Parallel()
+	format, err := ArchiveFormatFromFile("sdk.tgz")
+	require.NoError(t, err)
+	require.Equal(t, ArchiveTarGz, format)
+	format, err = ArchiveFormatFromFile("SDK.ZIP")
+	require.NoError(t, err)
+	require.Equal(t, ArchiveZip, format)
+	_, err = ArchiveFormatFromFile("sdk.rar")
+	require.Error(t, err)
+}
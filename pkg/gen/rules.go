@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/apigear-io/cli/pkg/helper"
 	"github.com/apigear-io/cli/pkg/spec"
 	"github.com/goccy/go-yaml"
 )
@@ -27,6 +28,23 @@ func ReadRulesDoc(filename string) (*spec.RulesDoc, error) {
 	return &doc, nil
 }
 
+// mergeOverlayRules merges the rules fragments of the overlay dirs into the rules
+func mergeOverlayRules(doc *spec.RulesDoc, overlays []string) error {
+	for _, dir := range overlays {
+		file := helper.Join(dir, "rules.yaml")
+		if !helper.IsFile(file) {
+			continue
+		}
+		log.Debug().Msgf("merge overlay rules %s", file)
+		fragment, err := ReadRulesDoc(file)
+		if err != nil {
+			return fmt.Errorf("read overlay rules %s: %w", file, err)
+		}
+		doc.Merge(fragment)
+	}
+	return nil
+}
+
 func CheckRulesJson(file string, bytes []byte) error {
 	var err error
 	if filepath.Ext(file) == ".yaml" || filepath.Ext(file) == ".yml" {
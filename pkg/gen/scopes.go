@@ -0,0 +1,157 @@
+package gen
+
+import (
+	"github.com/apigear-io/cli/pkg/model"
+	"github.com/apigear-io/cli/pkg/spec"
+)
+
+// ScopeFunc is called with the scope context of a symbol
+type ScopeFunc func(match spec.ScopeType, ctx any) error
+
+// WalkScopes walks the system and calls fn with the scope context of every symbol.
+// The order is system, then per module the module, externs, interfaces
+// (each followed by its properties, operations and signals), structs and enums.
+func WalkScopes(system *model.System, features map[string]bool, meta map[string]any, params map[string]any, fn ScopeFunc) error {
+	// process system
+	ctx := model.SystemScope{
+		System:   system,
+		Features: features,
+		Meta:     meta,
+		Params:   params,
+	}
+	err := fn(spec.ScopeSystem, ctx)
+	if err != nil {
+		return err
+	}
+	for _, module := range system.Modules {
+		// process module
+		ctx := model.ModuleScope{
+			System:   system,
+			Module:   module,
+			Features: features,
+			Meta:     meta,
+			Params:   params,
+		}
+		err := fn(spec.ScopeModule, ctx)
+		if err != nil {
+			return err
+		}
+		for _, extern := range module.Externs {
+			// process extern
+			ctx := model.ExternScope{
+				System:   system,
+				Module:   module,
+				Extern:   extern,
+				Features: features,
+				Meta:     meta,
+				Params:   params,
+			}
+			err := fn(spec.ScopeExtern, ctx)
+			if err != nil {
+				return err
+			}
+		}
+		for _, iface := range module.Interfaces {
+			err := walkInterface(system, module, iface, features, meta, params, fn)
+			if err != nil {
+				return err
+			}
+		}
+		for _, struct_ := range module.Structs {
+			// process struct
+			ctx := model.StructScope{
+				System:   system,
+				Module:   module,
+				Struct:   struct_,
+				Features: features,
+				Meta:     meta,
+				Params:   params,
+			}
+			err := fn(spec.ScopeStruct, ctx)
+			if err != nil {
+				return err
+			}
+		}
+		for _, enum := range module.Enums {
+			// process enum
+			ctx := model.EnumScope{
+				System:   system,
+				Module:   module,
+				Enum:     enum,
+				Features: features,
+				Meta:     meta,
+				Params:   params,
+			}
+			err := fn(spec.ScopeEnum, ctx)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// walkInterface calls fn with the interface scope and the scopes of its members
+func walkInterface(system *model.System, module *model.Module, iface *model.Interface, features map[string]bool, meta map[string]any, params map[string]any, fn ScopeFunc) error {
+	ctx := model.InterfaceScope{
+		System:    system,
+		Module:    module,
+		Interface: iface,
+		Features:  features,
+		Meta:      meta,
+		Params:    params,
+	}
+	err := fn(spec.ScopeInterface, ctx)
+	if err != nil {
+		return err
+	}
+	for _, prop := range iface.Properties {
+		// process property
+		ctx := model.PropertyScope{
+			System:    system,
+			Module:    module,
+			Interface: iface,
+			Property:  prop,
+			Features:  features,
+			Meta:      meta,
+			Params:    params,
+		}
+		err := fn(spec.ScopeProperty, ctx)
+		if err != nil {
+			return err
+		}
+	}
+	for _, op := range iface.Operations {
+		// process operation
+		ctx := model.OperationScope{
+			System:    system,
+			Module:    module,
+			Interface: iface,
+			Operation: op,
+			Features:  features,
+			Meta:      meta,
+			Params:    params,
+		}
+		err := fn(spec.ScopeOperation, ctx)
+		if err != nil {
+			return err
+		}
+	}
+	for _, sig := range iface.Signals {
+		// process signal
+		ctx := model.SignalScope{
+			System:    system,
+			Module:    module,
+			Interface: iface,
+			Signal:    sig,
+			Features:  features,
+			Meta:      meta,
+			Params:    params,
+		}
+		err := fn(spec.ScopeSignal, ctx)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package gen
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/apigear-io/cli/pkg/gen/filters"
+	"github.com/apigear-io/cli/pkg/helper"
+	"github.com/dop251/goja"
+)
+
+// A template can ship JavaScript files inside its "filters" folder
+// (next to the "templates" folder), which register additional template
+// functions:
+//
+//	filter("shout", function (s) { return s.toUpperCase() + "!" })
+//	filter("opCount", function (iface) { return iface.operations.length })
+//
+// Scripts run sandboxed without access to the file system or network.
+// Model nodes are passed as read-only copies using their JSON representation.
+// Each script run and function call is stopped after a timeout.
+
+// DefaultScriptTimeout is the time a script filter call may take
+const DefaultScriptTimeout = 2 * time.Second
+
+// ScriptFilters are template functions defined by JavaScript files
+type ScriptFilters struct {
+	// mu guards the runtime, which is not safe for concurrent use
+	mu      sync.Mutex
+	vm      *goja.Runtime
+	timeout time.Duration
+	funcs   map[string]goja.Callable
+	// file is the script currently loaded
+	file string
+}
+
+// FindScriptFilters returns the JavaScript files inside the filters folder of the template dirs
+func FindScriptFilters(templateDirs ...string) ([]string, error) {
+	files := []string{}
+	for _, dir := range templateDirs {
+		filtersDir := helper.Join(dir, "filters")
+		if !helper.IsDir(filtersDir) {
+			continue
+		}
+		entries, err := os.ReadDir(filtersDir)
+		if err != nil {
+			return nil, err
+		}
+		names := []string{}
+		for _, e := range entries {
+			if !e.IsDir() && filepath.Ext(e.Name()) == ".js" {
+				names = append(names, e.Name())
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			files = append(files, helper.Join(filtersDir, name))
+		}
+	}
+	return files, nil
+}
+
+// LoadScriptFilters runs the scripts in order and collects the registered functions.
+// Later scripts replace functions with the same name. Built-in filters can not be replaced.
+// A zero timeout uses the DefaultScriptTimeout.
+func LoadScriptFilters(files []string, timeout time.Duration) (*ScriptFilters, error) {
+	if timeout <= 0 {
+		timeout = DefaultScriptTimeout
+	}
+	s := &ScriptFilters{
+		vm:      goja.New(),
+		timeout: timeout,
+		funcs:   map[string]goja.Callable{},
+	}
+	builtin := filters.PopulateFuncMap()
+	err := s.vm.Set("filter", func(name string, fn goja.Value) error {
+		call, ok := goja.AssertFunction(fn)
+		if !ok {
+			return fmt.Errorf("filter %s is not a function", name)
+		}
+		if _, ok := builtin[name]; ok {
+			return fmt.Errorf("filter %s replaces a built-in filter", name)
+		}
+		if _, ok := s.funcs[name]; ok {
+			log.Debug().Msgf("script %s replaces filter %s", s.file, name)
+		}
+		s.funcs[name] = call
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = s.vm.Set("log", func(args ...any) {
+		log.Info().Msgf("%s: %s", filepath.Base(s.file), fmt.Sprint(args...))
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		if err := s.load(file); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// load compiles and runs a single script
+func (s *ScriptFilters) load(file string) error {
+	log.Debug().Msgf("load script filters %s", file)
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	prog, err := goja.Compile(file, string(content), true)
+	if err != nil {
+		return fmt.Errorf("compile script %s: %w", file, err)
+	}
+	s.file = file
+	err = s.withTimeout(func() error {
+		_, err := s.vm.RunProgram(prog)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("run script %s: %w", file, err)
+	}
+	return nil
+}
+
+// Names returns the sorted names of the registered functions
+func (s *ScriptFilters) Names() []string {
+	names := make([]string, 0, len(s.funcs))
+	for name := range s.funcs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FuncMap returns the registered functions as template functions
+func (s *ScriptFilters) FuncMap() template.FuncMap {
+	fm := template.FuncMap{}
+	for name, fn := range s.funcs {
+		fm[name] = s.templateFunc(name, fn)
+	}
+	return fm
+}
+
+// templateFunc wraps a script function as template function
+func (s *ScriptFilters) templateFunc(name string, fn goja.Callable) func(args ...any) (any, error) {
+	return func(args ...any) (any, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		values := make([]goja.Value, 0, len(args))
+		for _, arg := range args {
+			v, err := s.toValue(arg)
+			if err != nil {
+				return nil, fmt.Errorf("filter %s: %w", name, err)
+			}
+			values = append(values, v)
+		}
+		var result goja.Value
+		err := s.withTimeout(func() error {
+			var err error
+			result, err = fn(goja.Undefined(), values...)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("filter %s: %w", name, err)
+		}
+		if result == nil || goja.IsUndefined(result) || goja.IsNull(result) {
+			return "", nil
+		}
+		return result.Export(), nil
+	}
+}
+
+// toValue converts a template value into a script value.
+// Values other than scalars are copied using their JSON representation,
+// so scripts can not modify the model.
+func (s *ScriptFilters) toValue(arg any) (goja.Value, error) {
+	switch v := arg.(type) {
+	case nil:
+		return goja.Null(), nil
+	case string, bool, int, int32, int64, uint, uint32, uint64, float32, float64:
+		return s.vm.ToValue(v), nil
+	}
+	data, err := json.Marshal(arg)
+	if err != nil {
+		return nil, err
+	}
+	var copied any
+	if err := json.Unmarshal(data, &copied); err != nil {
+		return nil, err
+	}
+	return s.vm.ToValue(copied), nil
+}
+
+// withTimeout runs fn and interrupts the runtime when the timeout is exceeded
+func (s *ScriptFilters) withTimeout(fn func() error) error {
+	timer := time.AfterFunc(s.timeout, func() {
+		s.vm.Interrupt(fmt.Sprintf("timeout after %s", s.timeout))
+	})
+	defer func() {
+		timer.Stop()
+		s.vm.ClearInterrupt()
+	}()
+	err := fn()
+	if ierr, ok := err.(*goja.InterruptedError); ok {
+		return fmt.Errorf("%s", strings.TrimSpace(fmt.Sprint(ierr.Value())))
+	}
+	return err
+}
+
+// LoadFuncMap returns the built-in filters extended by the
+// script filters found in the given template dirs
+func LoadFuncMap(templateDirs ...string) (template.FuncMap, error) {
+	fm := filters.PopulateFuncMap()
+	files, err := FindScriptFilters(templateDirs...)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return fm, nil
+	}
+	scripts, err := LoadScriptFilters(files, DefaultScriptTimeout)
+	if err != nil {
+		return nil, err
+	}
+	for name, fn := range scripts.FuncMap() {
+		fm[name] = fn
+	}
+	return fm, nil
+}
@@ -0,0 +1,57 @@
+package gen
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apigear-io/cli/pkg/helper"
+	"github.com/apigear-io/cli/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScriptFilters(t *testing.T) {
+	t.Parallel()
+	out := NewMockOutput()
+	g, err := New(Options{
+		System:       model.NewSystem("demo"),
+		Force:        true,
+		TemplatesDir: "testdata/scripts/templates",
+		OutputDir:    "testdata/output",
+		Output:       out,
+	})
+	require.NoError(t, err)
+	err = g.ProcessRules(readRules(t, "testdata/scripts/rules.yaml"))
+	require.NoError(t, err)
+	// the later script wins and the model stays unchanged
+	require.Equal(t, "DEMO!! changed demo\n", out.Writes[helper.Join("testdata", "output", "system.txt")])
+	// script filters are available to targets and conditions
+	require.Contains(t, out.Writes, helper.Join("testdata", "output", "DEMO!!.txt"))
+}
+
+func TestScriptFiltersTimeout(t *testing.T) {
+	t.Parallel()
+	files, err := FindScriptFilters("testdata/scripts")
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	s, err := LoadScriptFilters(files, 50*time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, []string{"rename", "shout", "spin"}, s.Names())
+	spin := s.FuncMap()["spin"].(func(args ...any) (any, error))
+	_, err = spin()
+	require.ErrorContains(t, err, "filter spin: timeout after 50ms")
+	// the runtime is usable after an interrupt
+	shout := s.FuncMap()["shout"].(func(args ...any) (any, error))
+	v, err := shout("hi")
+	require.NoError(t, err)
+	require.Equal(t, "HI!!", v)
+	files, err = FindScriptFilters("testdata/scripts-loop")
+	require.NoError(t, err)
+	_, err = LoadScriptFilters(files, 50*time.Millisecond)
+	require.ErrorContains(t, err, "timeout after 50ms")
+}
+
+func TestScriptFiltersBuiltin(t *testing.T) {
+	t.Parallel()
+	_, err := LoadFuncMap("testdata/scripts-builtin")
+	require.ErrorContains(t, err, "filter upper replaces a built-in filter")
+}
@@ -0,0 +1 @@
+base api
@@ -0,0 +1 @@
+{{ define "header" }}base header {{ end }}
\ No newline at end of file
@@ -0,0 +1 @@
+child api
@@ -0,0 +1 @@
+{{ define "header" }}child header {{ end }}{{ define "notice" }}child notice{{ end }}
\ No newline at end of file
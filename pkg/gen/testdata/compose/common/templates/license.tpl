@@ -0,0 +1 @@
+{{ template "notice" . }}
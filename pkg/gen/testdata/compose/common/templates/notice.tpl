@@ -0,0 +1 @@
+{{ define "notice" }}common notice{{ end }}
\ No newline at end of file
@@ -0,0 +1 @@
+overlay system: {{.System.Name}}
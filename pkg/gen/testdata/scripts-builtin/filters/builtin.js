@@ -0,0 +1,3 @@
+filter("upper", function (s) {
+  return s
+})
@@ -0,0 +1 @@
+for (;;) {}
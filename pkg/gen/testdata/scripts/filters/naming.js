@@ -0,0 +1,15 @@
+// shout returns the upper case text with an exclamation mark
+filter("shout", function (s) {
+  return s.toUpperCase() + "!"
+})
+
+// rename tries to change the model node and returns the new name
+filter("rename", function (node) {
+  node.name = "changed"
+  return node.name
+})
+
+// spin never returns
+filter("spin", function () {
+  for (;;) {}
+})
@@ -0,0 +1,4 @@
+// a later script replaces the filter of an earlier script
+filter("shout", function (s) {
+  return s.toUpperCase() + "!!"
+})
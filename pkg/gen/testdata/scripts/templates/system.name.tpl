@@ -0,0 +1 @@
+{{ shout .System.Name }} {{ rename .System }} {{ .System.Name }}
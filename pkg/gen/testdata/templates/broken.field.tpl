@@ -0,0 +1,2 @@
+{{ .Operation.Name }}
+{{ .Operation.Nope }}
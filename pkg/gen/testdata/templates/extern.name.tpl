@@ -0,0 +1 @@
+{{.Extern.Name}}
\ No newline at end of file
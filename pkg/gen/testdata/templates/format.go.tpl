@@ -0,0 +1,2 @@
+package   {{ .System.Name }}
+func  Hello( ) string { return  "hello" }
@@ -0,0 +1 @@
+{"name":"{{ .System.Name }}","tags":["a","b"]}
@@ -0,0 +1,2 @@
+name:   {{ .System.Name }}
+tags: [a,   b]
@@ -0,0 +1 @@
+{{.Interface.Name}}
\ No newline at end of file
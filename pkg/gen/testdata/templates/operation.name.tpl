@@ -0,0 +1 @@
+{{.Operation.Name}}
\ No newline at end of file
@@ -0,0 +1 @@
+{{ .Params.namespace }}:{{ .Params.port }}:{{ if .Params.tests }}tests{{ end }}
\ No newline at end of file
@@ -0,0 +1 @@
+{{.Property.Name}}
\ No newline at end of file
@@ -0,0 +1,3 @@
+{{- range .Operation.Params }}
+{{ rsParam "" "" . }} | {{ csParam "" . }} | {{ pyParam "" . }} | {{ swiftParam "" . }}
+{{- end }}
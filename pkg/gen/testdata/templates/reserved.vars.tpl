@@ -0,0 +1,3 @@
+{{- range .Operation.Params }}
+{{ rsVar "self." . }} | {{ rsVar "m_" . }} | {{ ueVar "In" . }} | {{ ueParam "In" . }}
+{{- end }}
@@ -0,0 +1 @@
+{{.Signal.Name}}
\ No newline at end of file
@@ -0,0 +1 @@
+{{.Struct.Name}}
\ No newline at end of file
@@ -0,0 +1,62 @@
+package gen
+
+import (
+	"strings"
+	"text/template"
+
+	"github.com/apigear-io/cli/pkg/gen/filters"
+)
+
+// EvalCondition evaluates a rules condition using the given context.
+// The condition is either a template pipeline (e.g. `.Interface.Meta.remote`
+// or `not .Struct.Meta.internal`) or a full template string which renders
+// to a truthy value. An empty condition always matches.
+func EvalCondition(cond string, ctx any) (bool, error) {
+	return EvalConditionWithFuncs(cond, ctx, filters.PopulateFuncMap())
+}
+
+// EvalConditionWithFuncs evaluates a rules condition using the given context and filters
+func EvalConditionWithFuncs(cond string, ctx any, funcs template.FuncMap) (bool, error) {
+	cond = conditionTemplate(cond)
+	if cond == "" {
+		return true, nil
+	}
+	s, err := RenderStringWithFuncs(cond, ctx, funcs)
+	if err != nil {
+		return false, err
+	}
+	return isTruthy(s), nil
+}
+
+// ParseCondition checks the syntax of a rules condition without evaluating it
+func ParseCondition(cond string) error {
+	return ParseConditionWithFuncs(cond, filters.PopulateFuncMap())
+}
+
+// ParseConditionWithFuncs checks the syntax of a rules condition using the given filters
+func ParseConditionWithFuncs(cond string, funcs template.FuncMap) error {
+	cond = conditionTemplate(cond)
+	if cond == "" {
+		return nil
+	}
+	_, err := template.New("when").Funcs(funcs).Parse(cond)
+	return err
+}
+
+// conditionTemplate wraps a bare pipeline condition into a template
+func conditionTemplate(cond string) string {
+	cond = strings.TrimSpace(cond)
+	if cond != "" && !strings.Contains(cond, "{{") {
+		cond = "{{ if " + cond + " }}true{{ end }}"
+	}
+	return cond
+}
+
+// isTruthy returns false for empty, "false", "0" and "<no value>" results
+func isTruthy(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "false", "0", "<no value>":
+		return false
+	}
+	return true
+}
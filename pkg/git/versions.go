@@ -1,6 +1,12 @@
 package git
 
-import "github.com/Masterminds/semver/v3"
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
 
 // VersionCollection is a collection of tags
 // it implements sort.Interface
@@ -37,6 +43,37 @@ func (c VersionCollection) Latest() VersionInfo {
 	return v
 }
 
+// Resolve returns the highest version satisfying the semver constraint
+// (e.g. ^1.2, ~1.4.0 or >=2 <3)
+func (c VersionCollection) Resolve(constraint string) (VersionInfo, error) {
+	cs, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return VersionInfo{}, fmt.Errorf("invalid version constraint %s: %w", constraint, err)
+	}
+	// versions read from json may only have a name
+	versions := make(VersionCollection, 0, len(c))
+	for _, v := range c {
+		if v.Version == nil {
+			v.Version, err = semver.NewVersion(v.Name)
+			if err != nil {
+				continue
+			}
+		}
+		versions = append(versions, v)
+	}
+	sort.Sort(versions)
+	for i := len(versions) - 1; i >= 0; i-- {
+		if cs.Check(versions[i].Version) {
+			return versions[i], nil
+		}
+	}
+	available := "none"
+	if len(versions) > 0 {
+		available = strings.Join(versions.AsList(), ", ")
+	}
+	return VersionInfo{}, fmt.Errorf("no version satisfies %s (available: %s)", constraint, available)
+}
+
 func (c VersionCollection) AsList() []string {
 	result := make([]string, 0)
 	for _, v := range c {
@@ -1,8 +1,12 @@
 package helper
 
 import (
+	"errors"
 	"os"
 	"os/signal"
+	"runtime"
+	"sync"
+	"sync/atomic"
 	"syscall"
 )
 
@@ -14,3 +18,180 @@ func WaitForInterrupt(cancel func()) {
 	<-sig
 	cancel()
 }
+
+// Workers returns the number of workers to use for n items.
+// A value below one uses the number of CPUs.
+func Workers(jobs int, n int) int {
+	if jobs < 1 {
+		jobs = runtime.NumCPU()
+	}
+	if jobs > n {
+		jobs = n
+	}
+	return jobs
+}
+
+// SplitJobs splits the jobs budget between n outer items run concurrently
+// and the inner items of each outer item, so at most jobs inner items run at once.
+// A value below one uses the number of CPUs.
+func SplitJobs(jobs int, n int) (int, int) {
+	if jobs < 1 {
+		jobs = runtime.NumCPU()
+	}
+	outer := Workers(jobs, n)
+	if outer < 1 {
+		return 1, jobs
+	}
+	return outer, max(1, jobs/outer)
+}
+
+// RunParallel calls fn for each index in [0, n) using a bounded number of workers.
+// After the first failure no further indices are started.
+// The error of the lowest failing index is returned.
+func RunParallel(n int, jobs int, fn func(i int) error) error {
+	if n == 0 {
+		return nil
+	}
+	workers := Workers(jobs, n)
+	errs := make([]error, n)
+	var failed atomic.Bool
+	next := make(chan int)
+	wg := sync.WaitGroup{}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				errs[i] = fn(i)
+				if errs[i] != nil {
+					failed.Store(true)
+				}
+			}
+		}()
+	}
+	for i := 0; i < n && !failed.Load(); i++ {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunParallelAll calls fn for each index in [0, n) using a bounded number of workers.
+// Failures do not stop the remaining indices.
+// All errors are joined in index order.
+func RunParallelAll(n int, jobs int, fn func(i int) error) error {
+	if n == 0 {
+		return nil
+	}
+	workers := Workers(jobs, n)
+	errs := make([]error, n)
+	next := make(chan int)
+	wg := sync.WaitGroup{}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				errs[i] = fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// ErrSkipped is reported for indices of RunGraph which were not run,
+// as one of their dependencies failed or the run was stopped by a failure.
+var ErrSkipped = errors.New("skipped")
+
+// RunGraph calls fn for each index in [0, len(deps)) using a bounded number of workers.
+// deps[i] are the indices which must succeed before index i is started,
+// independent indices run concurrently. The graph must not contain cycles.
+// Indices depending on a failed index are not run. Without keepGoing
+// no further indices are started after the first failure.
+// The returned slice holds the result of each index, ErrSkipped for indices not run.
+func RunGraph(deps [][]int, jobs int, keepGoing bool, fn func(i int) error) []error {
+	n := len(deps)
+	errs := make([]error, n)
+	if n == 0 {
+		return errs
+	}
+	workers := Workers(jobs, n)
+	pending := make([]int, n)
+	dependents := make([][]int, n)
+	for i, ds := range deps {
+		pending[i] = len(ds)
+		for _, d := range ds {
+			dependents[d] = append(dependents[d], i)
+		}
+	}
+	queue := []int{}
+	for i := range deps {
+		if pending[i] == 0 {
+			queue = append(queue, i)
+		}
+	}
+	done := make([]bool, n)
+	var skip func(i int)
+	skip = func(i int) {
+		if done[i] {
+			return
+		}
+		done[i] = true
+		errs[i] = ErrSkipped
+		for _, d := range dependents[i] {
+			skip(d)
+		}
+	}
+	finished := make(chan int)
+	running := 0
+	stopped := false
+	for {
+		for !stopped && running < workers && len(queue) > 0 {
+			i := queue[0]
+			queue = queue[1:]
+			running++
+			go func(i int) {
+				errs[i] = fn(i)
+				finished <- i
+			}(i)
+		}
+		if running == 0 {
+			break
+		}
+		i := <-finished
+		running--
+		done[i] = true
+		if errs[i] != nil {
+			if !keepGoing {
+				stopped = true
+			}
+			for _, d := range dependents[i] {
+				skip(d)
+			}
+			continue
+		}
+		for _, d := range dependents[i] {
+			pending[d]--
+			if pending[d] == 0 && !done[d] {
+				queue = append(queue, d)
+			}
+		}
+	}
+	for i := range errs {
+		if !done[i] {
+			errs[i] = ErrSkipped
+		}
+	}
+	return errs
+}
@@ -3,8 +3,10 @@ package gen
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/apigear-io/cli/pkg/cmd/gen"
+	generator "github.com/apigear-io/cli/pkg/gen"
 	"github.com/apigear-io/cli/pkg/helper"
 	"github.com/apigear-io/cli/pkg/sol"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -22,6 +24,7 @@ func registerGenerateExpertTool(s *server.MCPServer) {
 		mcp.WithString("features", mcp.Description("Features to enable (comma-separated, defaults to 'all')")),
 		mcp.WithString("force", mcp.Description("Force overwrite (true/false)")),
 		mcp.WithString("watch", mcp.Description("Watch for changes (true/false). This keeps the process running.")),
+		mcp.WithString("memory", mcp.Description("Return the generated files instead of writing them to the output directory (true/false).")),
 	)
 	s.AddTool(genExpertTool, func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		input, err := request.RequireString("input")
@@ -62,6 +65,15 @@ func registerGenerateExpertTool(s *server.MCPServer) {
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
 
+		if memory, err := request.RequireString("memory"); err == nil && memory == "true" {
+			out := generator.NewMemoryWriter(helper.Join(doc.RootDir, options.OutputDir))
+			runner.Options.Output = out
+			if err := runner.RunDoc(ctx, doc.RootDir, doc); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return mcp.NewToolResultText(formatMemoryFiles(out)), nil
+		}
+
 		if err := runner.RunDoc(ctx, doc.RootDir, doc); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
@@ -77,3 +89,18 @@ func registerGenerateExpertTool(s *server.MCPServer) {
 		return mcp.NewToolResultText("Successfully ran code generation with expert options"), nil
 	})
 }
+
+// formatMemoryFiles formats the generated in-memory files as text result
+func formatMemoryFiles(out *generator.MemoryWriter) string {
+	var sb strings.Builder
+	names := out.Names()
+	fmt.Fprintf(&sb, "Generated %d files\n", len(names))
+	for _, name := range names {
+		content, err := out.ReadFile(name)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&sb, "\n--- %s ---\n%s\n", name, content)
+	}
+	return sb.String()
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/apigear-io/cli/pkg/cmd/gen"
+	"github.com/apigear-io/cli/pkg/sol"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
@@ -31,7 +32,7 @@ func registerGenerateSolutionTool(s *server.MCPServer) {
 			watchEnabled = true
 		}
 
-		err = gen.RunGenerateSolution(solutionPath, watchEnabled, forceEnabled)
+		err = gen.RunGenerateSolution(solutionPath, watchEnabled, forceEnabled, sol.RunOptions{})
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
@@ -48,6 +48,10 @@ const (
 	TypeInterface KindType = "interface"
 )
 
+// Features are the model features supported by this version,
+// templates can require them in the engines section of their rules
+var Features = []string{"bytes", "extends", "externs", "imports", "meta", "readonly"}
+
 // Meta is a map of string to interface
 // It is used to store additional information in a node
 type Meta map[string]interface{}
@@ -212,6 +216,6 @@ func (t TypedNode) TypeName() string {
 	return s
 }
 
-func (t *TypedNode) CheckReservedWords(langs []rkw.Lang) {
-	rkw.CheckIsReserved(langs, t.Name, "type")
+func (t *TypedNode) CheckReservedWords(langs []rkw.Lang) []string {
+	return rkw.CheckIsReserved(langs, t.Name, "type")
 }
@@ -87,11 +87,12 @@ func (e *Enum) NoMembers() bool {
 }
 
 // CheckReservedWords checks the names of the enum.
-func (e *Enum) CheckReservedWords(langs []rkw.Lang) {
-	rkw.CheckIsReserved(langs, e.Name, "enum")
+func (e *Enum) CheckReservedWords(langs []rkw.Lang) []string {
+	warnings := rkw.CheckIsReserved(langs, e.Name, "enum")
 	for _, mem := range e.Members {
-		mem.CheckReservedWords(langs)
+		warnings = append(warnings, mem.CheckReservedWords(langs)...)
 	}
+	return warnings
 }
 
 // EnumMember is a member of an enumeration.
@@ -126,6 +127,6 @@ func (e *EnumMember) Validate(m *Module) error {
 }
 
 // CheckReservedWords checks the names of the enum member.
-func (e *EnumMember) CheckReservedWords(langs []rkw.Lang) {
-	rkw.CheckIsReserved(langs, e.Name, "enum member")
+func (e *EnumMember) CheckReservedWords(langs []rkw.Lang) []string {
+	return rkw.CheckIsReserved(langs, e.Name, "enum member")
 }
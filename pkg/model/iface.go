@@ -48,11 +48,12 @@ func (s *Signal) Validate(m *Module) error {
 	return nil
 }
 
-func (s *Signal) CheckReservedWords(langs []rkw.Lang) {
-	rkw.CheckIsReserved(langs, s.Name, "signal")
+func (s *Signal) CheckReservedWords(langs []rkw.Lang) []string {
+	warnings := rkw.CheckIsReserved(langs, s.Name, "signal")
 	for _, p := range s.Params {
-		p.CheckReservedWords(langs)
+		warnings = append(warnings, p.CheckReservedWords(langs)...)
 	}
+	return warnings
 }
 
 type Operation struct {
@@ -122,14 +123,15 @@ func (m *Operation) ParamNames() []string {
 	return names
 }
 
-func (m *Operation) CheckReservedWords(langs []rkw.Lang) {
-	rkw.CheckIsReserved(langs, m.Name, "operation")
+func (m *Operation) CheckReservedWords(langs []rkw.Lang) []string {
+	warnings := rkw.CheckIsReserved(langs, m.Name, "operation")
 	for _, p := range m.Params {
-		p.CheckReservedWords(langs)
+		warnings = append(warnings, p.CheckReservedWords(langs)...)
 	}
 	if m.Return != nil {
-		m.Return.CheckReservedWords(langs)
+		warnings = append(warnings, m.Return.CheckReservedWords(langs)...)
 	}
+	return warnings
 }
 
 type Extends struct {
@@ -311,15 +313,16 @@ func (i Interface) NoMembers() bool {
 	return i.NoProperties() && i.NoOperations() && i.NoSignals()
 }
 
-func (i *Interface) CheckReservedWords(langs []rkw.Lang) {
-	rkw.CheckIsReserved(langs, i.Name, "interface")
+func (i *Interface) CheckReservedWords(langs []rkw.Lang) []string {
+	warnings := rkw.CheckIsReserved(langs, i.Name, "interface")
 	for _, p := range i.Properties {
-		p.CheckReservedWords(langs)
+		warnings = append(warnings, p.CheckReservedWords(langs)...)
 	}
 	for _, o := range i.Operations {
-		o.CheckReservedWords(langs)
+		warnings = append(warnings, o.CheckReservedWords(langs)...)
 	}
 	for _, s := range i.Signals {
-		s.CheckReservedWords(langs)
+		warnings = append(warnings, s.CheckReservedWords(langs)...)
 	}
+	return warnings
 }
@@ -360,15 +360,16 @@ func (m *Module) computeChecksum() {
 	m.Checksum = hex.EncodeToString(sum[:])
 }
 
-func (m *Module) CheckReservedWords(langs []rkw.Lang) {
-	rkw.CheckIsReserved(langs, m.Name, "module")
+func (m *Module) CheckReservedWords(langs []rkw.Lang) []string {
+	warnings := rkw.CheckIsReserved(langs, m.Name, "module")
 	for _, i := range m.Interfaces {
-		i.CheckReservedWords(langs)
+		warnings = append(warnings, i.CheckReservedWords(langs)...)
 	}
 	for _, s := range m.Structs {
-		s.CheckReservedWords(langs)
+		warnings = append(warnings, s.CheckReservedWords(langs)...)
 	}
 	for _, e := range m.Enums {
-		e.CheckReservedWords(langs)
+		warnings = append(warnings, e.CheckReservedWords(langs)...)
 	}
+	return warnings
 }
@@ -8,6 +8,8 @@ type SystemScope struct {
 	Features map[string]bool
 	// Meta is the map of metadata
 	Meta map[string]any
+	// Params are the typed template parameters
+	Params map[string]any
 }
 
 // ModuleScope is used by the generator to generate code for a module
@@ -20,6 +22,8 @@ type ModuleScope struct {
 	Features map[string]bool
 	// Meta is the map of metadata
 	Meta map[string]any
+	// Params are the typed template parameters
+	Params map[string]any
 }
 
 // InterfaceScope is used by the generator to generate code for an interface
@@ -34,6 +38,8 @@ type InterfaceScope struct {
 	Features map[string]bool
 	// Meta is the map of metadata
 	Meta map[string]any
+	// Params are the typed template parameters
+	Params map[string]any
 }
 
 // StructScope is used by the generator to generate code for a struct
@@ -48,6 +54,8 @@ type StructScope struct {
 	Features map[string]bool
 	// Meta is the map of metadata
 	Meta map[string]any
+	// Params are the typed template parameters
+	Params map[string]any
 }
 
 // EnumScope is used by the generator to generate code for an enum
@@ -62,4 +70,76 @@ type EnumScope struct {
 	Features map[string]bool
 	// Meta is the map of metadata
 	Meta map[string]any
+	// Params are the typed template parameters
+	Params map[string]any
+}
+
+// OperationScope is used by the generator to generate code for an operation
+type OperationScope struct {
+	// System is the root of all modules
+	System *System
+	// Module is the module that contains the interfaces, structs, and enums
+	Module *Module
+	// Interface is the interface that contains the operation
+	Interface *Interface
+	// Operation is the operation that contains the params and return type
+	Operation *Operation
+	// Features is the list of features that are enabled
+	Features map[string]bool
+	// Meta is the map of metadata
+	Meta map[string]any
+	// Params are the typed template parameters
+	Params map[string]any
+}
+
+// PropertyScope is used by the generator to generate code for a property
+type PropertyScope struct {
+	// System is the root of all modules
+	System *System
+	// Module is the module that contains the interfaces, structs, and enums
+	Module *Module
+	// Interface is the interface that contains the property
+	Interface *Interface
+	// Property is the property of the interface
+	Property *TypedNode
+	// Features is the list of features that are enabled
+	Features map[string]bool
+	// Meta is the map of metadata
+	Meta map[string]any
+	// Params are the typed template parameters
+	Params map[string]any
+}
+
+// SignalScope is used by the generator to generate code for a signal
+type SignalScope struct {
+	// System is the root of all modules
+	System *System
+	// Module is the module that contains the interfaces, structs, and enums
+	Module *Module
+	// Interface is the interface that contains the signal
+	Interface *Interface
+	// Signal is the signal that contains the params
+	Signal *Signal
+	// Features is the list of features that are enabled
+	Features map[string]bool
+	// Meta is the map of metadata
+	Meta map[string]any
+	// Params are the typed template parameters
+	Params map[string]any
+}
+
+// ExternScope is used by the generator to generate code for an extern
+type ExternScope struct {
+	// System is the root of all modules
+	System *System
+	// Module is the module that contains the externs
+	Module *Module
+	// Extern is the external type declaration
+	Extern *Extern
+	// Features is the list of features that are enabled
+	Features map[string]bool
+	// Meta is the map of metadata
+	Meta map[string]any
+	// Params are the typed template parameters
+	Params map[string]any
 }
@@ -69,9 +69,10 @@ func (s *Struct) NoFields() bool {
 	return len(s.Fields) == 0
 }
 
-func (s *Struct) CheckReservedWords(langs []rkw.Lang) {
-	rkw.CheckIsReserved(langs, s.Name, "struct")
+func (s *Struct) CheckReservedWords(langs []rkw.Lang) []string {
+	warnings := rkw.CheckIsReserved(langs, s.Name, "struct")
 	for _, f := range s.Fields {
-		f.CheckReservedWords(langs)
+		warnings = append(warnings, f.CheckReservedWords(langs)...)
 	}
+	return warnings
 }
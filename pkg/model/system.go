@@ -260,13 +260,15 @@ func FQNSplit3(fqn string) (string, string, string) {
 	return moduleName, elementName, memberName
 }
 
-func (s *System) CheckReservedWords(langs []string) {
+// CheckReservedWords checks the names of the system and returns the warnings
+func (s *System) CheckReservedWords(langs []string) []string {
 	ls := make([]rkw.Lang, 0)
 	for _, l := range langs {
 		ls = append(ls, rkw.Lang(l))
 	}
-	rkw.CheckIsReserved(ls, s.Name, "system")
+	warnings := rkw.CheckIsReserved(ls, s.Name, "system")
 	for _, m := range s.Modules {
-		m.CheckReservedWords(ls)
+		warnings = append(warnings, m.CheckReservedWords(ls)...)
 	}
+	return warnings
 }
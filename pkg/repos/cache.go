@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/apigear-io/cli/pkg/cfg"
 	"github.com/apigear-io/cli/pkg/git"
 	"github.com/apigear-io/cli/pkg/helper"
@@ -60,6 +61,27 @@ func (c *cache) ListVersions(repoID string) ([]*git.RepoInfo, error) {
 	return versions, nil
 }
 
+// InstalledVersions returns the versions of the template installed in the cache
+func (c *cache) InstalledVersions(name string) (git.VersionCollection, error) {
+	infos, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+	versions := git.VersionCollection{}
+	for _, info := range infos {
+		if NameFromRepoID(info.Name) != name {
+			continue
+		}
+		version := VersionFromRepoID(info.Name)
+		v, err := semver.NewVersion(version)
+		if err != nil {
+			continue
+		}
+		versions = append(versions, git.VersionInfo{Name: version, SHA: info.Commit, Version: v})
+	}
+	return versions, nil
+}
+
 func (c *cache) Search(pattern string) ([]*git.RepoInfo, error) {
 	result, err := c.List()
 	if err != nil {
@@ -153,6 +175,9 @@ func (c *cache) ListCachedRepos() ([]*git.RepoInfo, error) {
 		if err != nil {
 			return fmt.Errorf("walk template dir: %s", err)
 		}
+		if info.IsDir() && info.Name() == commitsDir {
+			return filepath.SkipDir
+		}
 		if info.IsDir() && info.Name() != "." && info.Name() != ".." {
 			if helper.IsDir(helper.Join(path, ".git")) {
 				name, err := filepath.Rel(c.cacheDir, path)
@@ -217,3 +242,40 @@ func (c *cache) GetTemplateDir(repoId string) (string, error) {
 	}
 	return target, nil
 }
+
+// commitsDir holds the checkouts of locked commits inside the cache dir.
+// It is hidden, so the checkouts are not listed as installed templates.
+const commitsDir = ".commits"
+
+// GetTemplateDirAtCommit returns the template dir checked out at the given commit.
+// A commit other than the one of the installed version is checked out into
+// a dir keyed by the commit, so the installed version stays untouched.
+func (c *cache) GetTemplateDirAtCommit(repoId string, commit string) (string, error) {
+	repoId = EnsureRepoID(repoId)
+	dir, err := c.GetTemplateDir(repoId)
+	if err != nil {
+		return "", err
+	}
+	info, err := c.Info(repoId)
+	if err != nil {
+		return "", err
+	}
+	if commit == "" || info.Commit == commit {
+		return dir, nil
+	}
+	target := helper.Join(c.cacheDir, commitsDir, repoId, commit)
+	if helper.IsDir(target) {
+		return target, nil
+	}
+	log.Info().Msgf("checkout template %s at commit %s", repoId, commit)
+	err = git.Clone(dir, target)
+	if err == nil {
+		err = git.CheckoutCommit(target, commit)
+	}
+	if err != nil {
+		// do not leave a partial checkout behind
+		os.RemoveAll(target)
+		return "", fmt.Errorf("checkout template %s at commit %s: %w", repoId, commit, err)
+	}
+	return target, nil
+}
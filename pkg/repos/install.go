@@ -25,3 +25,13 @@ func GetOrInstallTemplateFromRepoID(repoID string) (string, error) {
 	}
 	return fixedRepoId, nil
 }
+
+// GetOrInstallTemplateAtCommit installs the template repo id if needed
+// and returns the template dir checked out at the given commit.
+func GetOrInstallTemplateAtCommit(repoID string, commit string) (string, error) {
+	fixedRepoId, err := GetOrInstallTemplateFromRepoID(repoID)
+	if err != nil {
+		return "", err
+	}
+	return Cache.GetTemplateDirAtCommit(fixedRepoId, commit)
+}
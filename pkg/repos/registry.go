@@ -178,6 +178,9 @@ func (r *registry) Reset() error {
 
 func (r *registry) FixRepoId(repoID string) (string, error) {
 	version := VersionFromRepoID(repoID)
+	if IsRepoID(repoID) && IsVersionRange(version) {
+		return r.resolveVersionRange(repoID)
+	}
 	if !IsRepoID(repoID) || version == "latest" {
 		info, err := r.Get(repoID)
 		if err != nil {
@@ -187,3 +190,36 @@ func (r *registry) FixRepoId(repoID string) (string, error) {
 	}
 	return repoID, nil
 }
+
+// resolveVersionRange resolves a repo id with a version range (e.g. name@^1.2)
+// to the highest registry version satisfying the range. A partial version
+// (e.g. v1.2) resolves to a tag with the exact name, if there is one.
+// Templates not found in the registry are resolved against the installed versions.
+func (r *registry) resolveVersionRange(repoID string) (string, error) {
+	name, constraint := SplitRepoID(repoID)
+	var versions git.VersionCollection
+	info, err := r.Get(name)
+	if err == nil {
+		versions = info.Versions
+	} else {
+		log.Warn().Err(err).Msgf("resolve %s against installed versions", repoID)
+		versions, err = Cache.InstalledVersions(name)
+		if err != nil {
+			return "", err
+		}
+	}
+	if !HasRangeOperator(constraint) {
+		for _, v := range versions {
+			if v.Name == constraint {
+				return MakeRepoID(name, v.Name), nil
+			}
+		}
+	}
+	v, err := versions.Resolve(constraint)
+	if err != nil {
+		return "", fmt.Errorf("template %s: %w", name, err)
+	}
+	fixedRepoID := MakeRepoID(name, v.Name)
+	log.Info().Msgf("resolved template %s to %s", repoID, fixedRepoID)
+	return fixedRepoID, nil
+}
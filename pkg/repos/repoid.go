@@ -3,6 +3,8 @@ package repos
 import (
 	"fmt"
 	"strings"
+
+	"github.com/Masterminds/semver/v3"
 )
 
 func EnsureRepoID(name string) string {
@@ -16,6 +18,28 @@ func EnsureRepoID(name string) string {
 	return fmt.Sprintf("%s@%s", parts[0], parts[1])
 }
 
+// IsVersionRange returns true if the version is a semver constraint
+// (e.g. ^1.2, ~1.4.0 or >=2 <3) and not an exact version or latest.
+// Partial versions (e.g. v1.2) are ranges, but resolve to a tag with
+// the exact name first. Versions which are not valid constraints
+// (e.g. main) are exact.
+func IsVersionRange(version string) bool {
+	if version == "" || version == "latest" {
+		return false
+	}
+	if _, err := semver.StrictNewVersion(strings.TrimPrefix(version, "v")); err == nil {
+		return false
+	}
+	_, err := semver.NewConstraint(version)
+	return err == nil
+}
+
+// HasRangeOperator returns true if the version range uses range operators
+// or wildcards (e.g. ^1.2, >=2 <3 or 1.x) and is not a partial version.
+func HasRangeOperator(version string) bool {
+	return strings.ContainsAny(version, "^~<>=xX*, |")
+}
+
 func IsRepoID(name string) bool {
 	return strings.Contains(name, "@")
 }
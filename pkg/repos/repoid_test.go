@@ -1,6 +1,8 @@
 package repos
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -108,3 +110,92 @@ func TestEnsureRepoID(t *testing.T) {
 		})
 	}
 }
+
+func TestIsVersionRange(t *testing.T) {
+	// table driven test for testing version ranges (e.g. ^1.2)
+	tests := []struct {
+		label    string
+		version  string
+		expected bool
+	}{
+		{"empty", "", false},
+		{"latest", "latest", false},
+		{"exact", "v1.2.0", false},
+		{"exact without prefix", "1.2.0", false},
+		{"branch", "main", false},
+		{"caret", "^1.2", true},
+		{"tilde", "~1.4.0", true},
+		{"bounds", ">=2 <3", true},
+		{"partial", "v1.2", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			actual := IsVersionRange(tt.version)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestFixRepoIdVersionRange(t *testing.T) {
+	dir := t.TempDir()
+	registry := `{ "entries": [ { "name": "foo", "versions": [
+		{ "name": "v1.2.0" }, { "name": "v1.4.2" }, { "name": "v1.5.0" }, { "name": "v2.0.0" }, { "name": "v2.1.0-beta" }, { "name": "v3.0.0" }
+	] } ] }`
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "registry.json"), []byte(registry), 0644))
+	r := NewRegistry(dir, "")
+	// table driven test for resolving version ranges against the registry
+	tests := []struct {
+		label    string
+		repoID   string
+		expected string
+	}{
+		{"caret", "foo@^1.2", "foo@v1.5.0"},
+		{"tilde", "foo@~1.4.0", "foo@v1.4.2"},
+		{"bounds", "foo@>=2 <3", "foo@v2.0.0"},
+		{"exact", "foo@v1.2.0", "foo@v1.2.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			actual, err := r.FixRepoId(tt.repoID)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+	_, err := r.FixRepoId("foo@^4")
+	assert.EqualError(t, err, "template foo: no version satisfies ^4 (available: v1.2.0, v1.4.2, v1.5.0, v2.0.0, v2.1.0-beta, v3.0.0)")
+}
+
+func TestFixRepoIdPartialVersionTag(t *testing.T) {
+	dir := t.TempDir()
+	registry := `{ "entries": [ { "name": "foo", "versions": [
+		{ "name": "v1.2" }, { "name": "v1.2.5" }, { "name": "v1.4.2" }
+	] } ] }`
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "registry.json"), []byte(registry), 0644))
+	r := NewRegistry(dir, "")
+	tests := []struct {
+		label    string
+		repoID   string
+		expected string
+	}{
+		{"exact tag", "foo@v1.2", "foo@v1.2"},
+		{"partial without tag", "foo@v1.4", "foo@v1.4.2"},
+		{"caret", "foo@^1.2", "foo@v1.4.2"},
+		{"wildcard", "foo@v1.2.x", "foo@v1.2.5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			actual, err := r.FixRepoId(tt.repoID)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestHasRangeOperator(t *testing.T) {
+	assert.False(t, HasRangeOperator("v1.2"))
+	assert.False(t, HasRangeOperator("1"))
+	assert.True(t, HasRangeOperator("^1.2"))
+	assert.True(t, HasRangeOperator(">=2 <3"))
+	assert.True(t, HasRangeOperator("1.x"))
+	assert.True(t, HasRangeOperator(">=1.2, <2"))
+}
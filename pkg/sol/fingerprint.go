@@ -0,0 +1,355 @@
+package sol
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/apigear-io/cli/pkg/cfg"
+	"github.com/apigear-io/cli/pkg/gen"
+	"github.com/apigear-io/cli/pkg/helper"
+	"github.com/apigear-io/cli/pkg/spec"
+)
+
+// A target is up to date when its fingerprint matches the fingerprint of the
+// last successful run and all files generated by that run still exist.
+// The fingerprint covers the parsed inputs (the system checksum), all files
+// of the template and its overlays, the target configuration (including the
+// template reference, the locked commit and the overlays) and the cli version.
+// The fingerprints are stored inside the solution root dir.
+
+// StateFile is the file storing the target fingerprints, relative to the solution root dir
+const StateFile = ".apigear/state.json"
+
+// Fingerprint parts
+const (
+	FingerprintInputs    = "inputs"
+	FingerprintTemplates = "templates"
+	FingerprintConfig    = "config"
+	FingerprintCli       = "cli"
+)
+
+// Fingerprint identifies everything a target generation depends on
+type Fingerprint struct {
+	// Sum is the hash of all parts
+	Sum string `json:"sum"`
+	// Parts are the hashes of the inputs, templates, config and cli version
+	Parts map[string]string `json:"parts"`
+}
+
+// Changed returns the sorted names of the parts which differ from the other fingerprint
+func (f *Fingerprint) Changed(other *Fingerprint) []string {
+	changed := []string{}
+	for name, sum := range f.Parts {
+		if other == nil || other.Parts[name] != sum {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// targetConfig is the target configuration taking part in the fingerprint
+type targetConfig struct {
+	Name        string         `json:"name"`
+	Output      string         `json:"output"`
+	Template    string         `json:"template"`
+	Commit      string         `json:"commit"`
+	Overlays    []string       `json:"overlays"`
+	Features    []string       `json:"features"`
+	Params      map[string]any `json:"params"`
+	SolMeta     map[string]any `json:"solMeta"`
+	Meta        map[string]any `json:"meta"`
+	MetaImports map[string]any `json:"metaImports"`
+}
+
+// TargetFingerprint computes the fingerprint of the prepared target
+func TargetFingerprint(doc *spec.SolutionDoc, target *spec.SolutionTarget, pt *preparedTarget) (*Fingerprint, error) {
+	commit := ""
+	if locked := doc.LockedTemplate(target); locked != nil {
+		commit = locked.Commit
+	}
+	config, err := json.Marshal(targetConfig{
+		Name:        pt.name,
+		Output:      target.Output,
+		Template:    target.Template,
+		Commit:      commit,
+		Overlays:    target.Overlays,
+		Features:    target.Features,
+		Params:      target.Params,
+		SolMeta:     doc.Meta,
+		Meta:        target.Meta,
+		MetaImports: target.MetaImports,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// the template dir contains the rules, templates and script filters
+	dirs := []string{target.TemplateDir}
+	for _, layer := range pt.composed.Layers {
+		dirs = append(dirs, filepath.Dir(layer.Dir))
+	}
+	dirs = append(dirs, target.OverlayDirs...)
+	templates, err := hashDirs(dirs...)
+	if err != nil {
+		return nil, err
+	}
+	fp := &Fingerprint{
+		Parts: map[string]string{
+			FingerprintInputs:    pt.system.Checksum,
+			FingerprintTemplates: templates,
+			FingerprintConfig:    hashBytes(config),
+			FingerprintCli:       cfg.GetBuildInfo("cli").Version,
+		},
+	}
+	names := make([]string, 0, len(fp.Parts))
+	for name := range fp.Parts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	h := sha256.New()
+	for _, name := range names {
+		io.WriteString(h, name+"="+fp.Parts[name]+"\n")
+	}
+	fp.Sum = hex.EncodeToString(h.Sum(nil))
+	return fp, nil
+}
+
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hashDirs hashes the relative paths and contents of all files inside the dirs.
+// Hidden files and dirs (e.g. .git) are ignored.
+func hashDirs(dirs ...string) (string, error) {
+	h := sha256.New()
+	seen := map[string]bool{}
+	for _, dir := range dirs {
+		dir = filepath.Clean(dir)
+		if seen[dir] || !helper.IsDir(dir) {
+			continue
+		}
+		seen[dir] = true
+		io.WriteString(h, "dir:"+filepath.Base(dir)+"\n")
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if path != dir && strings.HasPrefix(d.Name(), ".") {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() {
+				return nil
+			}
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			io.WriteString(h, filepath.ToSlash(rel)+":"+hashBytes(data)+"\n")
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// TargetState is the state of the last successful target run
+type TargetState struct {
+	Fingerprint *Fingerprint `json:"fingerprint"`
+	// Files are the documents produced by the run, relative to the output dir
+	Files []string `json:"files"`
+}
+
+// SolutionState stores the target states of a solution root dir
+type SolutionState struct {
+	mu      sync.Mutex
+	file    string
+	dirty   bool
+	Targets map[string]*TargetState `json:"targets"`
+}
+
+// targetKey identifies a target inside the solution root dir
+func targetKey(doc *spec.SolutionDoc, target *spec.SolutionTarget) string {
+	return targetName(doc, target) + "@" + filepath.ToSlash(filepath.Clean(target.Output))
+}
+
+// ReadSolutionState reads the state file of the solution root dir.
+// A missing state file results in an empty state.
+func ReadSolutionState(rootDir string) (*SolutionState, error) {
+	s := &SolutionState{
+		file:    helper.Join(rootDir, StateFile),
+		Targets: map[string]*TargetState{},
+	}
+	data, err := os.ReadFile(s.file)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		// a broken state only results in a full run
+		log.Warn().Err(err).Msgf("ignore invalid state file %s", s.file)
+		s.Targets = map[string]*TargetState{}
+	}
+	if s.Targets == nil {
+		s.Targets = map[string]*TargetState{}
+	}
+	return s, nil
+}
+
+// Get returns the state of the target or nil
+func (s *SolutionState) Get(key string) *TargetState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Targets[key]
+}
+
+// IsUpToDate returns true if the fingerprint matches the last run
+// and all files generated by the last run exist in the output dir
+func (s *SolutionState) IsUpToDate(key string, fp *Fingerprint, outDir string) bool {
+	ts := s.Get(key)
+	if ts == nil || ts.Fingerprint == nil || ts.Fingerprint.Sum != fp.Sum {
+		return false
+	}
+	for _, file := range ts.Files {
+		if !helper.IsFile(helper.Join(outDir, file)) {
+			log.Debug().Msgf("generated file %s is missing", file)
+			return false
+		}
+	}
+	return true
+}
+
+// producedFiles returns the documents of the run which exist in the output dir,
+// including the documents skipped because of the same content or preserved
+func producedFiles(docs []gen.DocumentResult) []string {
+	files := []string{}
+	seen := map[string]bool{}
+	for _, d := range docs {
+		if d.Action == gen.DocumentFailed || seen[d.Target] {
+			continue
+		}
+		seen[d.Target] = true
+		files = append(files, d.Target)
+	}
+	return files
+}
+
+// Record stores the fingerprint and generated files of a successful run
+func (s *SolutionState) Record(key string, fp *Fingerprint, files []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Targets[key] = &TargetState{Fingerprint: fp, Files: files}
+	s.dirty = true
+}
+
+// Forget removes the target state, e.g. after a failed run
+func (s *SolutionState) Forget(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.Targets[key]; ok {
+		delete(s.Targets, key)
+		s.dirty = true
+	}
+}
+
+// Write writes the state file if the state changed
+func (s *SolutionState) Write() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.dirty {
+		return nil
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := helper.MakeDir(filepath.Dir(s.file)); err != nil {
+		return err
+	}
+	s.dirty = false
+	return helper.WriteFile(s.file, data)
+}
+
+// isIncremental returns true if the target can be skipped when up to date.
+// Forced targets and runs into diffs, archives or custom writers always generate.
+func (r *Runner) isIncremental(target *spec.SolutionTarget, out gen.OutputWriter) bool {
+	return !target.Force && out == nil && target.Archive == ""
+}
+
+// TargetStatusInfo describes whether a target needs to be generated
+type TargetStatusInfo struct {
+	Name string `json:"name"`
+	// Status is up-to-date, stale or new
+	Status string `json:"status"`
+	// Reason lists the changed fingerprint parts or missing files
+	Reason string `json:"reason"`
+}
+
+// Target status values of SolutionStatus
+const (
+	StatusUpToDate = "up-to-date"
+	StatusStale    = "stale"
+	StatusNew      = "new"
+)
+
+// SolutionStatus compares the targets of the solution with their last successful run
+func SolutionStatus(doc *spec.SolutionDoc) ([]*TargetStatusInfo, error) {
+	if err := doc.Validate(); err != nil {
+		return nil, err
+	}
+	state, err := ReadSolutionState(doc.RootDir)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]*TargetStatusInfo, 0, len(doc.Targets))
+	for _, target := range doc.Targets {
+		pt, err := prepareTarget(doc, target)
+		if err != nil {
+			return nil, err
+		}
+		fp, err := TargetFingerprint(doc, target, pt)
+		if err != nil {
+			return nil, err
+		}
+		info := &TargetStatusInfo{Name: pt.name}
+		key := targetKey(doc, target)
+		ts := state.Get(key)
+		switch {
+		case ts == nil || ts.Fingerprint == nil:
+			info.Status = StatusNew
+			info.Reason = "never generated"
+		case state.IsUpToDate(key, fp, pt.outDir):
+			info.Status = StatusUpToDate
+		default:
+			info.Status = StatusStale
+			changed := fp.Changed(ts.Fingerprint)
+			if len(changed) == 0 {
+				info.Reason = "generated files missing"
+			} else {
+				info.Reason = strings.Join(changed, ", ") + " changed"
+			}
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
@@ -0,0 +1,215 @@
+package sol
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/apigear-io/cli/pkg/gen"
+	"github.com/apigear-io/cli/pkg/helper"
+	"github.com/apigear-io/cli/pkg/spec"
+)
+
+// Hook commands are run by the shell inside the solution root dir or the
+// target output dir. The output of stdout and stderr is captured and logged
+// when the hook fails. Hooks are not run in diff mode, when writing into a
+// solution archive or a custom output writer, or when disabled.
+
+// HookStatus is the outcome of a hook run
+type HookStatus string
+
+const (
+	HookSucceeded HookStatus = "succeeded"
+	HookFailed    HookStatus = "failed"
+)
+
+// HookResult is the summary of a single hook run
+type HookResult struct {
+	Name     string        `json:"name"`
+	Command  string        `json:"command"`
+	Status   HookStatus    `json:"status"`
+	Duration time.Duration `json:"duration"`
+	// Output is the combined stdout and stderr output
+	Output string `json:"output"`
+	Err    error  `json:"-"`
+}
+
+// hooksEnabled returns true if hooks run for the output writer
+func (r *Runner) hooksEnabled(out gen.OutputWriter) bool {
+	return !r.Options.SkipHooks && out == nil
+}
+
+// HookResults returns the results of the solution hooks of the last solution run
+func (r *Runner) HookResults() []*HookResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.hookResults
+}
+
+// targetHookVars returns the hook variables of the target
+func targetHookVars(doc *spec.SolutionDoc, target *spec.SolutionTarget, name string, outDir string) map[string]string {
+	return map[string]string{
+		spec.HookVarRootDir:     doc.RootDir,
+		spec.HookVarTarget:      name,
+		spec.HookVarOutputDir:   outDir,
+		spec.HookVarTemplateDir: target.TemplateDir,
+	}
+}
+
+// runHooks runs the hooks in order until a hook with the fail policy fails.
+// The touched files are passed as hook variables.
+func runHooks(ctx context.Context, doc *spec.SolutionDoc, label string, hooks []*spec.SolutionHook, dir string, vars map[string]string, files []string) ([]*HookResult, error) {
+	if len(hooks) == 0 {
+		return nil, nil
+	}
+	if files != nil {
+		list, err := os.CreateTemp("", "apigear-files-*.txt")
+		if err != nil {
+			return nil, err
+		}
+		defer os.Remove(list.Name())
+		_, err = list.WriteString(strings.Join(files, "\n"))
+		if cerr := list.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return nil, err
+		}
+		withFiles := make(map[string]string, len(vars)+2)
+		for k, v := range vars {
+			withFiles[k] = v
+		}
+		withFiles[spec.HookVarFiles] = strings.Join(files, " ")
+		withFiles[spec.HookVarFilesList] = list.Name()
+		vars = withFiles
+	}
+	results := make([]*HookResult, 0, len(hooks))
+	for _, hook := range hooks {
+		if hook == nil {
+			continue
+		}
+		res := runHook(ctx, doc, hook, dir, vars)
+		results = append(results, res)
+		if res.Err == nil {
+			log.Info().Msgf("%s hook %s: succeeded in %s", label, res.Name, res.Duration)
+			continue
+		}
+		switch hook.Policy() {
+		case spec.HookIgnore:
+			log.Debug().Msgf("%s hook %s: ignore failure: %s", label, res.Name, res.Err)
+		case spec.HookWarn:
+			log.Warn().Msgf("%s hook %s: %s\n%s", label, res.Name, res.Err, res.Output)
+		default:
+			log.Error().Msgf("%s hook %s: %s\n%s", label, res.Name, res.Err, res.Output)
+			return results, fmt.Errorf("%s hook %s: %w", label, res.Name, res.Err)
+		}
+	}
+	return results, nil
+}
+
+// runHook runs the hook command with the hook variables as environment variables
+func runHook(ctx context.Context, doc *spec.SolutionDoc, hook *spec.SolutionHook, dir string, vars map[string]string) *HookResult {
+	res := &HookResult{Name: hook.DisplayName(), Status: HookFailed}
+	start := time.Now()
+	defer func() {
+		res.Duration = time.Since(start).Truncate(time.Millisecond)
+	}()
+	command, err := doc.ExpandHookCommand(hook.Run, vars)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	res.Command = command
+	if hook.Dir != "" {
+		dir = helper.Join(doc.RootDir, hook.Dir)
+	} else if !helper.IsDir(dir) {
+		// e.g. the output dir before the first generation
+		dir = doc.RootDir
+	}
+	env := os.Environ()
+	for k, v := range vars {
+		env = append(env, k+"="+v)
+	}
+	for k, v := range hook.Env {
+		v, err := doc.ExpandHookEnv(v, vars)
+		if err != nil {
+			res.Err = fmt.Errorf("env %s: %w", k, err)
+			return res
+		}
+		env = append(env, k+"="+v)
+	}
+	timeout := hook.TimeoutDuration()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Dir = filepath.Clean(dir)
+	cmd.Env = env
+	// do not wait for child processes keeping the output open
+	cmd.WaitDelay = time.Second
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	err = cmd.Run()
+	res.Output = strings.TrimSpace(output.String())
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		res.Err = fmt.Errorf("timed out after %s", timeout)
+		return res
+	}
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	res.Status = HookSucceeded
+	return res
+}
+
+// relFiles returns the files relative to the base dir
+func relFiles(base string, files []string) []string {
+	result := make([]string, 0, len(files))
+	for _, f := range files {
+		rel, err := filepath.Rel(base, helper.Join(base, f))
+		if err != nil {
+			rel = f
+		}
+		result = append(result, filepath.ToSlash(rel))
+	}
+	return result
+}
+
+// runSolutionHooks runs the solution hooks after all targets are generated.
+// The touched files are relative to the solution root dir.
+func (r *Runner) runSolutionHooks(ctx context.Context, doc *spec.SolutionDoc, results []*TargetResult) error {
+	if doc.Hooks == nil || len(doc.Hooks.After) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(results))
+	files := []string{}
+	for i, res := range results {
+		names = append(names, res.Name)
+		outDir := doc.Targets[i].GetOutputDir(doc.RootDir)
+		for _, f := range res.Files {
+			files = append(files, helper.Join(outDir, f))
+		}
+	}
+	vars := map[string]string{
+		spec.HookVarRootDir: doc.RootDir,
+		spec.HookVarTargets: strings.Join(names, " "),
+	}
+	hooks, err := runHooks(ctx, doc, "solution", doc.Hooks.After, doc.RootDir, vars, relFiles(doc.RootDir, files))
+	r.mu.Lock()
+	r.hookResults = hooks
+	r.mu.Unlock()
+	return err
+}
@@ -8,10 +8,10 @@ import (
 	"github.com/apigear-io/cli/pkg/model"
 )
 
-// parseInputs parses the inputs from the layer.
+// ParseInputs parses the inputs from the layer.
 // A input can be either a file or a directory.
 // If the input is a directory, the files in the directory will be parsed.
-func parseInputs(s *model.System, inputs []string) error {
+func ParseInputs(s *model.System, inputs []string) error {
 	log.Info().Msgf("parse inputs %v", inputs)
 	for _, file := range inputs {
 		log.Debug().Msgf("parse input %s", file)
@@ -10,6 +10,12 @@ import (
 )
 
 func ReadSolutionDoc(file string) (*spec.SolutionDoc, error) {
+	return ReadSolutionDocWithOptions(file, spec.ResolveOptions{})
+}
+
+// ReadSolutionDocWithOptions reads the solution document and resolves
+// its variables using the given profile and variables.
+func ReadSolutionDocWithOptions(file string, opts spec.ResolveOptions) (*spec.SolutionDoc, error) {
 	data, err := os.ReadFile(file)
 	if err != nil {
 		return nil, err
@@ -19,6 +25,11 @@ func ReadSolutionDoc(file string) (*spec.SolutionDoc, error) {
 	if err != nil {
 		return nil, err
 	}
+	// resolve first, as the root dir can contain variables
+	err = doc.Resolve(opts)
+	if err != nil {
+		return nil, err
+	}
 	// set the root dir
 	if doc.RootDir == "" {
 		doc.RootDir = filepath.Dir(file)
@@ -33,3 +44,18 @@ func ReadSolutionDoc(file string) (*spec.SolutionDoc, error) {
 	}
 	return doc, nil
 }
+
+// WriteLockFile writes the lock file of the solution if templates were locked
+// or targets were removed since the lock file was written
+func WriteLockFile(doc *spec.SolutionDoc) error {
+	lock, err := doc.LockFile()
+	if err != nil {
+		return err
+	}
+	lock.Prune(doc.LockKeys())
+	if !lock.Changed() {
+		return nil
+	}
+	log.Info().Msgf("write lock file %s", lock.File())
+	return lock.Write()
+}
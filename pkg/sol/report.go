@@ -0,0 +1,408 @@
+package sol
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/apigear-io/cli/pkg/cfg"
+	"github.com/apigear-io/cli/pkg/gen"
+	"github.com/apigear-io/cli/pkg/helper"
+)
+
+// A report captures the results of a solution run for CI dashboards.
+// It is written after each run, also when the run failed, as JSON,
+// JUnit XML (one test suite per target, one test case per document and hook)
+// or SARIF (warnings and failures as results).
+
+// Report formats
+const (
+	ReportJSON  = "json"
+	ReportJUnit = "junit"
+	ReportSARIF = "sarif"
+)
+
+// ReportFormatFromFile returns the report format of the file extension:
+// .xml is JUnit, .sarif is SARIF and any other extension is JSON
+func ReportFormatFromFile(file string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".xml":
+		return ReportJUnit
+	case ".sarif":
+		return ReportSARIF
+	}
+	return ReportJSON
+}
+
+// Report is the result of a solution run
+type Report struct {
+	// Solution is the solution root dir
+	Solution string    `json:"solution"`
+	Version  string    `json:"version"`
+	Status   string    `json:"status"`
+	Error    string    `json:"error,omitempty"`
+	Started  time.Time `json:"started"`
+	// Duration is the run duration, as all durations in nanoseconds
+	Duration time.Duration   `json:"duration"`
+	Targets  []*ReportTarget `json:"targets"`
+	// Hooks are the results of the solution hooks
+	Hooks []*ReportHook `json:"hooks"`
+}
+
+// ReportTarget is the result of a target with the error messages
+// of the target and its hooks
+type ReportTarget struct {
+	*TargetResult
+	Error string        `json:"error,omitempty"`
+	Hooks []*ReportHook `json:"hooks"`
+}
+
+// ReportHook is the result of a hook with its error message
+type ReportHook struct {
+	*HookResult
+	Error string `json:"error,omitempty"`
+}
+
+// NewReport creates the report of the last run of the solution root dir
+func (r *Runner) NewReport(solution string, started time.Time, err error) *Report {
+	report := &Report{
+		Solution: solution,
+		Version:  cfg.GetBuildInfo("cli").Version,
+		Status:   string(TargetSucceeded),
+		Started:  started,
+		Duration: time.Since(started).Truncate(time.Millisecond),
+		Targets:  []*ReportTarget{},
+		Hooks:    reportHooks(r.HookResults()),
+	}
+	if err != nil {
+		report.Status = string(TargetFailed)
+		report.Error = err.Error()
+	}
+	for _, res := range r.Results() {
+		if res == nil {
+			continue
+		}
+		target := &ReportTarget{TargetResult: res, Hooks: reportHooks(res.Hooks)}
+		if res.Err != nil {
+			target.Error = res.Err.Error()
+		}
+		report.Targets = append(report.Targets, target)
+	}
+	return report
+}
+
+func reportHooks(hooks []*HookResult) []*ReportHook {
+	result := make([]*ReportHook, 0, len(hooks))
+	for _, h := range hooks {
+		hook := &ReportHook{HookResult: h}
+		if h.Err != nil {
+			hook.Error = h.Err.Error()
+		}
+		result = append(result, hook)
+	}
+	return result
+}
+
+// WriteReport writes the report in the given format to the file.
+// An empty format is derived from the file extension.
+func WriteReport(file string, format string, report *Report) error {
+	if format == "" {
+		format = ReportFormatFromFile(file)
+	}
+	var data []byte
+	var err error
+	switch format {
+	case ReportJSON:
+		data, err = json.MarshalIndent(report, "", "  ")
+	case ReportJUnit:
+		data, err = report.JUnit()
+	case ReportSARIF:
+		data, err = report.SARIF()
+	default:
+		return fmt.Errorf("invalid report format %s, expected json, junit or sarif", format)
+	}
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(file); dir != "" {
+		if err := helper.MakeDir(dir); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(file, data, 0644)
+}
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Skipped   int         `xml:"skipped,attr"`
+	Time      string      `xml:"time,attr"`
+	Cases     []junitCase `xml:"testcase"`
+	SystemOut string      `xml:"system-out,omitempty"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func junitTime(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+func (s *junitSuite) add(c junitCase) {
+	s.Tests++
+	if c.Failure != nil {
+		s.Failures++
+	}
+	if c.Skipped != nil {
+		s.Skipped++
+	}
+	s.Cases = append(s.Cases, c)
+}
+
+func junitHookCase(classname string, h *ReportHook) junitCase {
+	c := junitCase{
+		Name:      "hook " + h.Name,
+		ClassName: classname,
+		Time:      junitTime(h.Duration),
+		SystemOut: h.Output,
+	}
+	if h.Status == HookFailed {
+		c.Failure = &junitMessage{Message: h.Error, Text: h.Command}
+	}
+	return c
+}
+
+// JUnit returns the report as JUnit XML with a test suite per target
+// and a test case per document and hook
+func (r *Report) JUnit() ([]byte, error) {
+	suites := junitSuites{
+		Name: "apigear",
+		Time: junitTime(r.Duration),
+	}
+	for _, t := range r.Targets {
+		suite := junitSuite{Name: t.Name, Time: junitTime(t.Duration)}
+		failed := false
+		for _, doc := range t.Documents {
+			c := junitCase{
+				Name:      path.Join(t.OutputDir, filepath.ToSlash(doc.Target)),
+				ClassName: t.Name,
+				Time:      junitTime(doc.Duration),
+			}
+			if doc.Action == gen.DocumentFailed {
+				c.Failure = &junitMessage{Message: doc.Error, Text: doc.Source}
+				failed = true
+			}
+			suite.add(c)
+		}
+		for _, h := range t.Hooks {
+			c := junitHookCase(t.Name, h)
+			failed = failed || c.Failure != nil
+			suite.add(c)
+		}
+		switch {
+		case t.Status == TargetFailed && !failed:
+			// e.g. an invalid input or template
+			suite.add(junitCase{
+				Name:      "generate",
+				ClassName: t.Name,
+				Time:      junitTime(t.Duration),
+				Failure:   &junitMessage{Message: t.Error},
+			})
+		case t.Status == TargetSkipped || t.Status == TargetUpToDate:
+			suite.add(junitCase{
+				Name:      "generate",
+				ClassName: t.Name,
+				Time:      junitTime(t.Duration),
+				Skipped:   &junitMessage{Message: string(t.Status)},
+			})
+		}
+		warnings := make([]string, 0, len(t.Warnings))
+		for _, w := range t.Warnings {
+			warnings = append(warnings, fmt.Sprintf("warning [%s]: %s", w.Rule, w.Message))
+		}
+		suite.SystemOut = strings.Join(warnings, "\n")
+		suites.Suites = append(suites.Suites, suite)
+	}
+	if len(r.Hooks) > 0 {
+		suite := junitSuite{Name: "solution"}
+		var d time.Duration
+		for _, h := range r.Hooks {
+			d += h.Duration
+			suite.add(junitHookCase("solution", h))
+		}
+		suite.Time = junitTime(d)
+		suites.Suites = append(suites.Suites, suite)
+	}
+	for _, s := range suites.Suites {
+		suites.Tests += s.Tests
+		suites.Failures += s.Failures
+		suites.Skipped += s.Skipped
+	}
+	data, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// SARIF rules of failures, the warning rules are defined by the targets
+const (
+	sarifDocumentFailed = "document-failed"
+	sarifTargetFailed   = "target-failed"
+	sarifHookFailed     = "hook-failed"
+)
+
+var sarifRules = []sarifRule{
+	{ID: WarningReservedKeyword, Description: sarifText{Text: "A model name is a reserved keyword in a template language"}},
+	{ID: WarningEngine, Description: sarifText{Text: "The cli does not meet the engine requirements of the template"}},
+	{ID: sarifDocumentFailed, Description: sarifText{Text: "A document could not be generated"}},
+	{ID: sarifTargetFailed, Description: sarifText{Text: "A target could not be generated"}},
+	{ID: sarifHookFailed, Description: sarifText{Text: "A hook command failed"}},
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID          string    `json:"id"`
+	Description sarifText `json:"shortDescription"`
+}
+
+type sarifText struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifText       `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	Physical sarifPhysical `json:"physicalLocation"`
+}
+
+type sarifPhysical struct {
+	Artifact sarifArtifact `json:"artifactLocation"`
+}
+
+type sarifArtifact struct {
+	URI string `json:"uri"`
+}
+
+// SARIF returns the warnings and failures of the report as SARIF log.
+// Document locations are relative to the solution root dir.
+func (r *Report) SARIF() ([]byte, error) {
+	results := []sarifResult{}
+	for _, t := range r.Targets {
+		for _, w := range t.Warnings {
+			results = append(results, sarifResult{
+				RuleID:  w.Rule,
+				Level:   "warning",
+				Message: sarifText{Text: fmt.Sprintf("target %s: %s", t.Name, w.Message)},
+			})
+		}
+		failed := false
+		for _, doc := range t.Documents {
+			if doc.Action != gen.DocumentFailed {
+				continue
+			}
+			failed = true
+			results = append(results, sarifResult{
+				RuleID:  sarifDocumentFailed,
+				Level:   "error",
+				Message: sarifText{Text: doc.Error},
+				Locations: []sarifLocation{{
+					Physical: sarifPhysical{Artifact: sarifArtifact{URI: path.Join(t.OutputDir, filepath.ToSlash(doc.Target))}},
+				}},
+			})
+		}
+		for _, h := range t.Hooks {
+			if h.Status == HookFailed {
+				failed = true
+				results = append(results, sarifHookResult(t.Name, h))
+			}
+		}
+		if t.Status == TargetFailed && !failed {
+			results = append(results, sarifResult{
+				RuleID:  sarifTargetFailed,
+				Level:   "error",
+				Message: sarifText{Text: fmt.Sprintf("target %s: %s", t.Name, t.Error)},
+			})
+		}
+	}
+	for _, h := range r.Hooks {
+		if h.Status == HookFailed {
+			results = append(results, sarifHookResult("solution", h))
+		}
+	}
+	sarif := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "apigear",
+				Version:        r.Version,
+				InformationURI: "https://apigear.io",
+				Rules:          sarifRules,
+			}},
+			Results: results,
+		}},
+	}
+	return json.MarshalIndent(sarif, "", "  ")
+}
+
+func sarifHookResult(scope string, h *ReportHook) sarifResult {
+	return sarifResult{
+		RuleID:  sarifHookFailed,
+		Level:   "error",
+		Message: sarifText{Text: fmt.Sprintf("%s hook %s: %s", scope, h.Name, h.Error)},
+	}
+}
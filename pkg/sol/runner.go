@@ -2,6 +2,10 @@ package sol
 
 import (
 	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/apigear-io/cli/pkg/cfg"
 	"github.com/apigear-io/cli/pkg/gen"
@@ -11,9 +15,71 @@ import (
 	"github.com/apigear-io/cli/pkg/tasks"
 )
 
+// RunOptions are applied to every solution run of a runner.
+type RunOptions struct {
+	// Jobs is the number of documents processed concurrently, split between
+	// the targets run concurrently and the documents of each target.
+	// Zero uses the number of CPUs.
+	Jobs int
+	// Diff records the changes as unified diffs instead of writing files
+	Diff *gen.DiffWriter
+	// DiffRemoved reports files in the target output dirs,
+	// which are not generated, as removed in the diff
+	DiffRemoved bool
+	// Output overrides the output writer of all targets (e.g. a gen.MemoryWriter)
+	Output gen.OutputWriter
+	// Archive is a zip or tar.gz file all targets are written into.
+	// The archive entries are relative to the solution root dir.
+	Archive string
+	// KeepGoing reports all failing targets and documents
+	// instead of stopping at the first failure
+	KeepGoing bool
+	// FormatCommands enables external formatter commands declared in template rules
+	FormatCommands bool
+	// Profile is the solution profile to apply
+	Profile string
+	// Variables override the solution variables
+	Variables map[string]string
+	// Locked fails for targets whose template is not pinned by the lock file
+	// and never writes the lock file (e.g. in CI)
+	Locked bool
+	// WatchInclude are glob patterns of the files watched inside dirs,
+	// empty watches all files
+	WatchInclude []string
+	// WatchExclude are glob patterns of the files and dirs ignored by the watch
+	WatchExclude []string
+	// WatchDebounce is the quiet period after the last change
+	// before a watched solution runs again
+	WatchDebounce time.Duration
+	// SkipHooks disables the hook commands of the solution and its targets
+	SkipHooks bool
+	// Report is a file the results of each run are written to
+	Report string
+	// ReportFormat is the format of the report (json, junit or sarif).
+	// Empty derives the format from the report file extension.
+	ReportFormat string
+}
+
+// resolveOptions returns the options to resolve the solution variables
+func (o RunOptions) resolveOptions() spec.ResolveOptions {
+	opts := spec.ResolveOptions{
+		Profile:   o.Profile,
+		Variables: o.Variables,
+	}
+	if o.Locked {
+		opts.Lock = spec.LockFrozen
+	}
+	return opts
+}
+
 type Runner struct {
 	tm *tasks.TaskManager
 	// tasks map[string]*task
+	Options RunOptions
+	// mu guards the results
+	mu          sync.Mutex
+	results     []*TargetResult
+	hookResults []*HookResult
 }
 
 func NewRunner() *Runner {
@@ -50,7 +116,7 @@ func (r *Runner) RunSource(ctx context.Context, source string, force bool) error
 // It should not act on a cached value.
 func (r *Runner) RunDoc(ctx context.Context, file string, doc *spec.SolutionDoc) error {
 	task := func(ctx context.Context) error {
-		return runSolution(doc)
+		return r.runSolution(ctx, doc)
 	}
 	meta := map[string]interface{}{
 		"solution": file,
@@ -59,13 +125,14 @@ func (r *Runner) RunDoc(ctx context.Context, file string, doc *spec.SolutionDoc)
 	return r.tm.Run(ctx, file)
 }
 
+// WatchSource runs the solution and runs it again after changes.
+// The watched files are read again from the solution after each run,
+// so new targets, inputs and templates are picked up.
 func (r *Runner) WatchSource(ctx context.Context, source string, force bool) error {
-	doc, err := ReadSolutionDoc(source)
+	_, err := ReadSolutionDocWithOptions(source, r.Options.resolveOptions())
 	if err != nil {
 		return err
 	}
-	deps := doc.AggregateDependencies()
-	deps = append(deps, source)
 	task := func(ctx context.Context) error {
 		return r.runSolutionFromSource(ctx, source, force)
 	}
@@ -73,24 +140,89 @@ func (r *Runner) WatchSource(ctx context.Context, source string, force bool) err
 		"solution": source,
 	}
 	r.tm.Register(source, meta, task)
-	return r.tm.Watch(ctx, source, deps...)
+	return r.tm.WatchWith(ctx, source, r.watchOptions(func() (*spec.SolutionDoc, error) {
+		return ReadSolutionDocWithOptions(source, r.Options.resolveOptions())
+	}, source))
 }
 
 // WatchDoc starts the watch of the given file task.
 func (r *Runner) WatchDoc(ctx context.Context, file string, doc *spec.SolutionDoc) error {
+	if err := doc.Resolve(r.Options.resolveOptions()); err != nil {
+		return err
+	}
 	if err := doc.Validate(); err != nil {
 		return err
 	}
-	deps := doc.AggregateDependencies()
-	deps = append(deps, file)
 	task := func(ctx context.Context) error {
-		return runSolution(doc)
+		// pick up input files added or removed since the last run
+		if err := doc.RefreshDependencies(); err != nil {
+			return err
+		}
+		return r.runSolution(ctx, doc)
 	}
 	meta := map[string]interface{}{
 		"solution": file,
 	}
 	r.tm.Register(file, meta, task)
-	return r.tm.Watch(ctx, file, deps...)
+	return r.tm.WatchWith(ctx, file, r.watchOptions(func() (*spec.SolutionDoc, error) {
+		// the spec is read after each run, so the inputs are expanded again
+		if err := doc.RefreshDependencies(); err != nil {
+			return nil, err
+		}
+		return doc, nil
+	}, file))
+}
+
+// watchOptions returns the watch options of a solution.
+// The watched files are the solution file, the template and overlay dirs,
+// the inputs and the input dirs, to pick up new input files.
+// The files written by a run are ignored, as they often are inside
+// the input dirs and would run the solution again.
+func (r *Runner) watchOptions(read func() (*spec.SolutionDoc, error), file string) tasks.WatchOptions {
+	return tasks.WatchOptions{
+		Debounce: r.Options.WatchDebounce,
+		Spec: func() (*tasks.WatchSpec, error) {
+			doc, err := read()
+			if err != nil {
+				return nil, err
+			}
+			paths := doc.AggregateDependencies()
+			for _, target := range doc.Targets {
+				for _, input := range target.Inputs {
+					if dir := helper.Join(doc.RootDir, input); helper.IsDir(dir) {
+						paths = append(paths, dir)
+					}
+				}
+			}
+			paths = append(paths, file)
+			return &tasks.WatchSpec{
+				Paths:   paths,
+				Include: r.Options.WatchInclude,
+				Exclude: r.Options.WatchExclude,
+				Ignore:  r.writtenPaths(doc),
+			}, nil
+		},
+	}
+}
+
+// writtenPaths returns the files and dirs written by a solution run
+func (r *Runner) writtenPaths(doc *spec.SolutionDoc) []string {
+	paths := []string{
+		helper.Join(doc.RootDir, spec.LockFileName),
+		helper.Join(doc.RootDir, filepath.Dir(StateFile)),
+	}
+	for _, target := range doc.Targets {
+		paths = append(paths, target.GetOutputDir(doc.RootDir))
+		if target.Archive != "" {
+			paths = append(paths, helper.Join(doc.RootDir, target.Archive))
+		}
+	}
+	for _, file := range []string{r.Options.Report, r.Options.Archive} {
+		if file != "" {
+			paths = append(paths, file)
+		}
+	}
+	return paths
 }
 
 // StopWatch stops the watch of the given file task.
@@ -105,9 +237,16 @@ func (r *Runner) Clear() {
 	r.tm.CancelAll()
 }
 
-func (r *Runner) runSolutionFromSource(_ context.Context, source string, force bool) error {
-	doc, err := ReadSolutionDoc(source)
+func (r *Runner) runSolutionFromSource(ctx context.Context, source string, force bool) error {
+	started := time.Now()
+	doc, err := ReadSolutionDocWithOptions(source, r.Options.resolveOptions())
 	if err != nil {
+		r.resetResults()
+		rootDir, aerr := filepath.Abs(filepath.Dir(source))
+		if aerr != nil {
+			rootDir = filepath.Dir(source)
+		}
+		r.writeReport(rootDir, started, err)
 		return err
 	}
 	if force {
@@ -115,66 +254,280 @@ func (r *Runner) runSolutionFromSource(_ context.Context, source string, force b
 			target.Force = true
 		}
 	}
-	return runSolution(doc)
+	return r.runSolution(ctx, doc)
 }
 
-func runSolution(doc *spec.SolutionDoc) error {
+// runSolution generates the solution and writes the report of the run,
+// also when the run failed before any target was generated
+func (r *Runner) runSolution(ctx context.Context, doc *spec.SolutionDoc) error {
+	started := time.Now()
+	r.resetResults()
+	err := r.generateSolution(ctx, doc)
+	r.writeReport(doc.RootDir, started, err)
+	return err
+}
+
+func (r *Runner) generateSolution(ctx context.Context, doc *spec.SolutionDoc) (err error) {
 	log.Info().Msgf("run solution %s", doc.RootDir)
+	if err := doc.Resolve(r.Options.resolveOptions()); err != nil {
+		return err
+	}
 	if err := doc.Validate(); err != nil {
 		return err
 	}
-	rootDir := doc.RootDir
+	out, archive, err := r.solutionOutput(doc)
+	if err != nil {
+		return err
+	}
+	// templates are locked while the document is validated,
+	// only runs generating into the output dirs write the lock file
+	if !r.Options.Locked && out == nil {
+		if err := WriteLockFile(doc); err != nil {
+			return err
+		}
+	}
+	if archive != nil {
+		defer func() {
+			err = closeArchive(archive, err)
+		}()
+	}
+	err = r.runTargets(ctx, doc, out)
+	if err == nil && r.hooksEnabled(out) {
+		err = r.runSolutionHooks(ctx, doc, r.Results())
+	}
+	if err != nil {
+		return err
+	}
+	if r.Options.Diff != nil && r.Options.DiffRemoved {
+		return r.Options.Diff.DetectRemoved(r.diffOutputDirs(doc)...)
+	}
+	return nil
+}
+
+// closeArchive writes the archive after a successful run.
+// After a failed run the partial archive is discarded.
+func closeArchive(archive *gen.ArchiveWriter, err error) error {
+	if err != nil {
+		archive.Discard()
+		return err
+	}
+	return archive.Close()
+}
+
+// resetResults clears the target and hook results of the previous run
+func (r *Runner) resetResults() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.results = nil
+	r.hookResults = nil
+}
+
+// writeReport writes the report of the run, if a report file is configured.
+// A failing report is logged and does not fail the run.
+func (r *Runner) writeReport(solution string, started time.Time, err error) {
+	if r.Options.Report == "" {
+		return
+	}
+	report := r.NewReport(solution, started, err)
+	if rerr := WriteReport(r.Options.Report, r.Options.ReportFormat, report); rerr != nil {
+		log.Error().Err(rerr).Msgf("write report %s", r.Options.Report)
+		return
+	}
+	log.Info().Msgf("report written to %s", r.Options.Report)
+}
 
+// solutionOutput returns the output writer shared by all targets.
+// A nil writer lets each target decide about its output.
+func (r *Runner) solutionOutput(doc *spec.SolutionDoc) (gen.OutputWriter, *gen.ArchiveWriter, error) {
+	if r.Options.Diff != nil {
+		// diff paths are relative to the solution root dir
+		r.Options.Diff.SetBaseDir(doc.RootDir)
+		return r.Options.Diff, nil, nil
+	}
+	if r.Options.Output != nil {
+		return r.Options.Output, nil, nil
+	}
+	if r.Options.Archive != "" {
+		archive, err := gen.NewArchiveWriter(r.Options.Archive, doc.RootDir)
+		if err != nil {
+			return nil, nil, err
+		}
+		return archive, archive, nil
+	}
+	return nil, nil, nil
+}
+
+// diffOutputDirs returns the target output dirs checked for removed files.
+// An output dir equal to the solution root dir is not checked,
+// as it contains also the solution and input documents.
+func (r *Runner) diffOutputDirs(doc *spec.SolutionDoc) []string {
+	dirs := []string{}
 	for _, target := range doc.Targets {
-		name := target.Name
-		outDir := target.GetOutputDir(rootDir)
-		if name == "" {
-			name = helper.BaseName(outDir)
-		}
-		system := model.NewSystem(name)
-		doc.Meta["Layer"] = target
-		doc.Meta["App"] = cfg.GetBuildInfo("cli")
-		system.Meta = helper.JoinMaps(doc.Meta, target.Meta)
-		if err := parseInputs(system, target.ExpandedInputs()); err != nil {
-			return err
+		// inputs are never reported as removed
+		for _, input := range target.ExpandedInputs() {
+			r.Options.Diff.Skip(input)
 		}
-		applyMetaDocument(target, system)
-		if err := helper.MakeDir(outDir); err != nil {
+	}
+	for _, target := range doc.Targets {
+		outDir := target.GetOutputDir(doc.RootDir)
+		if filepath.Clean(outDir) == filepath.Clean(doc.RootDir) {
+			log.Debug().Msgf("skip removed files detection for %s", outDir)
+			continue
+		}
+		dirs = append(dirs, outDir)
+	}
+	return dirs
+}
+
+// preparedTarget is a target with parsed inputs and composed rules
+type preparedTarget struct {
+	name     string
+	outDir   string
+	system   *model.System
+	meta     map[string]any
+	composed *gen.ComposedRules
+}
+
+// prepareTarget parses the inputs and composes the rules of the target
+func prepareTarget(doc *spec.SolutionDoc, target *spec.SolutionTarget) (*preparedTarget, error) {
+	name := targetName(doc, target)
+	system := model.NewSystem(name)
+	// copy the solution meta, as targets run concurrently
+	meta := helper.JoinMaps(doc.Meta, map[string]any{
+		"Layer": target,
+		"App":   cfg.GetBuildInfo("cli"),
+	})
+	system.Meta = helper.JoinMaps(meta, target.Meta)
+	if err := ParseInputs(system, target.ExpandedInputs()); err != nil {
+		return nil, err
+	}
+	applyMetaDocument(target, system)
+	composed, err := gen.ComposeRules(target.TemplateDir, target.OverlayDirs)
+	if err != nil {
+		return nil, err
+	}
+	return &preparedTarget{
+		name:     name,
+		outDir:   target.GetOutputDir(doc.RootDir),
+		system:   system,
+		meta:     meta,
+		composed: composed,
+	}, nil
+}
+
+// runTarget generates the code for a single solution target.
+// Without an output writer the files are written into the target archive
+// or the target output dir. Targets whose fingerprint matches the last
+// successful run are skipped, unless forced. The target hooks run before
+// and after the generation.
+func (r *Runner) runTarget(ctx context.Context, doc *spec.SolutionDoc, target *spec.SolutionTarget, out gen.OutputWriter, state *SolutionState, result *TargetResult) (err error) {
+	pt, err := prepareTarget(doc, target)
+	if err != nil {
+		return err
+	}
+	name := pt.name
+	outDir := pt.outDir
+	incremental := r.isIncremental(target, out)
+	var fp *Fingerprint
+	if incremental {
+		fp, err = TargetFingerprint(doc, target, pt)
+		if err != nil {
 			return err
 		}
-		opts := gen.Options{
-			OutputDir:    outDir,
-			TemplatesDir: target.TemplatesDir,
-			System:       system,
-			Features:     target.Features,
-			Force:        target.Force,
-			Meta:         helper.JoinMaps(doc.Meta, target.Meta),
+		if state.IsUpToDate(targetKey(doc, target), fp, outDir) {
+			log.Info().Msgf("target %s is up to date", name)
+			result.Status = TargetUpToDate
+			return nil
 		}
-		g, err := gen.New(opts)
+	}
+	// incompatible templates fail before any hook is run or file is written
+	warnings, err := gen.CheckEngine(pt.composed.Doc, gen.Engine(), doc.EnginePolicy)
+	if err != nil {
+		return fmt.Errorf("target %s: %w", name, err)
+	}
+	for _, msg := range warnings {
+		result.Warnings = append(result.Warnings, TargetWarning{Rule: WarningEngine, Message: msg})
+	}
+	hooks := r.hooksEnabled(out) && target.Hooks != nil
+	vars := targetHookVars(doc, target, name, outDir)
+	if hooks {
+		res, err := runHooks(ctx, doc, "target "+name+" before", target.Hooks.Before, outDir, vars, nil)
+		result.Hooks = append(result.Hooks, res...)
 		if err != nil {
+			if incremental {
+				state.Forget(targetKey(doc, target))
+			}
 			return err
 		}
-		doc, err := gen.ReadRulesDoc(target.RulesFile)
+	}
+	var archive *gen.ArchiveWriter
+	if out == nil && target.Archive != "" {
+		archive, err = gen.NewArchiveWriter(helper.Join(doc.RootDir, target.Archive), outDir)
 		if err != nil {
-			return err
+			return fmt.Errorf("target %s: %w", name, err)
 		}
-		bi := cfg.GetBuildInfo("cli")
-		ok, errs := doc.CheckEngines(bi.Version)
-		if !ok {
-			// a warning should be enough
-			log.Warn().Msgf("template requires cli version %s. Only found %s", doc.Engines.Cli, bi.Version)
-			for _, err := range errs {
-				log.Warn().Err(err).Msg("cli version mismatch")
+		out = archive
+		// discard the archive, when the target fails before it is written
+		defer func() {
+			if archive != nil {
+				err = closeArchive(archive, err)
 			}
+		}()
+	}
+	if out == nil {
+		if err := helper.MakeDir(outDir); err != nil {
+			return err
 		}
-		// check keywords according to the rules languages
-		system.CheckReservedWords(doc.Languages)
-		err = g.ProcessRules(doc)
+	}
+	opts := gen.Options{
+		OutputDir:      outDir,
+		TemplatesDir:   target.TemplatesDir,
+		Overlays:       target.OverlayDirs,
+		Layers:         pt.composed.Layers,
+		System:         pt.system,
+		Features:       target.Features,
+		Force:          target.Force,
+		Meta:           helper.JoinMaps(pt.meta, target.Meta),
+		Jobs:           r.documentJobs(doc),
+		KeepGoing:      r.Options.KeepGoing,
+		FormatCommands: r.Options.FormatCommands,
+		Params:         target.Params,
+		Output:         out,
+		Context:        ctx,
+	}
+	g, err := gen.New(opts)
+	if err != nil {
+		return err
+	}
+	rules := pt.composed.Doc
+	// check keywords according to the rules languages
+	for _, msg := range pt.system.CheckReservedWords(rules.Languages) {
+		result.Warnings = append(result.Warnings, TargetWarning{Rule: WarningReservedKeyword, Message: msg})
+	}
+	err = g.ProcessRules(rules)
+	result.FilesWritten = g.Stats.FilesWritten
+	result.FilesSkipped = g.Stats.FilesSkipped
+	result.FilesCopied = g.Stats.FilesCopied
+	result.Files = relFiles(outDir, g.Stats.FilesTouched)
+	result.Documents = g.Stats.Documents
+	// the archive is written before the after hooks run
+	if archive != nil {
+		err = closeArchive(archive, err)
+		archive = nil
+	}
+	if err == nil && hooks {
+		var res []*HookResult
+		res, err = runHooks(ctx, doc, "target "+name+" after", target.Hooks.After, outDir, vars, result.Files)
+		result.Hooks = append(result.Hooks, res...)
+	}
+	if incremental {
 		if err != nil {
-			return err
+			state.Forget(targetKey(doc, target))
+		} else {
+			state.Record(targetKey(doc, target), fp, producedFiles(g.Stats.Documents))
 		}
 	}
-	return nil
+	return err
 }
 
 func applyMetaDocument(t *spec.SolutionTarget, s *model.System) {
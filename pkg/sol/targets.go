@@ -0,0 +1,206 @@
+package sol
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"time"
+
+	"github.com/apigear-io/cli/pkg/gen"
+	"github.com/apigear-io/cli/pkg/helper"
+	"github.com/apigear-io/cli/pkg/spec"
+	"github.com/apigear-io/cli/pkg/tasks"
+)
+
+// Targets can depend on other targets by name. The runner generates
+// independent targets concurrently and starts a target after all targets
+// it depends on succeeded. Targets depending on a failed target are skipped.
+// Each target reports its progress as task event using the target name.
+
+// TargetStatus is the outcome of a target run
+type TargetStatus string
+
+const (
+	TargetSucceeded TargetStatus = "succeeded"
+	TargetFailed    TargetStatus = "failed"
+	TargetSkipped   TargetStatus = "skipped"
+	// TargetUpToDate is a target not generated, as nothing changed since the last run
+	TargetUpToDate TargetStatus = "up-to-date"
+)
+
+// Warning rules reported by a target run
+const (
+	// WarningReservedKeyword is a model name which is a reserved keyword in a template language
+	WarningReservedKeyword = "reserved-keyword"
+	// WarningEngine is an engine requirement of the template not met by the cli
+	WarningEngine = "engine"
+)
+
+// TargetWarning is a warning reported while generating a target
+type TargetWarning struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// TargetResult is the summary of a single target run
+type TargetResult struct {
+	Name string `json:"name"`
+	// Template is the template reference of the target
+	Template string `json:"template"`
+	// OutputDir is the output dir relative to the solution root dir
+	OutputDir    string        `json:"output_dir"`
+	Status       TargetStatus  `json:"status"`
+	Duration     time.Duration `json:"duration"`
+	FilesWritten int           `json:"files_written"`
+	FilesSkipped int           `json:"files_skipped"`
+	FilesCopied  int           `json:"files_copied"`
+	// Files are the touched files relative to the output dir
+	Files []string `json:"files"`
+	// Documents are the results of the generated documents
+	Documents []gen.DocumentResult `json:"documents"`
+	Warnings  []TargetWarning      `json:"warnings"`
+	// Hooks are the results of the target hooks
+	Hooks []*HookResult `json:"hooks"`
+	Err   error         `json:"-"`
+}
+
+// Results returns the target results of the last solution run
+func (r *Runner) Results() []*TargetResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.results
+}
+
+// targetName returns the target name, which defaults to the output dir name
+func targetName(doc *spec.SolutionDoc, target *spec.SolutionTarget) string {
+	if target.Name != "" {
+		return target.Name
+	}
+	return helper.BaseName(target.GetOutputDir(doc.RootDir))
+}
+
+// newTargetResult returns the result of a target not yet run
+func newTargetResult(doc *spec.SolutionDoc, target *spec.SolutionTarget) *TargetResult {
+	outDir, err := filepath.Rel(doc.RootDir, target.GetOutputDir(doc.RootDir))
+	if err != nil {
+		outDir = target.Output
+	}
+	return &TargetResult{
+		Name:      targetName(doc, target),
+		Template:  target.Template,
+		OutputDir: filepath.ToSlash(outDir),
+	}
+}
+
+// documentJobs returns the number of documents a target renders concurrently,
+// so the targets together stay inside the jobs budget
+func (r *Runner) documentJobs(doc *spec.SolutionDoc) int {
+	_, jobs := helper.SplitJobs(r.Options.Jobs, len(doc.Targets))
+	return jobs
+}
+
+// runTargets generates the targets in dependency order.
+// After the context is cancelled no further targets are started.
+func (r *Runner) runTargets(ctx context.Context, doc *spec.SolutionDoc, out gen.OutputWriter) error {
+	graph, err := doc.DependencyGraph()
+	if err != nil {
+		return err
+	}
+	state, err := ReadSolutionState(doc.RootDir)
+	if err != nil {
+		return err
+	}
+	results := make([]*TargetResult, len(doc.Targets))
+	targetJobs, _ := helper.SplitJobs(r.Options.Jobs, len(doc.Targets))
+	errs := helper.RunGraph(graph, targetJobs, r.Options.KeepGoing, func(i int) error {
+		if ctx.Err() != nil {
+			return helper.ErrSkipped
+		}
+		target := doc.Targets[i]
+		result := newTargetResult(doc, target)
+		results[i] = result
+		r.fireTarget(doc, target, result, tasks.TaskStateRunning)
+		start := time.Now()
+		err := r.runTarget(ctx, doc, target, out, state, result)
+		result.Duration = time.Since(start).Truncate(time.Millisecond)
+		result.Err = err
+		if err != nil {
+			result.Status = TargetFailed
+			r.fireTarget(doc, target, result, tasks.TaskStateFailed)
+			return err
+		}
+		if result.Status == "" {
+			result.Status = TargetSucceeded
+		}
+		r.fireTarget(doc, target, result, tasks.TaskStateFinished)
+		return nil
+	})
+	failed := []error{}
+	for i, err := range errs {
+		if errors.Is(err, helper.ErrSkipped) {
+			target := doc.Targets[i]
+			results[i] = newTargetResult(doc, target)
+			results[i].Status = TargetSkipped
+			r.fireTarget(doc, target, results[i], tasks.TaskStateSkipped)
+			continue
+		}
+		if err != nil {
+			failed = append(failed, err)
+		}
+	}
+	r.mu.Lock()
+	r.results = results
+	r.mu.Unlock()
+	logTargetResults(results)
+	if err := state.Write(); err != nil {
+		log.Warn().Err(err).Msg("write solution state")
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	if r.Options.KeepGoing {
+		return errors.Join(failed...)
+	}
+	return failed[0]
+}
+
+// fireTarget reports the target state to the task hooks
+func (r *Runner) fireTarget(doc *spec.SolutionDoc, target *spec.SolutionTarget, result *TargetResult, state tasks.TaskState) {
+	meta := map[string]any{
+		"solution":  doc.RootDir,
+		"target":    result.Name,
+		"dependsOn": target.DependsOn,
+	}
+	if state != tasks.TaskStateRunning {
+		meta["status"] = string(result.Status)
+		meta["duration"] = result.Duration.String()
+		meta["filesWritten"] = result.FilesWritten
+		meta["filesSkipped"] = result.FilesSkipped
+		meta["filesCopied"] = result.FilesCopied
+	}
+	if result.Err != nil {
+		meta["error"] = result.Err.Error()
+	}
+	r.tm.FireHook(&tasks.TaskEvent{
+		Name:  result.Name,
+		State: state,
+		Meta:  meta,
+	})
+}
+
+// logTargetResults logs a summary line per target
+func logTargetResults(results []*TargetResult) {
+	for _, res := range results {
+		switch res.Status {
+		case TargetSkipped:
+			log.Warn().Msgf("target %s: skipped", res.Name)
+		case TargetFailed:
+			log.Error().Msgf("target %s: failed after %s: %s", res.Name, res.Duration, res.Err)
+		default:
+			log.Info().Msgf("target %s: %s in %s (%d write, %d skip, %d copy)", res.Name, res.Status, res.Duration, res.FilesWritten, res.FilesSkipped, res.FilesCopied)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package spec
+
+import "fmt"
+
+// The engines section of the rules declares what a template needs from the
+// cli: a version constraint, filter packs and model features. The engine
+// policy decides whether an unmet requirement is logged as warning or fails
+// the generation. It is configured in the cli config (engine_policy) and can
+// be overridden per solution (enginePolicy).
+
+// Engine policies
+const (
+	// EnginePolicyWarn logs unmet engine requirements as warnings
+	EnginePolicyWarn = "warn"
+	// EnginePolicyError fails for unmet engine requirements
+	EnginePolicyError = "error"
+)
+
+// EngineInfo describes the running engine, which is checked against the engines of the rules
+type EngineInfo struct {
+	// Version is the cli version, empty or (devel) for development builds
+	Version string
+	// Filters are the names of the available filter packs
+	Filters []string
+	// Features are the names of the supported model features
+	Features []string
+}
+
+// ValidateEnginePolicy checks the engine policy, empty uses the default policy
+func ValidateEnginePolicy(policy string) error {
+	switch policy {
+	case "", EnginePolicyWarn, EnginePolicyError:
+		return nil
+	}
+	return fmt.Errorf("invalid engine policy %s, expected warn or error", policy)
+}
@@ -0,0 +1,226 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/apigear-io/cli/pkg/helper"
+	"github.com/apigear-io/cli/pkg/repos"
+	"github.com/goccy/go-yaml"
+)
+
+// A lock file inside the solution root dir pins the template of each target
+// to the resolved repo id and commit. Locked targets install and check out
+// the locked commit instead of resolving the template reference again.
+// Targets using a local template dir are not locked.
+
+// LockFileName is the name of the lock file inside the solution root dir
+const LockFileName = "apigear.lock"
+
+// LockMode defines how the lock file is used when templates are resolved
+type LockMode string
+
+const (
+	// LockAuto uses locked templates and locks unlocked templates
+	LockAuto LockMode = ""
+	// LockUpdate resolves all templates again and replaces the locked ones
+	LockUpdate LockMode = "update"
+	// LockFrozen fails for templates which are not locked
+	LockFrozen LockMode = "frozen"
+)
+
+// LockedTemplate is the resolved template of a target
+type LockedTemplate struct {
+	// Template is the template reference of the target
+	Template string `json:"template" yaml:"template"`
+	// Repo is the resolved repo id (e.g. apigear-io/template-cpp14@v1.2.0)
+	Repo string `json:"repo" yaml:"repo"`
+	// Commit is the resolved commit
+	Commit string `json:"commit" yaml:"commit"`
+}
+
+// LockFile pins the templates of the solution targets
+type LockFile struct {
+	Targets map[string]*LockedTemplate `json:"targets" yaml:"targets"`
+	file    string
+	changed bool
+}
+
+// ReadLockFile reads the lock file of the solution root dir.
+// A missing lock file results in an empty lock.
+func ReadLockFile(rootDir string) (*LockFile, error) {
+	l := &LockFile{
+		file:    helper.Join(rootDir, LockFileName),
+		Targets: map[string]*LockedTemplate{},
+	}
+	data, err := os.ReadFile(l.file)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, l); err != nil {
+		return nil, fmt.Errorf("read %s: %w", l.file, err)
+	}
+	if l.Targets == nil {
+		l.Targets = map[string]*LockedTemplate{}
+	}
+	return l, nil
+}
+
+// File returns the path of the lock file
+func (l *LockFile) File() string {
+	return l.file
+}
+
+// Exists returns true if the lock file exists
+func (l *LockFile) Exists() bool {
+	return helper.IsFile(l.file)
+}
+
+// Get returns the locked template of the target,
+// if it was locked for the same template reference
+func (l *LockFile) Get(target string, template string) *LockedTemplate {
+	locked := l.Targets[target]
+	if locked == nil || locked.Template != template {
+		return nil
+	}
+	return locked
+}
+
+// Set locks the template of the target
+func (l *LockFile) Set(target string, locked *LockedTemplate) {
+	if existing := l.Targets[target]; existing != nil && *existing == *locked {
+		return
+	}
+	l.Targets[target] = locked
+	l.changed = true
+}
+
+// Prune removes the targets not contained in the given target names
+func (l *LockFile) Prune(targets []string) {
+	keep := map[string]bool{}
+	for _, t := range targets {
+		keep[t] = true
+	}
+	for name := range l.Targets {
+		if !keep[name] {
+			delete(l.Targets, name)
+			l.changed = true
+		}
+	}
+}
+
+// Changed returns true if the lock differs from the lock file
+func (l *LockFile) Changed() bool {
+	return l.changed
+}
+
+// Names returns the sorted names of the locked targets
+func (l *LockFile) Names() []string {
+	names := make([]string, 0, len(l.Targets))
+	for name := range l.Targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Write writes the lock file
+func (l *LockFile) Write() error {
+	data, err := yaml.Marshal(l)
+	if err != nil {
+		return err
+	}
+	header := "# generated by apigear, update with 'apigear template lock --update'\n"
+	err = helper.WriteFile(l.file, append([]byte(header), data...))
+	if err != nil {
+		return err
+	}
+	l.changed = false
+	return nil
+}
+
+// lockKey is the key of the target inside the lock file
+func (l *SolutionTarget) lockKey() string {
+	if l.Name != "" {
+		return l.Name
+	}
+	return l.Output
+}
+
+// LockKeys returns the lock keys of all targets
+func (s *SolutionDoc) LockKeys() []string {
+	keys := make([]string, 0, len(s.Targets))
+	for _, t := range s.Targets {
+		keys = append(keys, t.lockKey())
+	}
+	return keys
+}
+
+// LockFile returns the lock file of the solution, which is read on first use
+func (s *SolutionDoc) LockFile() (*LockFile, error) {
+	if s.lock != nil {
+		return s.lock, nil
+	}
+	lock, err := ReadLockFile(s.RootDir)
+	if err != nil {
+		return nil, err
+	}
+	s.lock = lock
+	return lock, nil
+}
+
+// LockedTemplate returns the locked template of the target,
+// or nil for local templates and templates which are not locked
+func (s *SolutionDoc) LockedTemplate(t *SolutionTarget) *LockedTemplate {
+	if s.lock == nil {
+		return nil
+	}
+	return s.lock.Get(t.lockKey(), t.Template)
+}
+
+// resolveTargetTemplate resolves the template of the target using the lock file.
+// It returns the template reference (the locked repo id for installed templates)
+// and the template dir.
+func (s *SolutionDoc) resolveTargetTemplate(t *SolutionTarget) (string, string, error) {
+	ref := t.Template
+	if dir := helper.Join(s.RootDir, ref); helper.IsDir(dir) {
+		return ref, dir, nil
+	}
+	lock, err := s.LockFile()
+	if err != nil {
+		return "", "", err
+	}
+	key := t.lockKey()
+	if s.LockMode != LockUpdate {
+		if locked := lock.Get(key, ref); locked != nil {
+			log.Debug().Msgf("target %s: use locked template %s (%s)", key, locked.Repo, locked.Commit)
+			dir, err := repos.GetOrInstallTemplateAtCommit(locked.Repo, locked.Commit)
+			if err != nil {
+				return "", "", err
+			}
+			return locked.Repo, dir, nil
+		}
+		if s.LockMode == LockFrozen {
+			return "", "", fmt.Errorf("target %s: template %s is not locked in %s", key, ref, lock.File())
+		}
+	}
+	repoID, dir, err := ResolveTemplateDir(s.RootDir, ref)
+	if err != nil {
+		return "", "", err
+	}
+	info, err := repos.Cache.Info(repoID)
+	if err != nil {
+		return "", "", err
+	}
+	lock.Set(key, &LockedTemplate{
+		Template: ref,
+		Repo:     repoID,
+		Commit:   info.Commit,
+	})
+	return repoID, dir, nil
+}
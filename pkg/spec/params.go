@@ -0,0 +1,232 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// A rules document declares the parameters a template understands.
+// A solution target supplies the values, which are validated and
+// converted to the declared type before the templates see them.
+
+// ParamType is the type of a template parameter.
+type ParamType string
+
+const (
+	ParamString ParamType = "string"
+	ParamBool   ParamType = "bool"
+	ParamInt    ParamType = "int"
+	ParamFloat  ParamType = "float"
+	ParamList   ParamType = "list"
+)
+
+// ParamRule declares a template parameter.
+type ParamRule struct {
+	// Name of the parameter, templates access it as .Params.<name>
+	Name string `json:"name" yaml:"name"`
+	// Type of the parameter, defaults to string
+	Type ParamType `json:"type" yaml:"type"`
+	// Default value used when the solution target does not set the parameter
+	Default any `json:"default" yaml:"default"`
+	// Enum lists the allowed values
+	Enum []any `json:"enum" yaml:"enum"`
+	// Description of the parameter
+	Description string `json:"description" yaml:"description"`
+}
+
+// Validate checks the type, the default value and the enum choices of the parameter.
+func (p *ParamRule) Validate() error {
+	if p.Type == "" {
+		p.Type = ParamString
+	}
+	switch p.Type {
+	case ParamString, ParamBool, ParamInt, ParamFloat, ParamList:
+	default:
+		return fmt.Errorf("param %s: unknown type %s", p.Name, p.Type)
+	}
+	for i, v := range p.Enum {
+		if p.Type == ParamList {
+			s, err := convertParam(ParamString, v)
+			if err != nil {
+				return fmt.Errorf("param %s: enum: %w", p.Name, err)
+			}
+			p.Enum[i] = s
+			continue
+		}
+		c, err := convertParam(p.Type, v)
+		if err != nil {
+			return fmt.Errorf("param %s: enum: %w", p.Name, err)
+		}
+		p.Enum[i] = c
+	}
+	if p.Default != nil {
+		if _, err := p.Convert(p.Default); err != nil {
+			return fmt.Errorf("param %s: default: %w", p.Name, err)
+		}
+	}
+	return nil
+}
+
+// Convert converts the value to the parameter type and checks the enum choices.
+func (p *ParamRule) Convert(v any) (any, error) {
+	c, err := convertParam(p.Type, v)
+	if err != nil {
+		return nil, err
+	}
+	if len(p.Enum) == 0 {
+		return c, nil
+	}
+	if list, ok := c.([]string); ok {
+		for _, item := range list {
+			if !p.isChoice(item) {
+				return nil, fmt.Errorf("value %s is not one of %v", item, p.Enum)
+			}
+		}
+		return c, nil
+	}
+	if !p.isChoice(c) {
+		return nil, fmt.Errorf("value %v is not one of %v", c, p.Enum)
+	}
+	return c, nil
+}
+
+// Zero returns the default value or the zero value of the parameter type.
+func (p *ParamRule) Zero() any {
+	if p.Default != nil {
+		v, err := p.Convert(p.Default)
+		if err == nil {
+			return v
+		}
+	}
+	switch p.Type {
+	case ParamBool:
+		return false
+	case ParamInt:
+		return 0
+	case ParamFloat:
+		return 0.0
+	case ParamList:
+		return []string{}
+	}
+	return ""
+}
+
+func (p *ParamRule) isChoice(v any) bool {
+	for _, e := range p.Enum {
+		if reflect.DeepEqual(e, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// convertParam converts a value decoded from yaml, json or the command line to the type.
+func convertParam(t ParamType, v any) (any, error) {
+	switch t {
+	case ParamString:
+		switch v := v.(type) {
+		case string:
+			return v, nil
+		case bool, int, int64, uint64, float64:
+			return fmt.Sprint(v), nil
+		}
+	case ParamBool:
+		switch v := v.(type) {
+		case bool:
+			return v, nil
+		case string:
+			return strconv.ParseBool(v)
+		}
+	case ParamInt:
+		switch v := v.(type) {
+		case int:
+			return v, nil
+		case int64:
+			return int(v), nil
+		case uint64:
+			return int(v), nil
+		case float64:
+			if v == math.Trunc(v) {
+				return int(v), nil
+			}
+		case string:
+			return strconv.Atoi(v)
+		}
+	case ParamFloat:
+		switch v := v.(type) {
+		case float64:
+			return v, nil
+		case int:
+			return float64(v), nil
+		case int64:
+			return float64(v), nil
+		case uint64:
+			return float64(v), nil
+		case string:
+			return strconv.ParseFloat(v, 64)
+		}
+	case ParamList:
+		switch v := v.(type) {
+		case []string:
+			return v, nil
+		case []any:
+			list := make([]string, 0, len(v))
+			for _, item := range v {
+				s, err := convertParam(ParamString, item)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, s.(string))
+			}
+			return list, nil
+		}
+	}
+	return nil, fmt.Errorf("value %v (%T) is not a %s", v, v, t)
+}
+
+// ParamByName returns the parameter with the given name.
+func (r *RulesDoc) ParamByName(name string) *ParamRule {
+	for _, p := range r.Params {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// ResolveParams validates the given values against the declared parameters
+// and returns the typed values of all parameters. Parameters without a value
+// use their default. Unknown parameters are reported as errors.
+func (r *RulesDoc) ResolveParams(values map[string]any) (map[string]any, error) {
+	params := make(map[string]any, len(r.Params))
+	for _, p := range r.Params {
+		params[p.Name] = p.Zero()
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var errs []error
+	for _, name := range names {
+		p := r.ParamByName(name)
+		if p == nil {
+			errs = append(errs, fmt.Errorf("unknown template param %s", name))
+			continue
+		}
+		v, err := p.Convert(values[name])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("param %s: %w", name, err))
+			continue
+		}
+		params[name] = v
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return params, nil
+}
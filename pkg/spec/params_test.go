@@ -0,0 +1,81 @@
+package spec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func paramsDoc() *RulesDoc {
+	return &RulesDoc{
+		Params: []*ParamRule{
+			{Name: "namespace", Default: "demo"},
+			{Name: "tests", Type: ParamBool, Default: true},
+			{Name: "port", Type: ParamInt, Default: uint64(8080)},
+			{Name: "ratio", Type: ParamFloat},
+			{Name: "std", Type: ParamString, Enum: []any{"c++14", "c++17"}, Default: "c++17"},
+			{Name: "platforms", Type: ParamList, Enum: []any{"linux", "windows", "macos"}},
+		},
+	}
+}
+
+func TestResolveParamsDefaults(t *testing.T) {
+	doc := paramsDoc()
+	require.NoError(t, doc.Validate())
+	params, err := doc.ResolveParams(nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"namespace": "demo",
+		"tests":     true,
+		"port":      8080,
+		"ratio":     0.0,
+		"std":       "c++17",
+		"platforms": []string{},
+	}, params)
+}
+
+func TestResolveParamsValues(t *testing.T) {
+	doc := paramsDoc()
+	require.NoError(t, doc.Validate())
+	params, err := doc.ResolveParams(map[string]any{
+		"namespace": "acme",
+		"tests":     "false",
+		"port":      uint64(9000),
+		"ratio":     "0.5",
+		"std":       "c++14",
+		"platforms": []any{"linux", "macos"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "acme", params["namespace"])
+	assert.Equal(t, false, params["tests"])
+	assert.Equal(t, 9000, params["port"])
+	assert.Equal(t, 0.5, params["ratio"])
+	assert.Equal(t, "c++14", params["std"])
+	assert.Equal(t, []string{"linux", "macos"}, params["platforms"])
+}
+
+func TestResolveParamsErrors(t *testing.T) {
+	doc := paramsDoc()
+	require.NoError(t, doc.Validate())
+	_, err := doc.ResolveParams(map[string]any{
+		"namepsace": "acme",
+		"port":      "http",
+		"std":       "c++20",
+		"platforms": []any{"linux", "android"},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown template param namepsace")
+	assert.Contains(t, err.Error(), "param port:")
+	assert.Contains(t, err.Error(), "param std: value c++20 is not one of [c++14 c++17]")
+	assert.Contains(t, err.Error(), "param platforms: value android is not one of")
+}
+
+func TestParamValidate(t *testing.T) {
+	p := &ParamRule{Name: "port", Type: ParamInt, Default: "http"}
+	assert.Error(t, p.Validate())
+	p = &ParamRule{Name: "port", Type: "number"}
+	assert.Error(t, p.Validate())
+	p = &ParamRule{Name: "std", Enum: []any{"a", "b"}, Default: "c"}
+	assert.Error(t, p.Validate())
+}
@@ -0,0 +1,101 @@
+package rkw
+
+import "fmt"
+
+// EscapeMode defines how a reserved word is escaped in a generated identifier
+type EscapeMode string
+
+const (
+	// EscapeNative uses the escaping of the language (e.g. r#type in Rust, @class in C#)
+	// and falls back to the suffix for languages without identifier escaping
+	EscapeNative EscapeMode = "native"
+	// EscapeSuffix appends the suffix (e.g. type_)
+	EscapeSuffix EscapeMode = "suffix"
+	// EscapeNone keeps reserved words unchanged
+	EscapeNone EscapeMode = "none"
+)
+
+// DefaultEscapeSuffix is appended to reserved words by the suffix mode
+const DefaultEscapeSuffix = "_"
+
+// ParseEscapeMode returns the escape mode, an empty mode is native
+func ParseEscapeMode(mode string) (EscapeMode, error) {
+	switch EscapeMode(mode) {
+	case "", EscapeNative:
+		return EscapeNative, nil
+	case EscapeSuffix, EscapeNone:
+		return EscapeMode(mode), nil
+	}
+	return "", fmt.Errorf("unknown escape mode %s", mode)
+}
+
+// Escaper escapes reserved words in generated identifiers
+type Escaper struct {
+	// Mode is the escape mode of all languages
+	Mode EscapeMode
+	// Suffix is appended to reserved words in suffix mode
+	Suffix string
+	// Langs overrides the mode per language
+	Langs map[Lang]EscapeMode
+}
+
+// DefaultEscaper escapes reserved words natively
+var DefaultEscaper = &Escaper{Mode: EscapeNative, Suffix: DefaultEscapeSuffix}
+
+// ModeFor returns the escape mode of the language
+func (e *Escaper) ModeFor(lang Lang) EscapeMode {
+	if mode, ok := e.Langs[lang]; ok {
+		return mode
+	}
+	if e.Mode == "" {
+		return EscapeNative
+	}
+	return e.Mode
+}
+
+// Escape returns the identifier escaped for the language if it is a reserved word.
+// Identifiers are compared case sensitive, as generated code is case sensitive.
+// A nil escaper is the default escaper.
+func (e *Escaper) Escape(lang Lang, ident string) string {
+	if e == nil {
+		e = DefaultEscaper
+	}
+	if !IsReservedIdentifier(lang, ident) {
+		return ident
+	}
+	suffix := e.Suffix
+	if suffix == "" {
+		suffix = DefaultEscapeSuffix
+	}
+	switch e.ModeFor(lang) {
+	case EscapeNone:
+		return ident
+	case EscapeSuffix:
+		return ident + suffix
+	}
+	switch lang {
+	case RS:
+		// these keywords can not be raw identifiers
+		switch ident {
+		case "self", "Self", "super", "crate":
+			return ident + suffix
+		}
+		return "r#" + ident
+	case CS:
+		return "@" + ident
+	case SWIFT, KT:
+		return "`" + ident + "`"
+	}
+	return ident + suffix
+}
+
+// IsReservedIdentifier returns true if the identifier is a reserved word in the language.
+// Unlike IsKeywordReservedInLang the comparison is case sensitive.
+func IsReservedIdentifier(lang Lang, ident string) bool {
+	for _, keyword := range reservedKeywordsPerLang[lang] {
+		if keyword == ident {
+			return true
+		}
+	}
+	return false
+}
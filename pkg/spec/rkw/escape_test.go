@@ -0,0 +1,52 @@
+package rkw
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscapeNative(t *testing.T) {
+	tests := []struct {
+		lang  Lang
+		ident string
+		want  string
+	}{
+		{RS, "type", "r#type"},
+		{RS, "self", "self_"},
+		{RS, "Self", "Self_"},
+		{RS, "count", "count"},
+		{CS, "class", "@class"},
+		{SWIFT, "in", "`in`"},
+		{KT, "object", "`object`"},
+		{PY, "from", "from_"},
+		{PY, "None", "None_"},
+		{PY, "none", "none"},
+		{GO, "type", "type_"},
+		{CPP, "delete", "delete_"},
+		{JAVA, "package", "package_"},
+		{TS, "delete", "delete_"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.lang)+"/"+tt.ident, func(t *testing.T) {
+			require.Equal(t, tt.want, DefaultEscaper.Escape(tt.lang, tt.ident))
+		})
+	}
+}
+
+func TestEscapeModes(t *testing.T) {
+	e := &Escaper{
+		Mode:   EscapeSuffix,
+		Suffix: "Arg",
+		Langs:  map[Lang]EscapeMode{PY: EscapeNone, CS: EscapeNative},
+	}
+	require.Equal(t, "typeArg", e.Escape(RS, "type"))
+	require.Equal(t, "from", e.Escape(PY, "from"))
+	require.Equal(t, "@class", e.Escape(CS, "class"))
+	require.Equal(t, "count", e.Escape(RS, "count"))
+	mode, err := ParseEscapeMode("")
+	require.NoError(t, err)
+	require.Equal(t, EscapeNative, mode)
+	_, err = ParseEscapeMode("prefix")
+	require.ErrorContains(t, err, "unknown escape mode prefix")
+}
@@ -1,6 +1,7 @@
 package rkw
 
 import (
+	"fmt"
 	"strings"
 )
 
@@ -8,13 +9,18 @@ import (
 type Lang string
 
 const (
-	CPP Lang = "cpp" // C++
-	PY  Lang = "py"  // Python
-	TS  Lang = "ts"  // TypeScript
-	JS  Lang = "js"  // JavaScript
-	GO  Lang = "go"  // Go
-	UE  Lang = "ue"  // Unreal Engine C++
-	QT  Lang = "qt"  // Qt C++
+	CPP   Lang = "cpp"   // C++
+	PY    Lang = "py"    // Python
+	TS    Lang = "ts"    // TypeScript
+	JS    Lang = "js"    // JavaScript
+	GO    Lang = "go"    // Go
+	UE    Lang = "ue"    // Unreal Engine C++
+	QT    Lang = "qt"    // Qt C++
+	CS    Lang = "cs"    // C#
+	SWIFT Lang = "swift" // Swift
+	RS    Lang = "rs"    // Rust
+	JAVA  Lang = "java"  // Java
+	KT    Lang = "kt"    // Kotlin
 )
 
 // DisplayName returns the display name of the language
@@ -34,6 +40,16 @@ func (l Lang) DisplayName() string {
 		return "Unreal Engine C++"
 	case QT:
 		return "Qt C++"
+	case CS:
+		return "C#"
+	case SWIFT:
+		return "Swift"
+	case RS:
+		return "Rust"
+	case JAVA:
+		return "Java"
+	case KT:
+		return "Kotlin"
 	default:
 		return string(l)
 	}
@@ -69,7 +85,7 @@ func cppReservedKeywords() []string {
 
 func pyReservedKeywords() []string {
 	return []string{
-		"false", "none", "true", "and",
+		"False", "None", "True", "and",
 		"as", "assert", "async", "await",
 		"break", "class", "continue", "def",
 		"del", "elif", "else", "except",
@@ -153,6 +169,99 @@ func qtReservedKeywords() []string {
 	}
 }
 
+func csReservedKeywords() []string {
+	return []string{
+		"abstract", "as", "base", "bool",
+		"break", "byte", "case", "catch",
+		"char", "checked", "class", "const",
+		"continue", "decimal", "default", "delegate",
+		"do", "double", "else", "enum",
+		"event", "explicit", "extern", "false",
+		"finally", "fixed", "float", "for",
+		"foreach", "goto", "if", "implicit",
+		"in", "int", "interface", "internal",
+		"is", "lock", "long", "namespace",
+		"new", "null", "object", "operator",
+		"out", "override", "params", "private",
+		"protected", "public", "readonly", "ref",
+		"return", "sbyte", "sealed", "short",
+		"sizeof", "stackalloc", "static", "string",
+		"struct", "switch", "this", "throw",
+		"true", "try", "typeof", "uint",
+		"ulong", "unchecked", "unsafe", "ushort",
+		"using", "virtual", "void", "volatile",
+		"while",
+	}
+}
+
+func swiftReservedKeywords() []string {
+	return []string{
+		"associatedtype", "class", "deinit", "enum",
+		"extension", "fileprivate", "func", "import",
+		"init", "inout", "internal", "let",
+		"open", "operator", "private", "precedencegroup",
+		"protocol", "public", "rethrows", "static",
+		"struct", "subscript", "typealias", "var",
+		"break", "case", "catch", "continue",
+		"default", "defer", "do", "else",
+		"fallthrough", "for", "guard", "if",
+		"in", "repeat", "return", "throw",
+		"switch", "where", "while", "any",
+		"as", "await", "false", "is",
+		"nil", "self", "super", "throws",
+		"true", "try",
+	}
+}
+
+func rsReservedKeywords() []string {
+	return []string{
+		"as", "async", "await", "break",
+		"const", "continue", "crate", "dyn",
+		"else", "enum", "extern", "false",
+		"fn", "for", "if", "impl",
+		"in", "let", "loop", "match",
+		"mod", "move", "mut", "pub",
+		"ref", "return", "self", "Self",
+		"static", "struct", "super", "trait",
+		"true", "type", "unsafe", "use",
+		"where", "while", "abstract", "become",
+		"box", "do", "final", "macro",
+		"override", "priv", "try", "typeof",
+		"unsized", "virtual", "yield",
+	}
+}
+
+func javaReservedKeywords() []string {
+	return []string{
+		"abstract", "assert", "boolean", "break",
+		"byte", "case", "catch", "char",
+		"class", "const", "continue", "default",
+		"do", "double", "else", "enum",
+		"extends", "final", "finally", "float",
+		"for", "goto", "if", "implements",
+		"import", "instanceof", "int", "interface",
+		"long", "native", "new", "package",
+		"private", "protected", "public", "return",
+		"short", "static", "strictfp", "super",
+		"switch", "synchronized", "this", "throw",
+		"throws", "transient", "try", "void",
+		"volatile", "while", "true", "false",
+		"null", "var", "record", "yield",
+	}
+}
+
+func ktReservedKeywords() []string {
+	return []string{
+		"as", "break", "class", "continue",
+		"do", "else", "false", "for",
+		"fun", "if", "in", "interface",
+		"is", "null", "object", "package",
+		"return", "super", "this", "throw",
+		"true", "try", "typealias", "typeof",
+		"val", "var", "when", "while",
+	}
+}
+
 var (
 	// map[lang][]keywords
 	reservedKeywordsPerLang = makeReservedKeywordsPerLang()
@@ -169,6 +278,11 @@ func makeReservedKeywordsPerLang() map[Lang][]string {
 	m[GO] = goReservedKeywords()
 	m[UE] = append(cppReservedKeywords(), unrealCPlusPlusKeywords()...)
 	m[QT] = append(cppReservedKeywords(), qtReservedKeywords()...)
+	m[CS] = csReservedKeywords()
+	m[SWIFT] = swiftReservedKeywords()
+	m[RS] = rsReservedKeywords()
+	m[JAVA] = javaReservedKeywords()
+	m[KT] = ktReservedKeywords()
 	return m
 }
 
@@ -177,6 +291,11 @@ func makeReservedKeywords() map[string][]Lang {
 	m := make(map[string][]Lang)
 	for lang, keywords := range reservedKeywordsPerLang {
 		for _, keyword := range keywords {
+			keyword = strings.ToLower(keyword)
+			if containsLang(m[keyword], lang) {
+				// e.g. rust self and Self
+				continue
+			}
 			m[keyword] = append(m[keyword], lang)
 		}
 	}
@@ -191,7 +310,16 @@ func IsKeywordReservedInLang(lang Lang, word string) bool {
 		return false
 	}
 	for _, keyword := range keywords {
-		if keyword == word {
+		if strings.ToLower(keyword) == word {
+			return true
+		}
+	}
+	return false
+}
+
+func containsLang(langs []Lang, lang Lang) bool {
+	for _, l := range langs {
+		if l == lang {
 			return true
 		}
 	}
@@ -225,12 +353,15 @@ func EscapeKeyword(word string) string {
 }
 
 // CheckIsReserved checks if the given name is a reserved keyword in any language
-// and logs a warning if it is
-func CheckIsReserved(langs []Lang, name string, scope string) {
+// and logs and returns a warning if it is
+func CheckIsReserved(langs []Lang, name string, scope string) []string {
 	lang, ok := IsKeywordInLangs(langs, name)
-	if ok {
-		log.Warn().Msgf("%s name \"%s\" is a reserved keyword in %s", scope, name, lang)
+	if !ok {
+		return nil
 	}
+	warning := fmt.Sprintf("%s name \"%s\" is a reserved keyword in %s", scope, name, lang)
+	log.Warn().Msg(warning)
+	return []string{warning}
 }
 
 // CheckAndEscapeName checks if the given name is a reserved keyword in any language
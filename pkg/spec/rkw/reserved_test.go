@@ -18,6 +18,16 @@ func TestIsKeywordReserved(t *testing.T) {
 		require.True(t, ok, "Expected %s to be reserved", keyword)
 		require.Contains(t, langs, CPP)
 	}
+	for _, keyword := range csReservedKeywords() {
+		langs, ok := IsKeywordReserved(keyword)
+		require.True(t, ok, "Expected %s to be reserved", keyword)
+		require.Contains(t, langs, CS)
+	}
+	for _, keyword := range swiftReservedKeywords() {
+		langs, ok := IsKeywordReserved(keyword)
+		require.True(t, ok, "Expected %s to be reserved", keyword)
+		require.Contains(t, langs, SWIFT)
+	}
 }
 
 func TestIsKeywordReservedInLang(t *testing.T) {
@@ -28,7 +38,9 @@ func TestIsKeywordReservedInLang(t *testing.T) {
 	}
 
 	tests := []test{
-		{kw: "else", ok: true, langs: []Lang{CPP, PY, TS, JS, GO, UE, QT}},
+		{kw: "else", ok: true, langs: []Lang{CPP, PY, TS, JS, GO, UE, QT, CS, SWIFT, RS, JAVA, KT}},
+		{kw: "guard", ok: true, langs: []Lang{SWIFT}},
+		{kw: "foreach", ok: true, langs: []Lang{CS}},
 	}
 
 	for _, tc := range tests {
@@ -3,8 +3,10 @@ package spec
 import (
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/Masterminds/semver/v3"
+	"github.com/apigear-io/cli/pkg/spec/rkw"
 )
 
 // A rules document defines a set of rules how to apply transformations
@@ -12,7 +14,8 @@ import (
 // For this the rules document is separated into a set of features, which can be enabled independently.
 // Each feature can depend on another feature, to form a dependency graph.
 // Transformation are applied based on the symbol type. A symbol can be a
-// system, module, interface, enum or struct.
+// system, module, interface, enum, struct, extern or an interface member
+// (operation, property or signal).
 // For this the feature has a set of scopes to match these symbol types.
 
 // ScopeType is the type of a scope.
@@ -24,6 +27,10 @@ const (
 	ScopeInterface ScopeType = "interface"
 	ScopeStruct    ScopeType = "struct"
 	ScopeEnum      ScopeType = "enum"
+	ScopeExtern    ScopeType = "extern"
+	ScopeOperation ScopeType = "operation"
+	ScopeProperty  ScopeType = "property"
+	ScopeSignal    ScopeType = "signal"
 )
 
 func containsString(list []string, value string) bool {
@@ -35,17 +42,93 @@ func containsString(list []string, value string) bool {
 	return false
 }
 
-// Engines defines version constraints for the engines.
+// Engines defines version constraints and requirements for the engines.
 type Engines struct {
 	// Cli is the version constraint for the cli engine.
 	Cli string `json:"cli" yaml:"cli"`
+	// Filters are the filter packs required by the templates (e.g. cpp, qt).
+	Filters []string `json:"filters" yaml:"filters"`
+	// Features are the model features required by the templates (e.g. externs).
+	Features []string `json:"features" yaml:"features"`
 }
 
 type RulesDoc struct {
 	Name      string         `json:"name" yaml:"name"`
 	Engines   Engines        `json:"engines" yaml:"engines"`
 	Languages []string       `json:"languages" yaml:"languages"`
+	Extends   string         `json:"extends" yaml:"extends"`
+	Includes  []*IncludeRule `json:"includes" yaml:"includes"`
+	Params    []*ParamRule   `json:"params" yaml:"params"`
 	Features  []*FeatureRule `json:"features" yaml:"features"`
+	Escape    *EscapeRule    `json:"escape,omitempty" yaml:"escape,omitempty"`
+}
+
+// EscapeRule defines how reserved words are escaped in generated identifiers.
+type EscapeRule struct {
+	// Mode is the escape mode for all languages (native, suffix or none).
+	Mode string `json:"mode" yaml:"mode"`
+	// Suffix is appended to reserved words in suffix mode, defaults to "_".
+	Suffix string `json:"suffix" yaml:"suffix"`
+	// Languages overrides the escape mode per language.
+	Languages map[string]string `json:"languages" yaml:"languages"`
+}
+
+// Escaper returns the escaper configured by the rule.
+// A nil rule returns the default escaper.
+func (e *EscapeRule) Escaper() (*rkw.Escaper, error) {
+	if e == nil {
+		return rkw.DefaultEscaper, nil
+	}
+	mode, err := rkw.ParseEscapeMode(e.Mode)
+	if err != nil {
+		return nil, fmt.Errorf("escape: %w", err)
+	}
+	escaper := &rkw.Escaper{
+		Mode:   mode,
+		Suffix: e.Suffix,
+		Langs:  map[rkw.Lang]rkw.EscapeMode{},
+	}
+	for lang, m := range e.Languages {
+		mode, err := rkw.ParseEscapeMode(m)
+		if err != nil {
+			return nil, fmt.Errorf("escape language %s: %w", lang, err)
+		}
+		escaper.Langs[rkw.Lang(lang)] = mode
+	}
+	return escaper, nil
+}
+
+// IncludeRule includes another template under a namespace.
+type IncludeRule struct {
+	// Template is an installed template or a template dir relative to the including template.
+	Template string `json:"template" yaml:"template"`
+	// As is the namespace prefixed to the included feature and template names.
+	As string `json:"as" yaml:"as"`
+}
+
+// Namespace prefixes all feature names, required features and
+// document sources with the given namespace (e.g. "common/").
+// Document targets are not changed.
+func (r *RulesDoc) Namespace(ns string) {
+	if ns == "" {
+		return
+	}
+	for _, f := range r.Features {
+		f.Name = ns + f.Name
+		for i, req := range f.Requires {
+			f.Requires[i] = ns + req
+		}
+		for _, s := range f.Scopes {
+			for i := range s.Documents {
+				doc := &s.Documents[i]
+				// the target defaults to the source, which is not namespaced
+				if doc.Target == "" {
+					doc.Target = doc.Source
+				}
+				doc.Source = ns + doc.Source
+			}
+		}
+	}
 }
 
 // FeatureByName returns the feature with the given name.
@@ -58,10 +141,78 @@ func (r *RulesDoc) FeatureByName(name string) *FeatureRule {
 	return nil
 }
 
+// Merge applies a rules fragment of an overlay on top of the rules.
+// Features and params of the overlay replace the ones with the same name,
+// others are appended. Languages and engine requirements are added and a cli
+// engine constraint and escape rule of the overlay replace the existing ones.
+func (r *RulesDoc) Merge(overlay *RulesDoc) {
+	for _, f := range overlay.Features {
+		replaced := false
+		for i, existing := range r.Features {
+			if existing.Name == f.Name {
+				r.Features[i] = f
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			r.Features = append(r.Features, f)
+		}
+	}
+	for _, p := range overlay.Params {
+		r.MergeParam(p)
+	}
+	for _, lang := range overlay.Languages {
+		if !containsString(r.Languages, lang) {
+			r.Languages = append(r.Languages, lang)
+		}
+	}
+	if overlay.Engines.Cli != "" {
+		r.Engines.Cli = overlay.Engines.Cli
+	}
+	for _, name := range overlay.Engines.Filters {
+		if !containsString(r.Engines.Filters, name) {
+			r.Engines.Filters = append(r.Engines.Filters, name)
+		}
+	}
+	for _, name := range overlay.Engines.Features {
+		if !containsString(r.Engines.Features, name) {
+			r.Engines.Features = append(r.Engines.Features, name)
+		}
+	}
+	if overlay.Escape != nil {
+		r.Escape = overlay.Escape
+	}
+}
+
+// MergeParam adds the parameter or replaces the parameter with the same name.
+func (r *RulesDoc) MergeParam(param *ParamRule) {
+	for i, p := range r.Params {
+		if p.Name == param.Name {
+			r.Params[i] = param
+			return
+		}
+	}
+	r.Params = append(r.Params, param)
+}
+
 func (d *RulesDoc) Validate() error {
 	if d.Features == nil {
 		d.Features = make([]*FeatureRule, 0)
 	}
+	names := map[string]bool{}
+	for _, p := range d.Params {
+		if names[p.Name] {
+			return fmt.Errorf("duplicate param %s", p.Name)
+		}
+		names[p.Name] = true
+		if err := p.Validate(); err != nil {
+			return err
+		}
+	}
+	if _, err := d.Escape.Escaper(); err != nil {
+		return err
+	}
 	for _, f := range d.Features {
 		if err := f.Validate(); err != nil {
 			return err
@@ -183,13 +334,18 @@ type FeatureRule struct {
 	Requires []string `json:"requires" yaml:"requires"`
 	// Scopes to match.
 	Scopes []*ScopeRule `json:"scopes" yaml:"scopes"`
-	Skip   bool         `json:"-" yaml:"-"`
+	// Format is the default formatter of the documents of the feature.
+	Format string `json:"format" yaml:"format"`
+	Skip   bool   `json:"-" yaml:"-"`
 }
 
 func (r *FeatureRule) Validate() error {
 	if r.Scopes == nil {
 		r.Scopes = make([]*ScopeRule, 0)
 	}
+	if err := ValidateFormat(r.Format); err != nil {
+		return fmt.Errorf("feature %s: %w", r.Name, err)
+	}
 	for _, s := range r.Scopes {
 		if err := s.Validate(); err != nil {
 			return err
@@ -215,6 +371,9 @@ type ScopeRule struct {
 	Match ScopeType `json:"match" yaml:"match"`
 	// Prefix is the prefix for all target documents
 	Prefix string `json:"prefix" yaml:"prefix"`
+	// When is an optional condition evaluated against the scope context.
+	// The scope is skipped for symbols where the condition is false.
+	When string `json:"when" yaml:"when"`
 	// Documents is a list of document rules to apply
 	Documents []DocumentRule `json:"documents" yaml:"documents"`
 }
@@ -243,9 +402,48 @@ type DocumentRule struct {
 	Force bool `json:"force" yaml:"force"`
 	// Preserve is true if the target file should be preserved.
 	Preserve bool `json:"preserve" yaml:"preserve"`
+	// When is an optional condition evaluated against the scope context.
+	// The document is skipped for symbols where the condition is false.
+	When string `json:"when" yaml:"when"`
+	// Format is the formatter applied to the rendered document,
+	// it overrides the format of the feature.
+	Format string `json:"format" yaml:"format"`
+}
+
+// Formatters applied to rendered documents.
+const (
+	// FormatNone disables the formatting of a document
+	FormatNone = "none"
+	// FormatGo formats go source code like gofmt
+	FormatGo = "gofmt"
+	// FormatJSON indents json documents
+	FormatJSON = "json"
+	// FormatYAML normalizes yaml documents
+	FormatYAML = "yaml"
+	// FormatExecPrefix prefixes an external formatter command (e.g. "exec:clang-format"),
+	// which reads the document from stdin and writes the formatted document to stdout.
+	FormatExecPrefix = "exec:"
+)
+
+// ValidateFormat checks the format is empty, a built-in formatter or an external command.
+func ValidateFormat(format string) error {
+	switch format {
+	case "", FormatNone, FormatGo, FormatJSON, FormatYAML:
+		return nil
+	}
+	if strings.HasPrefix(format, FormatExecPrefix) {
+		if strings.TrimSpace(strings.TrimPrefix(format, FormatExecPrefix)) == "" {
+			return fmt.Errorf("format %s: missing command", format)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown format %s", format)
 }
 
 func (r *DocumentRule) Validate() error {
+	if err := ValidateFormat(r.Format); err != nil {
+		return fmt.Errorf("document %s: %w", r.Source, err)
+	}
 	if r.Force {
 		if r.Preserve {
 			log.Warn().Msgf("force and preserve are mutually exclusive")
@@ -274,32 +472,56 @@ func FeatureRulesToStringMap(features []*FeatureRule) map[string]bool {
 	return result
 }
 
-// CheckCliVersion checks if the given engines are compatible with the rules.
+// CheckEngines checks the cli version constraint of the rules against the given version.
+// See CheckEngine for checking all engine requirements.
 func (r *RulesDoc) CheckEngines(version string) (bool, []error) {
+	cli := &RulesDoc{Engines: Engines{Cli: r.Engines.Cli}}
+	errs := cli.CheckEngine(EngineInfo{Version: version})
+	return len(errs) == 0, errs
+}
+
+// CheckEngine checks the engines of the rules against the running engine
+// and returns an error for each unmet requirement. Development builds have no
+// version, for them only the syntax of the cli version constraint is checked.
+func (r *RulesDoc) CheckEngine(engine EngineInfo) []error {
+	errs := []error{}
 	if r.Engines.Cli != "" {
-		check, errs := checkVersion(r.Engines.Cli, version)
-		if len(errs) > 0 {
-			return false, errs
+		ok, verrs := checkVersion(r.Engines.Cli, engine.Version)
+		if !ok {
+			msgs := make([]string, 0, len(verrs))
+			for _, err := range verrs {
+				msgs = append(msgs, err.Error())
+			}
+			err := fmt.Errorf("template requires cli version %s, found %s", r.Engines.Cli, engine.Version)
+			if len(msgs) > 0 {
+				err = fmt.Errorf("%w: %s", err, strings.Join(msgs, "; "))
+			}
+			errs = append(errs, err)
 		}
-		if !check {
-			return false, []error{}
+	}
+	for _, name := range r.Engines.Filters {
+		if !containsString(engine.Filters, name) {
+			errs = append(errs, fmt.Errorf("template requires filter pack %s (available: %s)", name, strings.Join(engine.Filters, ", ")))
+		}
+	}
+	for _, name := range r.Engines.Features {
+		if !containsString(engine.Features, name) {
+			errs = append(errs, fmt.Errorf("template requires model feature %s (supported: %s)", name, strings.Join(engine.Features, ", ")))
 		}
 	}
-	return true, []error{}
+	return errs
 }
 
 // checkVersion checks if the given version is compatible with the given constraint.
 func checkVersion(constraint, version string) (bool, []error) {
-	if version == "(devel)" {
-		version = ""
-	}
-	if version == "" {
-		return true, []error{}
-	}
 	c, err := semver.NewConstraint(constraint)
 	if err != nil {
 		return false, []error{err}
 	}
+	if version == "" || version == "(devel)" {
+		log.Warn().Msgf("skip cli version check %s: development build has no version", constraint)
+		return true, []error{}
+	}
 	v, err := semver.NewVersion(version)
 	if err != nil {
 		return false, []error{err}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/apigear-io/cli/pkg/spec/rkw"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -121,6 +122,7 @@ func TestCheckEngines(t *testing.T) {
 		{constraint: ">=1.0.0", version: "", check: true, errorsLen: 0},
 		{constraint: ">=1.0.0", version: "1.0.0-beta", check: false, errorsLen: 1},
 		{constraint: ">=1.0.0", version: "(devel)", check: true, errorsLen: 0},
+		{constraint: ">= x.y", version: "(devel)", check: false, errorsLen: 1},
 		{constraint: ">=1.0.0", version: "v0.9", check: false, errorsLen: 1},
 		{constraint: ">=1.0.0", version: "v1.0", check: true, errorsLen: 0},
 		{constraint: ">=1.0.0", version: "v1.1", check: true, errorsLen: 0},
@@ -139,3 +141,83 @@ func TestCheckEngines(t *testing.T) {
 		assert.Equal(t, test.check, check, label)
 	}
 }
+
+func TestCheckEngine(t *testing.T) {
+	engine := EngineInfo{
+		Version:  "1.2.0",
+		Filters:  []string{"common", "cpp"},
+		Features: []string{"externs", "imports"},
+	}
+	doc := RulesDoc{Engines: Engines{Cli: ">= 1.0.0", Filters: []string{"cpp"}, Features: []string{"externs"}}}
+	assert.Empty(t, doc.CheckEngine(engine))
+	doc.Engines = Engines{Cli: ">= 2.0.0", Filters: []string{"cpp", "qt"}, Features: []string{"streams"}}
+	errs := doc.CheckEngine(engine)
+	assert.Len(t, errs, 3)
+	assert.ErrorContains(t, errs[0], "template requires cli version >= 2.0.0, found 1.2.0")
+	assert.EqualError(t, errs[1], "template requires filter pack qt (available: common, cpp)")
+	assert.EqualError(t, errs[2], "template requires model feature streams (supported: externs, imports)")
+	// development builds have no version to check
+	engine.Version = "(devel)"
+	assert.Len(t, doc.CheckEngine(engine), 2)
+	assert.NoError(t, ValidateEnginePolicy(""))
+	assert.NoError(t, ValidateEnginePolicy(EnginePolicyError))
+	assert.EqualError(t, ValidateEnginePolicy("fail"), "invalid engine policy fail, expected warn or error")
+}
+
+func TestRulesMerge(t *testing.T) {
+	doc := RulesDoc{
+		Languages: []string{"cpp"},
+		Features: []*FeatureRule{
+			{Name: "api", Scopes: []*ScopeRule{{Match: ScopeModule}}},
+			{Name: "core", Requires: []string{"api"}},
+		},
+	}
+	doc.Engines.Filters = []string{"cpp"}
+	overlay := RulesDoc{
+		Engines:   Engines{Cli: ">= 0.40.0", Filters: []string{"cpp", "qt"}},
+		Languages: []string{"cpp", "qt"},
+		Features: []*FeatureRule{
+			{Name: "api", Scopes: []*ScopeRule{{Match: ScopeInterface}}},
+			{Name: "extra", Requires: []string{"core"}},
+		},
+	}
+	doc.Merge(&overlay)
+	assert.Len(t, doc.Features, 3)
+	assert.Equal(t, ScopeInterface, doc.Features[0].Scopes[0].Match)
+	assert.Equal(t, "core", doc.Features[1].Name)
+	assert.Equal(t, "extra", doc.Features[2].Name)
+	assert.Equal(t, []string{"cpp", "qt"}, doc.Languages)
+	assert.Equal(t, ">= 0.40.0", doc.Engines.Cli)
+	assert.Equal(t, []string{"cpp", "qt"}, doc.Engines.Filters)
+}
+
+func TestValidateFormat(t *testing.T) {
+	for _, format := range []string{"", "none", "gofmt", "json", "yaml", "exec:clang-format"} {
+		assert.NoError(t, ValidateFormat(format), format)
+	}
+	assert.ErrorContains(t, ValidateFormat("prettier"), "unknown format prettier")
+	assert.ErrorContains(t, ValidateFormat("exec: "), "missing command")
+	doc := RulesDoc{
+		Features: []*FeatureRule{
+			{Name: "api", Scopes: []*ScopeRule{{Documents: []DocumentRule{{Source: "api.h", Format: "clang"}}}}},
+		},
+	}
+	assert.ErrorContains(t, doc.Validate(), "document api.h: unknown format clang")
+}
+
+func TestValidateEscape(t *testing.T) {
+	doc := RulesDoc{Escape: &EscapeRule{Mode: "suffix", Suffix: "_", Languages: map[string]string{"cs": "native"}}}
+	assert.NoError(t, doc.Validate())
+	e, err := doc.Escape.Escaper()
+	assert.NoError(t, err)
+	assert.Equal(t, "type_", e.Escape(rkw.GO, "type"))
+	assert.Equal(t, "@class", e.Escape(rkw.CS, "class"))
+	doc.Escape.Languages["rs"] = "raw"
+	assert.ErrorContains(t, doc.Validate(), "escape language rs: unknown escape mode raw")
+	doc.Escape = &EscapeRule{Mode: "prefix"}
+	assert.ErrorContains(t, doc.Validate(), "escape: unknown escape mode prefix")
+	var rule *EscapeRule
+	e, err = rule.Escaper()
+	assert.NoError(t, err)
+	assert.Equal(t, rkw.DefaultEscaper, e)
+}
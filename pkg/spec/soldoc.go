@@ -1,5 +1,10 @@
 package spec
 
+import (
+	"fmt"
+	"strings"
+)
+
 type SolutionDoc struct {
 	Version     string            `json:"version" yaml:"version"`
 	Name        string            `json:"name" yaml:"name"`
@@ -8,8 +13,24 @@ type SolutionDoc struct {
 	Meta        map[string]any    `json:"meta" yaml:"meta"`
 	Layers      []*SolutionTarget `json:"layers" yaml:"layers"`
 	Targets     []*SolutionTarget `json:"targets" yaml:"targets"`
+	// Variables can be referenced as ${NAME} in the document
+	Variables map[string]string `json:"variables" yaml:"variables"`
+	// Profiles override variables and target fields, selected by name
+	Profiles map[string]*SolutionProfile `json:"profiles" yaml:"profiles"`
+	// Hooks are run after all targets are generated
+	Hooks *SolutionHooks `json:"hooks" yaml:"hooks"`
+	// EnginePolicy overrides the configured engine policy (warn or error)
+	EnginePolicy string `json:"enginePolicy" yaml:"enginePolicy"`
+	// LockMode defines how the lock file is used to resolve templates
+	LockMode LockMode `json:"-" yaml:"-"`
 	// computed fields
-	computed bool `json:"-" yaml:"-"`
+	computed bool      `json:"-" yaml:"-"`
+	resolved bool      `json:"-" yaml:"-"`
+	lock     *LockFile `json:"-" yaml:"-"`
+	// resolvedWith identifies the profile and variables the document is resolved with
+	resolvedWith string `json:"-" yaml:"-"`
+	// vars are the variables of the resolved document, used to expand hook commands
+	vars map[string]string `json:"-" yaml:"-"`
 }
 
 func (s *SolutionDoc) Validate() error {
@@ -29,9 +50,89 @@ func (s *SolutionDoc) Validate() error {
 			return err
 		}
 	}
+	if err := s.Hooks.Validate(); err != nil {
+		return fmt.Errorf("solution hooks: %w", err)
+	}
+	if err := ValidateEnginePolicy(s.EnginePolicy); err != nil {
+		return err
+	}
+	if _, err := s.DependencyGraph(); err != nil {
+		return err
+	}
 	return nil
 }
 
+// DependencyGraph returns for each target the indices of the targets it depends on.
+// Dependencies refer to target names, which must be unique, and must not form a cycle.
+func (s *SolutionDoc) DependencyGraph() ([][]int, error) {
+	byName := map[string]int{}
+	for i, t := range s.Targets {
+		if t.Name == "" {
+			continue
+		}
+		if _, ok := byName[t.Name]; ok {
+			// duplicate names are only a problem when a target depends on them
+			byName[t.Name] = -1
+			continue
+		}
+		byName[t.Name] = i
+	}
+	graph := make([][]int, len(s.Targets))
+	for i, t := range s.Targets {
+		graph[i] = make([]int, 0, len(t.DependsOn))
+		for _, name := range t.DependsOn {
+			dep, ok := byName[name]
+			if !ok {
+				return nil, fmt.Errorf("target %s: unknown dependency %s", t.Name, name)
+			}
+			if dep < 0 {
+				return nil, fmt.Errorf("target %s: dependency %s is ambiguous, target names are not unique", t.Name, name)
+			}
+			graph[i] = append(graph[i], dep)
+		}
+	}
+	// detect cycles using a depth first search
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(s.Targets))
+	stack := []string{}
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			start := 0
+			for j, name := range stack {
+				if name == s.Targets[i].Name {
+					start = j
+				}
+			}
+			cycle := append(stack[start:], s.Targets[i].Name)
+			return fmt.Errorf("target dependency cycle: %s", strings.Join(cycle, " -> "))
+		}
+		state[i] = visiting
+		stack = append(stack, s.Targets[i].Name)
+		for _, dep := range graph[i] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[i] = visited
+		return nil
+	}
+	for i := range s.Targets {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return graph, nil
+}
+
 // compute computes derived fields.
 func (s *SolutionDoc) compute() error {
 	if s.computed {
@@ -46,6 +147,20 @@ func (s *SolutionDoc) compute() error {
 	return nil
 }
 
+// RefreshDependencies expands the inputs of the computed targets again,
+// so input files added or removed since the document was validated are used.
+func (s *SolutionDoc) RefreshDependencies() error {
+	for _, t := range s.Targets {
+		if !t.computed {
+			continue
+		}
+		if err := t.computeDependencies(s); err != nil {
+			return fmt.Errorf("target %s: %w", t.Name, err)
+		}
+	}
+	return nil
+}
+
 // AggregateDependencies computes the dependencies of each layer.
 func (s *SolutionDoc) AggregateDependencies() []string {
 	deps := make([]string, 0)
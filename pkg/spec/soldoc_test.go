@@ -2,6 +2,7 @@ package spec
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -62,3 +63,168 @@ func TestUseLayers(t *testing.T) {
 	require.Equal(t, "layer1", doc.Targets[0].Name)
 	require.Equal(t, "layer2", doc.Targets[1].Name)
 }
+
+func TestDependencyGraph(t *testing.T) {
+	doc := SolutionDoc{
+		Targets: []*SolutionTarget{
+			{Name: "api"},
+			{Name: "impl", DependsOn: []string{"api"}},
+			{Name: "docs", DependsOn: []string{"api", "impl"}},
+		},
+	}
+	graph, err := doc.DependencyGraph()
+	require.NoError(t, err)
+	require.Equal(t, [][]int{{}, {0}, {0, 1}}, graph)
+	doc.Targets[0].DependsOn = []string{"docs"}
+	_, err = doc.DependencyGraph()
+	require.ErrorContains(t, err, "target dependency cycle: api -> docs -> api")
+	doc.Targets[0].DependsOn = []string{"tests"}
+	_, err = doc.DependencyGraph()
+	require.ErrorContains(t, err, "target api: unknown dependency tests")
+	doc.Targets[0].DependsOn = nil
+	doc.Targets[2].Name = "impl"
+	_, err = doc.DependencyGraph()
+	require.ErrorContains(t, err, "dependency impl is ambiguous")
+}
+
+func TestResolveVariables(t *testing.T) {
+	newDoc := func() *SolutionDoc {
+		return &SolutionDoc{
+			Variables: map[string]string{"build": "debug", "out": "out/${build}"},
+			Meta:      map[string]any{"home": "${HOME}", "list": []any{"${build}", 1}},
+			Profiles: map[string]*SolutionProfile{
+				"release": {
+					Variables: map[string]string{"build": "release"},
+					Targets: map[string]*SolutionTarget{
+						"*":   {Force: true},
+						"cpp": {Features: []string{"api"}, Meta: map[string]any{"opt": "${build}"}},
+					},
+				},
+			},
+			Targets: []*SolutionTarget{
+				{Name: "cpp", Output: "${out}/cpp", Template: "${TPL:-tpl}", Meta: map[string]any{"keep": true}},
+				{Name: "py", Output: "${out}/py", Template: "$${literal}"},
+			},
+		}
+	}
+	env := func(name string) (string, bool) {
+		if name == "HOME" {
+			return "/home/demo", true
+		}
+		return "", false
+	}
+	doc := newDoc()
+	require.NoError(t, doc.Resolve(ResolveOptions{LookupEnv: env}))
+	require.Equal(t, "out/debug/cpp", doc.Targets[0].Output)
+	require.Equal(t, "tpl", doc.Targets[0].Template)
+	require.Equal(t, "${literal}", doc.Targets[1].Template)
+	require.Equal(t, "/home/demo", doc.Meta["home"])
+	require.Equal(t, []any{"debug", 1}, doc.Meta["list"])
+	require.False(t, doc.Targets[0].Force)
+
+	doc = newDoc()
+	require.NoError(t, doc.Resolve(ResolveOptions{Profile: "release", LookupEnv: env}))
+	require.Equal(t, "out/release/cpp", doc.Targets[0].Output)
+	require.Equal(t, []string{"api"}, doc.Targets[0].Features)
+	require.Equal(t, map[string]any{"keep": true, "opt": "release"}, doc.Targets[0].Meta)
+	require.True(t, doc.Targets[0].Force)
+	require.True(t, doc.Targets[1].Force)
+	// resolving again only succeeds with the same profile and variables
+	require.NoError(t, doc.Resolve(ResolveOptions{Profile: "release", LookupEnv: env}))
+	require.ErrorContains(t, doc.Resolve(ResolveOptions{}), "already resolved")
+
+	// command line variables win over profile variables
+	doc = newDoc()
+	require.NoError(t, doc.Resolve(ResolveOptions{Profile: "release", Variables: map[string]string{"build": "ci"}, LookupEnv: env}))
+	require.Equal(t, "out/ci/py", doc.Targets[1].Output)
+
+	doc = newDoc()
+	require.ErrorContains(t, doc.Resolve(ResolveOptions{Profile: "nightly"}), "unknown profile nightly (available: release)")
+	doc = newDoc()
+	doc.Targets[1].Output = "${missing}"
+	require.ErrorContains(t, doc.Resolve(ResolveOptions{LookupEnv: env}), "target py: unknown variable missing")
+	// unknown references in meta and params are kept for the templates
+	doc = newDoc()
+	doc.Meta["tpl"] = "${missing} ${build}"
+	doc.Targets[1].Params = map[string]any{"header": "${FILE}", "out": "${out}"}
+	require.NoError(t, doc.Resolve(ResolveOptions{LookupEnv: env}))
+	require.Equal(t, "${missing} debug", doc.Meta["tpl"])
+	require.Equal(t, map[string]any{"header": "${FILE}", "out": "out/debug"}, doc.Targets[1].Params)
+	doc = newDoc()
+	doc.Variables["build"] = "${out}"
+	require.ErrorContains(t, doc.Resolve(ResolveOptions{LookupEnv: env}), "refers to itself")
+}
+
+func TestParseVariables(t *testing.T) {
+	vars, err := ParseVariables([]string{"a=1", "b=x=y", "c="})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"a": "1", "b": "x=y", "c": ""}, vars)
+	_, err = ParseVariables([]string{"novalue"})
+	require.ErrorContains(t, err, "invalid variable novalue")
+}
+
+func TestLockFile(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	lock, err := ReadLockFile(dir)
+	require.NoError(t, err)
+	require.False(t, lock.Exists())
+	locked := &LockedTemplate{Template: "apigear-io/template-cpp14@v1", Repo: "apigear-io/template-cpp14@v1.2.0", Commit: "abc"}
+	lock.Set("cpp", locked)
+	lock.Set("py", &LockedTemplate{Template: "py", Repo: "py@v1.0.0", Commit: "def"})
+	require.True(t, lock.Changed())
+	require.NoError(t, lock.Write())
+	require.False(t, lock.Changed())
+
+	lock, err = ReadLockFile(dir)
+	require.NoError(t, err)
+	require.True(t, lock.Exists())
+	require.Equal(t, []string{"cpp", "py"}, lock.Names())
+	require.Equal(t, locked, lock.Get("cpp", "apigear-io/template-cpp14@v1"))
+	// a changed template reference is not locked
+	require.Nil(t, lock.Get("cpp", "apigear-io/template-cpp14@v2"))
+	lock.Set("cpp", &LockedTemplate{Template: locked.Template, Repo: locked.Repo, Commit: locked.Commit})
+	require.False(t, lock.Changed())
+	lock.Prune([]string{"cpp"})
+	require.True(t, lock.Changed())
+	require.Equal(t, []string{"cpp"}, lock.Names())
+}
+
+func TestValidateHooks(t *testing.T) {
+	t.Parallel()
+	hooks := &TargetHooks{
+		Before: []*SolutionHook{{Run: "echo before"}},
+		After:  []*SolutionHook{{Name: "build", Run: "make", Timeout: "10m", OnFailure: HookWarn}},
+	}
+	require.NoError(t, hooks.Validate())
+	require.Equal(t, 10*time.Minute, hooks.After[0].TimeoutDuration())
+	require.Equal(t, DefaultHookTimeout, hooks.Before[0].TimeoutDuration())
+	require.Equal(t, HookFail, hooks.Before[0].Policy())
+	require.Equal(t, "echo before", hooks.Before[0].DisplayName())
+	hooks.After[0].Timeout = "soon"
+	require.ErrorContains(t, hooks.Validate(), "hook build: invalid timeout soon")
+	hooks.After[0].Timeout = ""
+	hooks.After[0].OnFailure = "retry"
+	require.ErrorContains(t, hooks.Validate(), "hook build: invalid onFailure retry")
+	solHooks := &SolutionHooks{After: []*SolutionHook{{}}}
+	require.ErrorContains(t, solHooks.Validate(), "hook: run is required")
+}
+
+func TestExpandHookCommand(t *testing.T) {
+	t.Parallel()
+	doc := &SolutionDoc{Variables: map[string]string{"dist": "out/${APIGEAR_TARGET}"}}
+	require.NoError(t, doc.Resolve(ResolveOptions{LookupEnv: func(string) (string, bool) { return "", false }}))
+	cmd, err := doc.ExpandHookCommand("cp -r ${APIGEAR_OUTPUT_DIR} ${dist} $HOME ${UNSET_VAR}", map[string]string{
+		HookVarTarget:    "cpp",
+		HookVarOutputDir: "/sdk/cpp",
+	})
+	require.NoError(t, err)
+	// hook and unset variables are expanded by the shell from the environment
+	require.Equal(t, "cp -r ${APIGEAR_OUTPUT_DIR} out/${APIGEAR_TARGET} $HOME ${UNSET_VAR}", cmd)
+	env, err := doc.ExpandHookEnv("${dist}:${APIGEAR_OUTPUT_DIR}", map[string]string{
+		HookVarTarget:    "cpp",
+		HookVarOutputDir: "/sdk/cpp",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "out/cpp:/sdk/cpp", env)
+}
@@ -0,0 +1,196 @@
+package spec
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Hooks are shell commands run before and after a target is generated and
+// after all targets of the solution are generated:
+//
+//	hooks:
+//	  after:
+//	    - { run: "cmake --build build", timeout: 10m }
+//	targets:
+//	  - name: cpp
+//	    hooks:
+//	      before:
+//	        - run: 'rm -rf "$APIGEAR_OUTPUT_DIR/generated"'
+//	      after:
+//	        - { name: format, run: "clang-format -i $APIGEAR_FILES", onFailure: warn }
+//
+// Hook commands can reference the solution variables, which are replaced
+// before the command runs. The hook variables (e.g. APIGEAR_TARGET) are only
+// passed as environment variables and are never replaced inside the command,
+// so the shell expands them and their values are not parsed as shell code.
+// Quote them as in "$APIGEAR_OUTPUT_DIR" (%APIGEAR_OUTPUT_DIR% on Windows).
+// Target hooks are not run for targets which are up to date.
+
+// DefaultHookTimeout is the time a hook command may take, unless configured
+const DefaultHookTimeout = 5 * time.Minute
+
+// Failure policies of a hook
+const (
+	// HookFail fails the target or solution when the hook fails
+	HookFail = "fail"
+	// HookWarn logs a warning when the hook fails
+	HookWarn = "warn"
+	// HookIgnore ignores a failing hook
+	HookIgnore = "ignore"
+)
+
+// Hook variables, passed as environment variables to the hook commands
+const (
+	// HookVarRootDir is the solution root dir
+	HookVarRootDir = "APIGEAR_ROOT_DIR"
+	// HookVarTarget is the target name
+	HookVarTarget = "APIGEAR_TARGET"
+	// HookVarOutputDir is the absolute target output dir
+	HookVarOutputDir = "APIGEAR_OUTPUT_DIR"
+	// HookVarTemplateDir is the target template dir
+	HookVarTemplateDir = "APIGEAR_TEMPLATE_DIR"
+	// HookVarTargets are the space separated names of the solution targets
+	HookVarTargets = "APIGEAR_TARGETS"
+	// HookVarFiles are the space separated files touched by the generator,
+	// relative to the output dir for target hooks and to the root dir for solution hooks
+	HookVarFiles = "APIGEAR_FILES"
+	// HookVarFilesList is a file listing the touched files, one per line
+	HookVarFilesList = "APIGEAR_FILES_LIST"
+)
+
+// SolutionHook is a shell command run by the solution
+type SolutionHook struct {
+	// Name of the hook in logs and reports, defaults to the command
+	Name string `json:"name" yaml:"name"`
+	// Run is the command, run by the shell
+	Run string `json:"run" yaml:"run"`
+	// Dir is the working dir relative to the solution root dir.
+	// Defaults to the output dir for target hooks and the root dir for solution hooks.
+	Dir string `json:"dir" yaml:"dir"`
+	// Timeout is the maximal duration of the command (e.g. 30s)
+	Timeout string `json:"timeout" yaml:"timeout"`
+	// OnFailure is the failure policy: fail (default), warn or ignore
+	OnFailure string `json:"onFailure" yaml:"onFailure"`
+	// Env are additional environment variables
+	Env map[string]string `json:"env" yaml:"env"`
+}
+
+// TargetHooks are run before and after the target is generated
+type TargetHooks struct {
+	Before []*SolutionHook `json:"before" yaml:"before"`
+	After  []*SolutionHook `json:"after" yaml:"after"`
+}
+
+// SolutionHooks are run after all targets of the solution are generated
+type SolutionHooks struct {
+	After []*SolutionHook `json:"after" yaml:"after"`
+}
+
+// DisplayName returns the hook name, which defaults to the command
+func (h *SolutionHook) DisplayName() string {
+	if h.Name != "" {
+		return h.Name
+	}
+	return h.Run
+}
+
+// TimeoutDuration returns the hook timeout
+func (h *SolutionHook) TimeoutDuration() time.Duration {
+	d, err := time.ParseDuration(h.Timeout)
+	if err != nil || d <= 0 {
+		return DefaultHookTimeout
+	}
+	return d
+}
+
+// Policy returns the failure policy
+func (h *SolutionHook) Policy() string {
+	if h.OnFailure == "" {
+		return HookFail
+	}
+	return h.OnFailure
+}
+
+// Validate checks the command, timeout and failure policy
+func (h *SolutionHook) Validate() error {
+	if strings.TrimSpace(h.Run) == "" {
+		if h.Name == "" {
+			return fmt.Errorf("hook: run is required")
+		}
+		return fmt.Errorf("hook %s: run is required", h.Name)
+	}
+	if h.Timeout != "" {
+		d, err := time.ParseDuration(h.Timeout)
+		if err != nil {
+			return fmt.Errorf("hook %s: invalid timeout %s", h.DisplayName(), h.Timeout)
+		}
+		if d <= 0 {
+			return fmt.Errorf("hook %s: timeout must be positive", h.DisplayName())
+		}
+	}
+	switch h.Policy() {
+	case HookFail, HookWarn, HookIgnore:
+	default:
+		return fmt.Errorf("hook %s: invalid onFailure %s, expected fail, warn or ignore", h.DisplayName(), h.OnFailure)
+	}
+	return nil
+}
+
+func validateHooks(hooks ...[]*SolutionHook) error {
+	for _, list := range hooks {
+		for _, h := range list {
+			if h == nil {
+				continue
+			}
+			if err := h.Validate(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Validate checks all target hooks
+func (h *TargetHooks) Validate() error {
+	if h == nil {
+		return nil
+	}
+	return validateHooks(h.Before, h.After)
+}
+
+// Validate checks all solution hooks
+func (h *SolutionHooks) Validate() error {
+	if h == nil {
+		return nil
+	}
+	return validateHooks(h.After)
+}
+
+// ExpandHookCommand replaces the solution variable references of a hook command,
+// unknown variables fall back to the environment. References to the hook
+// variables and to unset variables are kept, the shell expands them.
+func (s *SolutionDoc) ExpandHookCommand(command string, hookVars map[string]string) (string, error) {
+	keep := make(map[string]bool, len(hookVars))
+	for k := range hookVars {
+		keep[k] = true
+	}
+	r := &resolver{vars: s.vars, env: os.LookupEnv, resolving: map[string]bool{}, keep: keep, keepUnknown: true}
+	return r.expand(command)
+}
+
+// ExpandHookEnv replaces the variable references of a hook environment value.
+// Hook variables take precedence over the solution variables,
+// unknown variables fall back to the environment.
+func (s *SolutionDoc) ExpandHookEnv(value string, hookVars map[string]string) (string, error) {
+	vars := make(map[string]string, len(s.vars)+len(hookVars))
+	for k, v := range s.vars {
+		vars[k] = v
+	}
+	for k, v := range hookVars {
+		vars[k] = v
+	}
+	r := &resolver{vars: vars, env: os.LookupEnv, resolving: map[string]bool{}}
+	return r.expand(value)
+}
@@ -12,11 +12,18 @@ type SolutionTarget struct {
 	Description string                 `json:"description" yaml:"description"`
 	Inputs      []string               `json:"inputs" yaml:"inputs"`
 	Output      string                 `json:"output" yaml:"output"`
+	Archive     string                 `json:"archive" yaml:"archive"`
 	Template    string                 `json:"template" yaml:"template"`
+	Overlays    []string               `json:"overlays" yaml:"overlays"`
 	Features    []string               `json:"features" yaml:"features"`
 	Force       bool                   `json:"force" yaml:"force"`
 	Imports     []string               `json:"imports" yaml:"imports"`
 	Meta        map[string]interface{} `json:"meta" yaml:"meta"`
+	Params      map[string]any         `json:"params" yaml:"params"`
+	// DependsOn are the names of the targets which are generated before this target
+	DependsOn []string `json:"dependsOn" yaml:"dependsOn"`
+	// Hooks are run before and after the target is generated
+	Hooks       *TargetHooks           `json:"hooks" yaml:"hooks"`
 	MetaImports map[string]interface{} `json:"-" yaml:"-"` // meta imports
 	// computed fields
 	computed bool `json:"-" yaml:"-"`
@@ -30,6 +37,8 @@ type SolutionTarget struct {
 	TemplatesDir string `json:"-" yaml:"-"`
 	// RulesFile is the "rules.yaml" file inside the template dir
 	RulesFile string `json:"-" yaml:"-"`
+	// OverlayDirs are the overlay dirs relative to the solution root dir
+	OverlayDirs []string `json:"-" yaml:"-"`
 }
 
 // GetOutputDir returns the output dir.
@@ -70,6 +79,14 @@ func (l *SolutionTarget) Validate(doc *SolutionDoc) error {
 	if !helper.IsFile(l.RulesFile) {
 		return fmt.Errorf("target %s: rules file not found: %s", l.Name, l.RulesFile)
 	}
+	for _, dir := range l.OverlayDirs {
+		if !helper.IsDir(dir) {
+			return fmt.Errorf("target %s: overlay dir not found: %s", l.Name, dir)
+		}
+	}
+	if err := l.Hooks.Validate(); err != nil {
+		return fmt.Errorf("target %s: %w", l.Name, err)
+	}
 	// check inputs
 	for _, input := range l.expandedInputs {
 		result, err := CheckFile(input)
@@ -91,60 +108,70 @@ func (l *SolutionTarget) compute(doc *SolutionDoc) error {
 	if l.computed {
 		return nil
 	}
-	// compute template dir
-	tplDir := helper.Join(doc.RootDir, l.Template)
-	if helper.IsDir(tplDir) {
-		l.TemplateDir = tplDir
-		l.TemplatesDir = helper.Join(tplDir, "templates")
-		l.RulesFile = helper.Join(tplDir, "rules.yaml")
-	} else {
-		// try to find the template dir in the templates dir
-		repoId, err := repos.GetOrInstallTemplateFromRepoID(l.Template)
-		if err != nil {
-			log.Err(err).Msgf("failed to get template %s", l.Template)
-			return err
-		}
-		tplDir, err := repos.Cache.GetTemplateDir(repoId)
-		if err != nil {
-			log.Err(err).Msgf("failed to get template dir %s", l.Template)
-			return err
-		}
-		l.Template = repoId
-		l.TemplateDir = tplDir
-		l.TemplatesDir = helper.Join(tplDir, "templates")
-		l.RulesFile = helper.Join(tplDir, "rules.yaml")
+	// compute template dir, installed templates are pinned by the lock file
+	template, tplDir, err := doc.resolveTargetTemplate(l)
+	if err != nil {
+		return err
 	}
-
-	// record dependencies
-	if l.dependencies == nil {
-		l.dependencies = make([]string, 0)
+	l.Template = template
+	l.TemplateDir = tplDir
+	l.TemplatesDir = helper.Join(tplDir, "templates")
+	l.RulesFile = helper.Join(tplDir, "rules.yaml")
+	// overlays shadow the templates and rules of the template
+	l.OverlayDirs = make([]string, 0, len(l.Overlays))
+	for _, overlay := range l.Overlays {
+		l.OverlayDirs = append(l.OverlayDirs, helper.Join(doc.RootDir, overlay))
 	}
-	if len(l.dependencies) == 0 {
-		if l.TemplatesDir != "" {
-			l.dependencies = append(l.dependencies, l.TemplatesDir)
-		}
-		if l.RulesFile != "" {
-			l.dependencies = append(l.dependencies, l.RulesFile)
-		}
-		l.dependencies = append(l.dependencies, l.expandedInputs...)
+	if err := l.computeDependencies(doc); err != nil {
+		return err
 	}
-	// expand inputs
-	if l.expandedInputs == nil {
-		l.expandedInputs = make([]string, 0)
+	err = l.computeImports()
+	if err != nil {
+		return err
 	}
-	if len(l.expandedInputs) == 0 {
-		expanded, err := helper.ExpandInputs(doc.RootDir, l.Inputs...)
-		if err != nil {
-			return err
-		}
-		l.expandedInputs = append(l.expandedInputs, expanded...)
+	l.computed = true
+	return nil
+}
+
+// ResolveTemplateDir resolves a template reference, which is either a template dir
+// relative to the root dir or a template repo id, which is installed if needed.
+// It returns the template reference (the fixed repo id for installed templates)
+// and the template dir.
+func ResolveTemplateDir(rootDir string, ref string) (string, string, error) {
+	tplDir := helper.Join(rootDir, ref)
+	if helper.IsDir(tplDir) {
+		return ref, tplDir, nil
 	}
-	l.dependencies = append(l.dependencies, l.expandedInputs...)
-	err := l.computeImports()
+	// try to find the template dir in the templates dir
+	repoId, err := repos.GetOrInstallTemplateFromRepoID(ref)
+	if err != nil {
+		log.Err(err).Msgf("failed to get template %s", ref)
+		return "", "", err
+	}
+	tplDir, err = repos.Cache.GetTemplateDir(repoId)
+	if err != nil {
+		log.Err(err).Msgf("failed to get template dir %s", ref)
+		return "", "", err
+	}
+	return repoId, tplDir, nil
+}
+
+// computeDependencies expands the inputs and records the dependencies of the target
+func (l *SolutionTarget) computeDependencies(doc *SolutionDoc) error {
+	expanded, err := helper.ExpandInputs(doc.RootDir, l.Inputs...)
 	if err != nil {
 		return err
 	}
-	l.computed = true
+	l.expandedInputs = expanded
+	l.dependencies = make([]string, 0, len(expanded)+2+len(l.OverlayDirs))
+	if l.TemplatesDir != "" {
+		l.dependencies = append(l.dependencies, l.TemplatesDir)
+	}
+	if l.RulesFile != "" {
+		l.dependencies = append(l.dependencies, l.RulesFile)
+	}
+	l.dependencies = append(l.dependencies, l.OverlayDirs...)
+	l.dependencies = append(l.dependencies, l.expandedInputs...)
 	return nil
 }
 
@@ -0,0 +1,396 @@
+package spec
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Solution documents can use ${NAME} references in the string fields of the
+// solution and its targets, including meta and params values.
+// A reference is resolved in this order: variables set on the command line,
+// variables of the selected profile, variables of the document and at last
+// environment variables. ${NAME:-default} uses the default for unknown names
+// and $${ is a literal ${. Unknown names fail the resolution, except in
+// meta and params values, which keep them unchanged (e.g. placeholders for
+// templates). Hook commands are expanded when the hook runs.
+//
+// A profile overrides variables and target fields. Profile targets are
+// matched by target name, "*" applies to all targets:
+//
+//	variables:
+//	  build: debug
+//	profiles:
+//	  release:
+//	    variables: { build: release }
+//	    targets:
+//	      "*": { force: true }
+//	      cpp: { output: "out/${build}/cpp", features: [api, core] }
+
+// SolutionProfile is a named set of variable and target overrides
+type SolutionProfile struct {
+	Variables map[string]string          `json:"variables" yaml:"variables"`
+	Targets   map[string]*SolutionTarget `json:"targets" yaml:"targets"`
+}
+
+// ResolveOptions are applied when a solution document is resolved
+type ResolveOptions struct {
+	// Profile is the name of the profile to apply
+	Profile string
+	// Variables override the document and profile variables
+	Variables map[string]string
+	// LookupEnv looks up environment variables, defaults to os.LookupEnv
+	LookupEnv func(string) (string, bool)
+	// Lock defines how the lock file pins the target templates
+	Lock LockMode
+}
+
+// ParseVariables parses a list of key=value assignments
+func ParseVariables(assignments []string) (map[string]string, error) {
+	vars := map[string]string{}
+	for _, a := range assignments {
+		key, value, ok := strings.Cut(a, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid variable %s: expected key=value", a)
+		}
+		vars[key] = value
+	}
+	return vars, nil
+}
+
+// key identifies the profile and variables of the options
+func (o ResolveOptions) key() string {
+	names := make([]string, 0, len(o.Variables))
+	for k := range o.Variables {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	b.WriteString(o.Profile)
+	for _, k := range names {
+		b.WriteString("\n" + k + "=" + o.Variables[k])
+	}
+	return b.String()
+}
+
+// Resolve applies the selected profile and replaces all variable references.
+// A document is only resolved once, resolving it again with another
+// profile or other variables fails.
+func (s *SolutionDoc) Resolve(opts ResolveOptions) error {
+	if s.resolved {
+		if opts.key() != s.resolvedWith {
+			return fmt.Errorf("solution is already resolved with other profile or variables")
+		}
+		return nil
+	}
+	if err := s.compute(); err != nil {
+		return err
+	}
+	if opts.Lock != LockAuto {
+		s.LockMode = opts.Lock
+	}
+	vars := map[string]string{}
+	for k, v := range s.Variables {
+		vars[k] = v
+	}
+	if opts.Profile != "" {
+		p, ok := s.Profiles[opts.Profile]
+		if !ok || p == nil {
+			return fmt.Errorf("unknown profile %s (available: %s)", opts.Profile, strings.Join(s.ProfileNames(), ", "))
+		}
+		for k, v := range p.Variables {
+			vars[k] = v
+		}
+		if err := s.applyProfile(p); err != nil {
+			return fmt.Errorf("profile %s: %w", opts.Profile, err)
+		}
+	}
+	for k, v := range opts.Variables {
+		vars[k] = v
+	}
+	lookupEnv := opts.LookupEnv
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+	}
+	r := &resolver{vars: vars, env: lookupEnv, resolving: map[string]bool{}}
+	if err := r.doc(s); err != nil {
+		return err
+	}
+	s.vars = vars
+	s.resolved = true
+	s.resolvedWith = opts.key()
+	return nil
+}
+
+// ProfileNames returns the sorted profile names
+func (s *SolutionDoc) ProfileNames() []string {
+	names := make([]string, 0, len(s.Profiles))
+	for name := range s.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyProfile overrides the target fields set in the profile.
+// Overrides for all targets ("*") are applied first.
+func (s *SolutionDoc) applyProfile(p *SolutionProfile) error {
+	for name := range p.Targets {
+		if name == "*" {
+			continue
+		}
+		found := false
+		for _, t := range s.Targets {
+			if t.Name == name {
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown target %s", name)
+		}
+	}
+	for _, t := range s.Targets {
+		if o := p.Targets["*"]; o != nil {
+			t.override(o)
+		}
+		if o := p.Targets[t.Name]; o != nil && t.Name != "" {
+			t.override(o)
+		}
+	}
+	return nil
+}
+
+// override replaces the fields set in the other target.
+// Meta and params are merged.
+func (l *SolutionTarget) override(o *SolutionTarget) {
+	if o.Description != "" {
+		l.Description = o.Description
+	}
+	if o.Inputs != nil {
+		l.Inputs = o.Inputs
+	}
+	if o.Output != "" {
+		l.Output = o.Output
+	}
+	if o.Archive != "" {
+		l.Archive = o.Archive
+	}
+	if o.Template != "" {
+		l.Template = o.Template
+	}
+	if o.Overlays != nil {
+		l.Overlays = o.Overlays
+	}
+	if o.Features != nil {
+		l.Features = o.Features
+	}
+	if o.Force {
+		l.Force = true
+	}
+	if o.Imports != nil {
+		l.Imports = o.Imports
+	}
+	if o.DependsOn != nil {
+		l.DependsOn = o.DependsOn
+	}
+	if o.Hooks != nil {
+		l.Hooks = o.Hooks
+	}
+	if len(o.Meta) > 0 {
+		meta := make(map[string]any, len(l.Meta)+len(o.Meta))
+		for k, v := range l.Meta {
+			meta[k] = v
+		}
+		for k, v := range o.Meta {
+			meta[k] = v
+		}
+		l.Meta = meta
+	}
+	if len(o.Params) > 0 {
+		params := make(map[string]any, len(l.Params)+len(o.Params))
+		for k, v := range l.Params {
+			params[k] = v
+		}
+		for k, v := range o.Params {
+			params[k] = v
+		}
+		l.Params = params
+	}
+}
+
+// resolver replaces variable references
+type resolver struct {
+	vars map[string]string
+	env  func(string) (string, bool)
+	// resolving detects variables referring to themselves
+	resolving map[string]bool
+	// keep are the variables whose references are kept unchanged
+	keep map[string]bool
+	// keepUnknown keeps references to unknown variables unchanged
+	// instead of failing
+	keepUnknown bool
+}
+
+// lenient returns a resolver keeping references to unknown variables
+func (r *resolver) lenient() *resolver {
+	l := *r
+	l.keepUnknown = true
+	return &l
+}
+
+func (r *resolver) doc(s *SolutionDoc) error {
+	var err error
+	fields := []*string{&s.Name, &s.Description, &s.RootDir, &s.EnginePolicy}
+	if err = r.strings(fields...); err != nil {
+		return err
+	}
+	if s.Meta, err = r.lenient().mapValues(s.Meta); err != nil {
+		return err
+	}
+	for _, t := range s.Targets {
+		if err := r.target(t); err != nil {
+			return fmt.Errorf("target %s: %w", t.Name, err)
+		}
+	}
+	return nil
+}
+
+func (r *resolver) target(t *SolutionTarget) error {
+	var err error
+	fields := []*string{&t.Name, &t.Description, &t.Output, &t.Archive, &t.Template}
+	for _, list := range [][]string{t.Inputs, t.Overlays, t.Features, t.Imports, t.DependsOn} {
+		for i := range list {
+			fields = append(fields, &list[i])
+		}
+	}
+	if err = r.strings(fields...); err != nil {
+		return err
+	}
+	if t.Meta, err = r.lenient().mapValues(t.Meta); err != nil {
+		return err
+	}
+	if t.Params, err = r.lenient().mapValues(t.Params); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *resolver) strings(fields ...*string) error {
+	for _, f := range fields {
+		v, err := r.expand(*f)
+		if err != nil {
+			return err
+		}
+		*f = v
+	}
+	return nil
+}
+
+// mapValues returns a copy of the map with all string values expanded
+func (r *resolver) mapValues(m map[string]any) (map[string]any, error) {
+	if m == nil {
+		return nil, nil
+	}
+	result := make(map[string]any, len(m))
+	for k, v := range m {
+		expanded, err := r.value(v)
+		if err != nil {
+			return nil, err
+		}
+		result[k] = expanded
+	}
+	return result, nil
+}
+
+func (r *resolver) value(v any) (any, error) {
+	switch v := v.(type) {
+	case string:
+		return r.expand(v)
+	case map[string]any:
+		return r.mapValues(v)
+	case []any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			expanded, err := r.value(item)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = expanded
+		}
+		return result, nil
+	}
+	return v, nil
+}
+
+// lookup returns the expanded value of the variable
+func (r *resolver) lookup(name string) (string, bool, error) {
+	if v, ok := r.vars[name]; ok {
+		if r.resolving[name] {
+			return "", false, fmt.Errorf("variable %s refers to itself", name)
+		}
+		r.resolving[name] = true
+		defer delete(r.resolving, name)
+		expanded, err := r.expand(v)
+		return expanded, true, err
+	}
+	if v, ok := r.env(name); ok {
+		return v, true, nil
+	}
+	return "", false, nil
+}
+
+// expand replaces all variable references inside the string
+func (r *resolver) expand(s string) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+	var b strings.Builder
+	for {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+		if i > 0 && s[i-1] == '$' {
+			// $${ is a literal ${
+			b.WriteString(s[:i])
+			b.WriteString("{")
+			s = s[i+2:]
+			continue
+		}
+		end := strings.Index(s[i:], "}")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated variable reference in %q", s)
+		}
+		b.WriteString(s[:i])
+		ref := s[i+2 : i+end]
+		name, def, hasDefault := strings.Cut(ref, ":-")
+		if r.keep[name] {
+			b.WriteString(s[i : i+end+1])
+			s = s[i+end+1:]
+			continue
+		}
+		v, ok, err := r.lookup(name)
+		if err != nil {
+			return "", err
+		}
+		if !ok && !hasDefault && r.keepUnknown {
+			b.WriteString(s[i : i+end+1])
+			s = s[i+end+1:]
+			continue
+		}
+		if !ok {
+			if !hasDefault {
+				return "", fmt.Errorf("unknown variable %s", name)
+			}
+			v, err = r.expand(def)
+			if err != nil {
+				return "", err
+			}
+		}
+		b.WriteString(v)
+		s = s[i+end+1:]
+	}
+}
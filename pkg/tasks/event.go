@@ -21,6 +21,8 @@ const (
 	TaskStateStopped
 	// TaskStateFailed is the state when a task is failed
 	TaskStateFailed
+	// TaskStateSkipped is the state when a task is skipped as a task it depends on failed
+	TaskStateSkipped
 )
 
 type TaskEvent struct {
@@ -61,6 +63,8 @@ func (s TaskState) String() string {
 		return "finished"
 	case TaskStateFailed:
 		return "failed"
+	case TaskStateSkipped:
+		return "skipped"
 	default:
 		return "unknown"
 	}
@@ -97,6 +97,12 @@ func (tm *TaskManager) Run(ctx context.Context, name string) error {
 
 // Watch watches a task
 func (tm *TaskManager) Watch(ctx context.Context, name string, dependencies ...string) error {
+	return tm.WatchWith(ctx, name, StaticWatch(dependencies...))
+}
+
+// WatchWith runs the task once and runs it again after changes
+// of the files defined by the watch options
+func (tm *TaskManager) WatchWith(ctx context.Context, name string, opts WatchOptions) error {
 	task := tm.Get(name)
 	if task == nil {
 		return ErrTaskNotFound
@@ -105,7 +111,7 @@ func (tm *TaskManager) Watch(ctx context.Context, name string, dependencies ...s
 	if err != nil {
 		log.Error().Err(err).Str("task", name).Msg("failed to run task")
 	}
-	go task.Watch(ctx, dependencies...)
+	go task.WatchWith(ctx, opts)
 	tm.FireHook(NewTaskEvent(task, TaskStateWatching))
 	return nil
 }
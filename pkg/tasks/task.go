@@ -2,12 +2,7 @@ package tasks
 
 import (
 	"context"
-	"os"
-	"path/filepath"
 	"sync"
-
-	"github.com/apigear-io/cli/pkg/helper"
-	"github.com/fsnotify/fsnotify"
 )
 
 // TaskFunc is the function type of the task to run
@@ -35,11 +30,13 @@ func NewTaskItem(name string, meta map[string]interface{}, tf TaskFunc) *TaskIte
 // Run runs the task once
 func (t *TaskItem) Run(ctx context.Context) error {
 	log.Debug().Msgf("run task: %s", t.name)
+	t.Lock()
 	if t.cancel != nil {
 		// cancel the previous task
 		t.cancel()
 	}
 	ctx, t.cancel = context.WithCancel(ctx)
+	t.Unlock()
 	err := t.taskFunc(ctx)
 	// handle the error
 	if err != nil {
@@ -52,79 +49,15 @@ func (t *TaskItem) Run(ctx context.Context) error {
 }
 
 // Watch watches all the dependencies of the task and runs the task
-// it uses fsnotify to watch the files
+// after changes, see WatchWith
 func (t *TaskItem) Watch(ctx context.Context, dependencies ...string) {
-	if t.watchCancel != nil {
-		t.watchCancel()
-	}
-	ctx, t.watchCancel = context.WithCancel(ctx)
-
-	watcher, err := fsnotify.NewWatcher()
-	if err != nil {
-		log.Error().Msgf("error creating watcher: %s", err)
-		if err := watcher.Close(); err != nil {
-			log.Error().Err(err).Msg("failed to close watcher")
-		}
-		return
-	}
-	defer func() {
-		if err := watcher.Close(); err != nil {
-			log.Error().Err(err).Msg("failed to close watcher")
-		}
-	}()
-
-	for _, dep := range dependencies {
-		// check if the file exists
-		if _, err := os.Stat(dep); os.IsNotExist(err) {
-			log.Debug().Msgf("file %s does not exist", dep)
-			continue
-		}
-		log.Info().Msgf("watching file %s", dep)
-		err := watcher.Add(dep)
-		if err != nil {
-			log.Debug().Msgf("error watching file %s: %s", dep, err)
-		}
-		// check if the dependency is a directory
-		if helper.IsDir(dep) {
-			err = filepath.WalkDir(dep, func(path string, d os.DirEntry, err error) error {
-				if err != nil {
-					log.Error().Err(err).Msgf("error walking directory %s", dep)
-					return err
-				}
-				if d.IsDir() {
-					err = watcher.Add(path)
-					if err != nil {
-						log.Warn().Err(err).Msgf("error watching directory %s", path)
-					}
-				}
-				return nil
-			})
-			if err != nil {
-				log.Warn().Err(err).Msgf("error walking directory %s", dep)
-			}
-		}
-	}
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case event := <-watcher.Events:
-			if event.Op&fsnotify.Write == fsnotify.Write {
-				log.Debug().Msgf("modified file: %s", event.Name)
-				err := t.Run(ctx)
-				if err != nil {
-					log.Error().Err(err).Msgf("failed to run task %s", t.name)
-				}
-			}
-		case err := <-watcher.Errors:
-			log.Error().Msgf("error watching file: %s", err)
-		}
-	}
+	t.WatchWith(ctx, StaticWatch(dependencies...))
 }
 
 // Cancel cancels the task
 func (t *TaskItem) Cancel() {
+	t.RLock()
+	defer t.RUnlock()
 	if t.cancel == nil {
 		return
 	}
@@ -132,6 +65,8 @@ func (t *TaskItem) Cancel() {
 }
 
 func (t *TaskItem) CancelWatch() {
+	t.RLock()
+	defer t.RUnlock()
 	if t.watchCancel == nil {
 		return
 	}
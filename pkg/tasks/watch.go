@@ -0,0 +1,381 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/apigear-io/cli/pkg/helper"
+	"github.com/fsnotify/fsnotify"
+)
+
+// A watched task runs again after changes of its watched files. Dirs are
+// watched recursively, files are watched through their parent dir, so files
+// created later and files replaced by editors are picked up. A burst of
+// changes results in a single run after the debounce period. A change during
+// a run cancels the running task before it runs again.
+
+// DefaultDebounce is the quiet period after the last change before a watched task runs
+const DefaultDebounce = 200 * time.Millisecond
+
+// DefaultExclude are the glob patterns never watched inside dirs:
+// hidden files and dirs (e.g. .git) and editor backup files
+var DefaultExclude = []string{".*", "*~", "*.swp"}
+
+// WatchSpec defines the files watched by a task
+type WatchSpec struct {
+	// Paths are the watched files and dirs, dirs are watched recursively.
+	// Files which do not exist yet are picked up when created.
+	Paths []string
+	// Include are glob patterns of the files watched inside dirs,
+	// empty watches all files
+	Include []string
+	// Exclude are glob patterns of the files and dirs ignored inside dirs
+	Exclude []string
+	// Ignore are files and dirs whose changes never run the task,
+	// e.g. the files written by the task. Explicitly watched files
+	// inside ignored dirs are still watched.
+	Ignore []string
+}
+
+// WatchOptions configure the watch of a task
+type WatchOptions struct {
+	// Debounce is the quiet period after the last change before the task runs.
+	// Zero uses DefaultDebounce.
+	Debounce time.Duration
+	// Spec returns the watched files. It is called again after each run,
+	// so the watch follows changed dependencies.
+	Spec func() (*WatchSpec, error)
+}
+
+// StaticWatch returns watch options for a fixed list of files and dirs
+func StaticWatch(paths ...string) WatchOptions {
+	return WatchOptions{
+		Spec: func() (*WatchSpec, error) {
+			return &WatchSpec{Paths: paths}, nil
+		},
+	}
+}
+
+// MatchGlob reports whether the slash separated path matches the glob pattern.
+// Patterns without a slash match the base name, a leading **/ matches in any dir.
+func MatchGlob(pattern string, name string) bool {
+	name = filepath.ToSlash(name)
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(name))
+		return ok
+	}
+	if rest, ok := strings.CutPrefix(pattern, "**/"); ok {
+		parts := strings.Split(name, "/")
+		for i := range parts {
+			if MatchGlob(rest, strings.Join(parts[i:], "/")) {
+				return true
+			}
+		}
+		return false
+	}
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
+
+// watchSet tracks the files and dirs of a watch spec inside a fsnotify watcher
+type watchSet struct {
+	watcher *fsnotify.Watcher
+	// roots are the recursively watched dirs
+	roots []string
+	// files are the explicitly watched files
+	files   map[string]bool
+	include []string
+	exclude []string
+	// ignore are the files and dirs whose changes are ignored
+	ignore []string
+	// dirs are the dirs added to the watcher
+	dirs map[string]bool
+}
+
+func newWatchSet(watcher *fsnotify.Watcher) *watchSet {
+	return &watchSet{
+		watcher: watcher,
+		files:   map[string]bool{},
+		dirs:    map[string]bool{},
+	}
+}
+
+// update replaces the watched files and dirs with the ones of the spec
+func (w *watchSet) update(spec *WatchSpec) {
+	w.roots = nil
+	w.files = map[string]bool{}
+	w.include = spec.Include
+	w.exclude = append(append([]string{}, DefaultExclude...), spec.Exclude...)
+	w.ignore = nil
+	for _, name := range spec.Ignore {
+		p, err := filepath.Abs(name)
+		if err != nil {
+			log.Warn().Err(err).Msgf("ignore %s", name)
+			continue
+		}
+		w.ignore = append(w.ignore, p)
+	}
+	dirs := map[string]bool{}
+	for _, name := range spec.Paths {
+		p, err := filepath.Abs(name)
+		if err != nil {
+			log.Warn().Err(err).Msgf("watch %s", name)
+			continue
+		}
+		if helper.IsDir(p) {
+			w.roots = append(w.roots, p)
+			w.walk(p, dirs)
+			continue
+		}
+		w.files[p] = true
+		if dir := filepath.Dir(p); helper.IsDir(dir) {
+			dirs[dir] = true
+		} else {
+			log.Debug().Msgf("dir of file %s does not exist", p)
+		}
+	}
+	for dir := range w.dirs {
+		if !dirs[dir] {
+			w.remove(dir)
+		}
+	}
+	for dir := range dirs {
+		w.add(dir)
+	}
+}
+
+// walk collects the dir and all sub dirs which are not excluded
+func (w *watchSet) walk(root string, dirs map[string]bool) {
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if p != root && (w.excluded(p) || w.ignored(p)) {
+			return filepath.SkipDir
+		}
+		dirs[p] = true
+		return nil
+	})
+	if err != nil {
+		log.Warn().Err(err).Msgf("error walking directory %s", root)
+	}
+}
+
+func (w *watchSet) add(dir string) {
+	if w.dirs[dir] {
+		return
+	}
+	log.Debug().Msgf("watching dir %s", dir)
+	if err := w.watcher.Add(dir); err != nil {
+		log.Warn().Err(err).Msgf("error watching directory %s", dir)
+		return
+	}
+	w.dirs[dir] = true
+}
+
+func (w *watchSet) remove(dir string) {
+	delete(w.dirs, dir)
+	// the dir may be removed already
+	_ = w.watcher.Remove(dir)
+}
+
+// root returns the watched root dir containing the path
+// and the relative path inside the root
+func (w *watchSet) root(p string) (string, string, bool) {
+	for _, root := range w.roots {
+		if rel, ok := within(root, p); ok {
+			return root, filepath.ToSlash(rel), true
+		}
+	}
+	return "", "", false
+}
+
+// within returns the relative path, if the path is the dir or inside the dir
+func within(dir string, p string) (string, bool) {
+	rel, err := filepath.Rel(dir, p)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
+}
+
+// excluded returns true if the path or one of its parent dirs inside the root is excluded
+func (w *watchSet) excluded(p string) bool {
+	_, rel, ok := w.root(p)
+	if !ok || rel == "." {
+		return false
+	}
+	parts := strings.Split(rel, "/")
+	for i := range parts {
+		prefix := strings.Join(parts[:i+1], "/")
+		for _, pattern := range w.exclude {
+			if MatchGlob(pattern, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ignored returns true if the path is an ignored file or dir or inside an ignored dir
+func (w *watchSet) ignored(p string) bool {
+	for _, ignore := range w.ignore {
+		if _, ok := within(ignore, p); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// included returns true if the file inside a root matches an include pattern
+func (w *watchSet) included(p string) bool {
+	if len(w.include) == 0 {
+		return true
+	}
+	_, rel, _ := w.root(p)
+	for _, pattern := range w.include {
+		if MatchGlob(pattern, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// handle returns true if the event changes a watched file.
+// Dirs created inside a root are added to the watcher.
+func (w *watchSet) handle(event fsnotify.Event) bool {
+	if event.Op == fsnotify.Chmod {
+		return false
+	}
+	p := filepath.Clean(event.Name)
+	if w.files[p] {
+		return true
+	}
+	if _, _, ok := w.root(p); !ok || w.excluded(p) || w.ignored(p) {
+		return false
+	}
+	if event.Op.Has(fsnotify.Create) && helper.IsDir(p) {
+		dirs := map[string]bool{}
+		w.walk(p, dirs)
+		for dir := range dirs {
+			w.add(dir)
+		}
+		return true
+	}
+	if w.dirs[p] {
+		// a removed or renamed dir
+		w.remove(p)
+		return true
+	}
+	return w.included(p)
+}
+
+// WatchWith watches the files of the watch spec and runs the task after changes.
+// It blocks until the context is cancelled or the watch is cancelled.
+func (t *TaskItem) WatchWith(ctx context.Context, opts WatchOptions) {
+	t.Lock()
+	if t.watchCancel != nil {
+		t.watchCancel()
+	}
+	ctx, t.watchCancel = context.WithCancel(ctx)
+	t.Unlock()
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Error().Msgf("error creating watcher: %s", err)
+		return
+	}
+	defer func() {
+		if err := watcher.Close(); err != nil {
+			log.Error().Err(err).Msg("failed to close watcher")
+		}
+	}()
+	debounce := opts.Debounce
+	if debounce <= 0 {
+		debounce = DefaultDebounce
+	}
+	set := newWatchSet(watcher)
+	refresh := func() {
+		spec, err := opts.Spec()
+		if err != nil {
+			log.Warn().Err(err).Msgf("task %s: keep watching the previous files", t.name)
+			return
+		}
+		set.update(spec)
+	}
+	refresh()
+	log.Info().Msgf("watching %d files and %d dirs of task %s", len(set.files), len(set.roots), t.name)
+
+	var timer *time.Timer
+	var fire <-chan time.Time
+	// running is closed when the current run finished
+	var running chan struct{}
+	for {
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			if running != nil {
+				t.Cancel()
+				<-running
+			}
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if !set.handle(event) {
+				continue
+			}
+			log.Debug().Msgf("changed file: %s (%s)", event.Name, event.Op)
+			if timer == nil {
+				timer = time.NewTimer(debounce)
+			} else {
+				timer.Reset(debounce)
+			}
+			fire = timer.C
+		case <-fire:
+			fire = nil
+			if running != nil {
+				log.Info().Msgf("cancel running task %s", t.name)
+				t.Cancel()
+				<-running
+			}
+			running = t.start(ctx)
+		case <-running:
+			running = nil
+			// the dependencies may have changed with the run
+			refresh()
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Error().Msgf("error watching file: %s", err)
+		}
+	}
+}
+
+// start runs the task in the background.
+// The returned channel is closed when the run finished.
+func (t *TaskItem) start(ctx context.Context) chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		err := t.Run(ctx)
+		if errors.Is(err, context.Canceled) {
+			log.Info().Msgf("task %s cancelled", t.name)
+		} else if err != nil {
+			log.Error().Err(err).Msgf("failed to run task %s", t.name)
+		}
+	}()
+	return done
+}
@@ -0,0 +1,158 @@
+package tasks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchGlob(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		pattern  string
+		name     string
+		expected bool
+	}{
+		{"*.tpl", "templates/module.h.tpl", true},
+		{"*.tpl", "rules.yaml", false},
+		{".*", "templates/.module.swp", true},
+		{"build", "build", true},
+		{"templates/*.tpl", "templates/module.h.tpl", true},
+		{"templates/*.tpl", "templates/sub/module.h.tpl", false},
+		{"**/sub/*.tpl", "templates/sub/module.h.tpl", true},
+		{"**/*.tpl", "templates/sub/module.h.tpl", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, MatchGlob(tt.pattern, tt.name))
+		})
+	}
+}
+
+func TestWatchDirs(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	write := func(name string) {
+		t.Helper()
+		p := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(t, os.WriteFile(p, []byte(name), 0644))
+	}
+	write("a.txt")
+	var runs atomic.Int32
+	task := NewTaskItem("watch", map[string]any{}, func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go task.WatchWith(ctx, WatchOptions{
+		Debounce: 50 * time.Millisecond,
+		Spec: func() (*WatchSpec, error) {
+			return &WatchSpec{Paths: []string{dir}, Include: []string{"*.txt"}}, nil
+		},
+	})
+	time.Sleep(100 * time.Millisecond)
+	// a burst of changes runs the task once
+	for range 5 {
+		write("a.txt")
+	}
+	require.Eventually(t, func() bool { return runs.Load() == 1 }, time.Second, 10*time.Millisecond)
+	// new files in new sub dirs are picked up
+	write("sub/b.txt")
+	require.Eventually(t, func() bool { return runs.Load() == 2 }, time.Second, 10*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	write("sub/c.txt")
+	require.Eventually(t, func() bool { return runs.Load() == 3 }, time.Second, 10*time.Millisecond)
+	// files not included and hidden files are ignored
+	write("c.log")
+	write(".d.txt")
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, int32(3), runs.Load())
+}
+
+func TestWatchIgnore(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	write := func(name string) {
+		t.Helper()
+		p := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(t, os.WriteFile(p, []byte(name), 0644))
+	}
+	write("a.txt")
+	write("out/b.txt")
+	var runs atomic.Int32
+	task := NewTaskItem("watch", map[string]any{}, func(ctx context.Context) error {
+		// the task writes into the watched dir
+		write("out/b.txt")
+		write("out/sub/c.txt")
+		write("report.txt")
+		runs.Add(1)
+		return nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	defer func() {
+		cancel()
+		<-done
+	}()
+	go func() {
+		defer close(done)
+		task.WatchWith(ctx, WatchOptions{
+			Debounce: 50 * time.Millisecond,
+			Spec: func() (*WatchSpec, error) {
+				return &WatchSpec{
+					Paths:   []string{dir},
+					Include: []string{"*.txt"},
+					Ignore:  []string{filepath.Join(dir, "out"), filepath.Join(dir, "report.txt")},
+				}, nil
+			},
+		})
+	}()
+	time.Sleep(100 * time.Millisecond)
+	write("a.txt")
+	require.Eventually(t, func() bool { return runs.Load() == 1 }, time.Second, 10*time.Millisecond)
+	// the files written by the task do not run it again
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, int32(1), runs.Load())
+	write("a.txt")
+	require.Eventually(t, func() bool { return runs.Load() == 2 }, time.Second, 10*time.Millisecond)
+}
+
+func TestWatchCancelsRunningTask(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	file := filepath.Join(dir, "a.txt")
+	var runs, cancelled atomic.Int32
+	task := NewTaskItem("watch", map[string]any{}, func(ctx context.Context) error {
+		if runs.Add(1) > 1 {
+			return nil
+		}
+		// the first run blocks until cancelled
+		<-ctx.Done()
+		cancelled.Add(1)
+		return ctx.Err()
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// the file does not exist yet
+	go task.WatchWith(ctx, WatchOptions{
+		Debounce: 20 * time.Millisecond,
+		Spec: func() (*WatchSpec, error) {
+			return &WatchSpec{Paths: []string{file}}, nil
+		},
+	})
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, os.WriteFile(file, []byte("1"), 0644))
+	require.Eventually(t, func() bool { return runs.Load() == 1 }, time.Second, 10*time.Millisecond)
+	require.NoError(t, os.WriteFile(file, []byte("2"), 0644))
+	require.Eventually(t, func() bool { return runs.Load() == 2 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(1), cancelled.Load())
+}
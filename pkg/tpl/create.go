@@ -11,6 +11,7 @@ import (
 	"github.com/apigear-io/apigear-by-example/tplts"
 	"github.com/apigear-io/apigear-by-example/tplue"
 	"github.com/apigear-io/cli/pkg/helper"
+	"github.com/apigear-io/cli/pkg/tpl/tplcs"
 )
 
 func CreateCustomTemplate(dir string, lang string) error {
@@ -42,6 +43,10 @@ func CreateCustomTemplate(dir string, lang string) error {
 		rules = tplue.RulesYaml
 		apiTpl = tplue.ApiTpl
 		apiTplName = tplue.ApiTplName
+	case "cs":
+		rules = tplcs.RulesYaml
+		apiTpl = tplcs.ApiTpl
+		apiTplName = tplcs.ApiTplName
 	default:
 		return fmt.Errorf("unsupported language: %s", lang)
 	}
@@ -3,7 +3,9 @@ package tpl
 import (
 	"os"
 
+	"github.com/apigear-io/cli/pkg/gen"
 	"github.com/apigear-io/cli/pkg/helper"
+	"github.com/apigear-io/cli/pkg/spec"
 )
 
 type TemplateInfo struct {
@@ -29,3 +31,16 @@ func Info(dir string) (*TemplateInfo, error) {
 	}
 	return info, nil
 }
+
+// ReadParams returns the params declared by the template in the dir,
+// including the params of extended and included templates
+func ReadParams(dir string) ([]*spec.ParamRule, error) {
+	composed, err := gen.ComposeRules(dir, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := composed.Doc.Validate(); err != nil {
+		return nil, err
+	}
+	return composed.Doc.Params, nil
+}
@@ -0,0 +1,543 @@
+package tpl
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/apigear-io/cli/pkg/cfg"
+	"github.com/apigear-io/cli/pkg/gen"
+	"github.com/apigear-io/cli/pkg/gen/filters"
+	"github.com/apigear-io/cli/pkg/helper"
+	"github.com/apigear-io/cli/pkg/idl"
+	"github.com/apigear-io/cli/pkg/spec"
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+)
+
+// Linting a template cross-checks the rules document against the templates dir
+// and executes every template against a synthetic system which covers all type kinds.
+
+//go:embed lint.idl
+var lintIdl string
+
+// LintSeverity is the severity of a lint finding
+type LintSeverity string
+
+const (
+	LintError   LintSeverity = "error"
+	LintWarning LintSeverity = "warning"
+)
+
+// LintFinding is a single problem found while linting a template
+type LintFinding struct {
+	// File is the file the finding refers to
+	File string `json:"file"`
+	// Line is the 1-based line inside the file, zero if unknown
+	Line int `json:"line"`
+	// Column is the 1-based column inside the line, zero if unknown
+	Column int `json:"column"`
+	// Severity is either error or warning
+	Severity LintSeverity `json:"severity"`
+	// Message describes the problem
+	Message string `json:"message"`
+}
+
+// String returns the finding in the form file:line:column: severity: message
+func (f *LintFinding) String() string {
+	loc := f.File
+	if f.Line > 0 {
+		loc += fmt.Sprintf(":%d", f.Line)
+		if f.Column > 0 {
+			loc += fmt.Sprintf(":%d", f.Column)
+		}
+	}
+	return fmt.Sprintf("%s: %s: %s", loc, f.Severity, f.Message)
+}
+
+// HasLintErrors returns true if any of the findings is an error
+func HasLintErrors(findings []*LintFinding) bool {
+	for _, f := range findings {
+		if f.Severity == LintError {
+			return true
+		}
+	}
+	return false
+}
+
+type linter struct {
+	// rootDir is the template dir containing the rules document
+	rootDir string
+	// templatesDir is the dir containing the template files
+	templatesDir string
+	// rulesFile is the rules document, empty if not found
+	rulesFile string
+	rulesAst  *ast.File
+	rules     *spec.RulesDoc
+	// composed are the rules including extended and included templates
+	composed *spec.RulesDoc
+	// templates are the template files relative to the templates dir
+	templates []string
+	// set contains all templates which could be parsed
+	set *template.Template
+	// funcs are the built-in and script filters available to templates
+	funcs template.FuncMap
+	// used records referenced template names
+	used map[string]bool
+	// invalid records rule paths with syntax errors, which are not executed
+	invalid  map[string]bool
+	findings []*LintFinding
+	seen     map[string]bool
+}
+
+// Lint checks the template in the given dir. The dir is either the template
+// dir containing the rules document or its templates folder.
+// The returned findings are sorted by file and line.
+func Lint(dir string) ([]*LintFinding, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !helper.IsDir(dir) {
+		return nil, fmt.Errorf("template dir %s not found", dir)
+	}
+	l := &linter{
+		rootDir:      dir,
+		templatesDir: helper.Join(dir, "templates"),
+		set:          template.New("").Funcs(filters.PopulateFuncMap()),
+		used:         map[string]bool{},
+		invalid:      map[string]bool{},
+		seen:         map[string]bool{},
+	}
+	switch {
+	case helper.IsFile(helper.Join(dir, "rules.yaml")):
+		l.rulesFile = helper.Join(dir, "rules.yaml")
+	case helper.IsFile(helper.Join(filepath.Dir(dir), "rules.yaml")):
+		l.rootDir = filepath.Dir(dir)
+		l.templatesDir = dir
+		l.rulesFile = helper.Join(l.rootDir, "rules.yaml")
+	default:
+		l.templatesDir = dir
+		l.add(dir, 0, 0, LintWarning, "rules.yaml not found, only templates are checked")
+	}
+	if l.rulesFile != "" {
+		if err := l.readRules(); err != nil {
+			return nil, err
+		}
+	}
+	if l.rules != nil {
+		l.composeRules()
+	}
+	if l.funcs == nil {
+		l.loadFilters(l.rootDir)
+	}
+	if err := l.parseTemplates(); err != nil {
+		return nil, err
+	}
+	if l.rules != nil {
+		l.checkFeatures()
+		l.checkDocuments()
+		if err := l.execTemplates(); err != nil {
+			return nil, err
+		}
+		l.checkUnused()
+	}
+	sort.SliceStable(l.findings, func(i, j int) bool {
+		a, b := l.findings[i], l.findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return l.findings, nil
+}
+
+// add records a finding once
+func (l *linter) add(file string, line, col int, severity LintSeverity, format string, args ...any) {
+	f := &LintFinding{
+		File:     file,
+		Line:     line,
+		Column:   col,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	}
+	key := f.String()
+	if l.seen[key] {
+		return
+	}
+	l.seen[key] = true
+	l.findings = append(l.findings, f)
+}
+
+// addTemplateError records a template error at the position reported by the template engine.
+// Errors without a position are reported at the fallback file.
+func (l *linter) addTemplateError(err error, file string, suffix string) {
+	name, line, col, msg := gen.SplitTemplateError(err)
+	if name != "" && helper.IsFile(helper.Join(l.templatesDir, name)) {
+		file = helper.Join(l.templatesDir, name)
+	}
+	l.add(file, line, col, LintError, "%s%s", msg, suffix)
+}
+
+// parseTemplates parses every template file on its own to report all syntax errors
+func (l *linter) parseTemplates() error {
+	if !helper.IsDir(l.templatesDir) {
+		l.add(l.templatesDir, 0, 0, LintError, "templates dir not found")
+		return nil
+	}
+	err := filepath.WalkDir(l.templatesDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".") || !strings.HasSuffix(d.Name(), ".tpl") {
+			return nil
+		}
+		name, err := filepath.Rel(l.templatesDir, path)
+		if err != nil {
+			return err
+		}
+		l.templates = append(l.templates, name)
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		t, err := template.New(name).Funcs(l.funcs).Parse(string(content))
+		if err != nil {
+			l.addTemplateError(err, path, "")
+			return nil
+		}
+		for _, tt := range t.Templates() {
+			if tt.Tree != nil {
+				gen.WalkTemplateCalls(tt.Tree.Root, func(n *parse.TemplateNode) {
+					l.used[n.Name] = true
+				})
+			}
+		}
+		_, err = l.set.New(name).Parse(string(content))
+		if err != nil {
+			l.addTemplateError(err, path, "")
+		}
+		return nil
+	})
+	return err
+}
+
+// readRules validates the rules document against the schema and decodes it
+func (l *linter) readRules() error {
+	content, err := os.ReadFile(l.rulesFile)
+	if err != nil {
+		return err
+	}
+	l.rulesAst, err = parser.ParseBytes(content, 0)
+	if err != nil {
+		l.add(l.rulesFile, 0, 0, LintError, "%s", err)
+		return nil
+	}
+	data, err := spec.YamlToJson(content)
+	if err != nil {
+		l.add(l.rulesFile, 0, 0, LintError, "%s", err)
+		return nil
+	}
+	result, err := spec.CheckJson(spec.DocumentTypeRules, data)
+	if err != nil {
+		return err
+	}
+	for _, e := range result.Errors {
+		line, col := l.rulesPos(schemaFieldPath(e.Field))
+		l.add(l.rulesFile, line, col, LintError, "%s", e.Description)
+	}
+	var doc spec.RulesDoc
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		l.add(l.rulesFile, 0, 0, LintError, "%s", err)
+		return nil
+	}
+	if err := doc.Validate(); err != nil {
+		l.add(l.rulesFile, 0, 0, LintError, "%s", err)
+		return nil
+	}
+	l.rules = &doc
+	return nil
+}
+
+// schemaFieldPath converts a schema field (e.g. features.0.scopes) into a yaml path
+func schemaFieldPath(field string) string {
+	path := "$"
+	if field == "" || field == "(root)" {
+		return path
+	}
+	for _, part := range strings.Split(field, ".") {
+		if _, err := strconv.Atoi(part); err == nil {
+			path += "[" + part + "]"
+		} else {
+			path += "." + part
+		}
+	}
+	return path
+}
+
+// rulesPos returns the line and column of the yaml path inside the rules document
+func (l *linter) rulesPos(path string) (int, int) {
+	if l.rulesAst == nil {
+		return 0, 0
+	}
+	p, err := yaml.PathString(path)
+	if err != nil {
+		return 0, 0
+	}
+	node, err := p.FilterFile(l.rulesAst)
+	if err != nil || node == nil || node.GetToken() == nil {
+		return 0, 0
+	}
+	pos := node.GetToken().Position
+	return pos.Line, pos.Column
+}
+
+// addRule records a finding at the given yaml path of the rules document
+func (l *linter) addRule(path string, severity LintSeverity, format string, args ...any) {
+	if severity == LintError {
+		l.invalid[path] = true
+	}
+	line, col := l.rulesPos(path)
+	l.add(l.rulesFile, line, col, severity, format, args...)
+}
+
+// composeRules resolves the extended and included templates
+// and parses their templates before the own templates
+func (l *linter) composeRules() {
+	l.composed = l.rules
+	if l.rules.Extends == "" && len(l.rules.Includes) == 0 {
+		return
+	}
+	composed, err := gen.ComposeRules(l.rootDir, nil)
+	if err != nil {
+		path := "$.includes"
+		if l.rules.Extends != "" {
+			path = "$.extends"
+		}
+		l.addRule(path, LintError, "%s", err)
+		return
+	}
+	dirs := []string{}
+	for _, layer := range composed.Layers {
+		dirs = append(dirs, filepath.Dir(layer.Dir))
+	}
+	l.loadFilters(append(dirs, l.rootDir)...)
+	if err := gen.ParseLayers(l.set, composed.Layers, l.funcs); err != nil {
+		l.add(l.rulesFile, 0, 0, LintError, "parse composed templates: %s", err)
+		return
+	}
+	l.composed = composed.Doc
+}
+
+// loadFilters loads the script filters of the template dirs.
+// Script errors are reported and only the built-in filters are used.
+func (l *linter) loadFilters(dirs ...string) {
+	funcs, err := gen.LoadFuncMap(dirs...)
+	if err != nil {
+		l.add(helper.Join(l.rootDir, "filters"), 0, 0, LintError, "%s", err)
+		funcs = filters.PopulateFuncMap()
+	}
+	l.funcs = funcs
+	l.set.Funcs(funcs)
+}
+
+// checkFeatures checks for duplicate features and unknown required features
+func (l *linter) checkFeatures() {
+	names := map[string]bool{}
+	for i, f := range l.rules.Features {
+		if names[f.Name] {
+			l.addRule(fmt.Sprintf("$.features[%d].name", i), LintError, "duplicate feature %s", f.Name)
+		}
+		names[f.Name] = true
+	}
+	// features of composed templates can be required
+	for _, f := range l.composed.Features {
+		names[f.Name] = true
+	}
+	for i, f := range l.rules.Features {
+		for k, r := range f.Requires {
+			if !names[r] {
+				l.addRule(fmt.Sprintf("$.features[%d].requires[%d]", i, k), LintError, "feature %s requires unknown feature %s", f.Name, r)
+			}
+		}
+	}
+}
+
+// checkDocuments checks document sources, targets, prefixes and conditions
+func (l *linter) checkDocuments() {
+	funcs := l.funcs
+	for i, f := range l.rules.Features {
+		for k, s := range f.Scopes {
+			scopePath := fmt.Sprintf("$.features[%d].scopes[%d]", i, k)
+			if err := gen.ParseConditionWithFuncs(s.When, funcs); err != nil {
+				l.addRule(scopePath+".when", LintError, "invalid scope condition: %s", err)
+			}
+			if _, err := template.New("prefix").Funcs(funcs).Parse(s.Prefix); err != nil {
+				l.addRule(scopePath+".prefix", LintError, "invalid prefix expression: %s", err)
+			}
+			for d, doc := range s.Documents {
+				docPath := fmt.Sprintf("%s.documents[%d]", scopePath, d)
+				source := filepath.Clean(doc.Source)
+				l.used[source] = true
+				if !l.sourceExists(source, doc.Raw) {
+					l.addRule(docPath+".source", LintError, "source %s not found in %s", doc.Source, l.templatesDir)
+				}
+				if doc.Target != "" {
+					if _, err := template.New("target").Funcs(funcs).Parse(doc.Target); err != nil {
+						l.addRule(docPath+".target", LintError, "invalid target expression: %s", err)
+					}
+				}
+				if err := gen.ParseConditionWithFuncs(doc.When, funcs); err != nil {
+					l.addRule(docPath+".when", LintError, "invalid document condition: %s", err)
+				}
+			}
+		}
+	}
+}
+
+// sourceExists returns true if the document source is a template or a raw file
+func (l *linter) sourceExists(source string, raw bool) bool {
+	if helper.IsFile(helper.Join(l.templatesDir, source)) {
+		return true
+	}
+	return !raw && l.set.Lookup(source) != nil
+}
+
+// checkUnused warns about template files which are neither a document source
+// nor invoked by another template
+func (l *linter) checkUnused() {
+	for _, name := range l.templates {
+		if l.used[name] {
+			continue
+		}
+		// a template file which only defines named templates is a helper file
+		t := l.set.Lookup(name)
+		if t != nil && l.definesUsedTemplates(t) {
+			continue
+		}
+		l.add(helper.Join(l.templatesDir, name), 0, 0, LintWarning, "template is not used by any rule")
+	}
+}
+
+// definesUsedTemplates returns true if the file defines a template used elsewhere
+func (l *linter) definesUsedTemplates(file *template.Template) bool {
+	for _, t := range l.set.Templates() {
+		if t.Tree == nil || t.Name() == file.Name() || t.Tree.ParseName != file.Name() {
+			continue
+		}
+		if l.used[t.Name()] {
+			return true
+		}
+	}
+	return false
+}
+
+// execTemplates renders all documents of all features against the synthetic system
+func (l *linter) execTemplates() error {
+	system, err := idl.LoadIdlFromString("lint", lintIdl)
+	if err != nil {
+		return fmt.Errorf("load lint system: %w", err)
+	}
+	if err := system.Validate(); err != nil {
+		return fmt.Errorf("validate lint system: %w", err)
+	}
+	features := map[string]bool{}
+	for _, f := range l.composed.Features {
+		features[f.Name] = true
+	}
+	// templates are executed with the default params
+	params, err := l.composed.ResolveParams(nil)
+	if err != nil {
+		return err
+	}
+	meta := map[string]any{
+		"Layer": &spec.SolutionTarget{
+			Name:     "lint",
+			Inputs:   []string{"lint.idl"},
+			Output:   "lint",
+			Template: filepath.Base(l.rootDir),
+			Features: []string{"all"},
+		},
+		"App": cfg.GetBuildInfo("cli"),
+	}
+	system.Meta = meta
+	for i, f := range l.rules.Features {
+		for k, s := range f.Scopes {
+			scopePath := fmt.Sprintf("$.features[%d].scopes[%d]", i, k)
+			err := gen.WalkScopes(system, features, meta, params, func(match spec.ScopeType, ctx any) error {
+				if match == s.Match {
+					l.execScope(f, s, scopePath, ctx)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// execScope renders the documents of the scope using the given context
+func (l *linter) execScope(f *spec.FeatureRule, s *spec.ScopeRule, scopePath string, ctx any) {
+	suffix := fmt.Sprintf(" (feature %s, match %s)", f.Name, s.Match)
+	if l.invalid[scopePath+".when"] || l.invalid[scopePath+".prefix"] {
+		return
+	}
+	ok, err := gen.EvalConditionWithFuncs(s.When, ctx, l.funcs)
+	if err != nil {
+		l.addRule(scopePath+".when", LintError, "eval scope condition: %s%s", stripTemplatePrefix(err), suffix)
+		return
+	}
+	if !ok {
+		return
+	}
+	for d, doc := range s.Documents {
+		docPath := fmt.Sprintf("%s.documents[%d]", scopePath, d)
+		if l.invalid[docPath+".when"] {
+			continue
+		}
+		ok, err := gen.EvalConditionWithFuncs(doc.When, ctx, l.funcs)
+		if err != nil {
+			l.addRule(docPath+".when", LintError, "eval document condition: %s%s", stripTemplatePrefix(err), suffix)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		target := doc.Target
+		if target == "" {
+			target = doc.Source
+		}
+		// invalid target expressions are already reported
+		if !l.invalid[docPath+".target"] {
+			if _, err := gen.RenderStringWithFuncs(s.Prefix+target, ctx, l.funcs); err != nil {
+				l.addRule(docPath+".target", LintError, "render target: %s%s", stripTemplatePrefix(err), suffix)
+			}
+		}
+		source := filepath.Clean(doc.Source)
+		if doc.Raw || l.set.Lookup(source) == nil {
+			continue
+		}
+		if err := l.set.ExecuteTemplate(io.Discard, source, ctx); err != nil {
+			l.addTemplateError(err, helper.Join(l.templatesDir, source), suffix)
+		}
+	}
+}
+
+// stripTemplatePrefix removes the position of inline rule templates from the error
+func stripTemplatePrefix(err error) string {
+	_, _, _, msg := gen.SplitTemplateError(err)
+	return msg
+}
@@ -0,0 +1,198 @@
+package tpl
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/apigear-io/cli/pkg/cfg"
+	"github.com/apigear-io/cli/pkg/gen"
+	"github.com/apigear-io/cli/pkg/helper"
+	"github.com/apigear-io/cli/pkg/model"
+	"github.com/apigear-io/cli/pkg/sol"
+	"github.com/apigear-io/cli/pkg/spec"
+)
+
+// A template test renders a fixture module from the template "tests" folder
+// and compares the output with a golden directory next to the fixture.
+// For example "tests/demo.idl" is compared with "tests/demo.golden".
+
+// TestOptions are the options to run template tests
+type TestOptions struct {
+	// Dir is the template dir containing the rules document
+	Dir string
+	// Features is a list of features to enable
+	Features []string
+	// Update writes the rendered output into the golden directories
+	Update bool
+}
+
+// TestResult is the result of a single template test
+type TestResult struct {
+	// Name is the name of the fixture
+	Name string
+	// Fixture is the fixture file
+	Fixture string
+	// GoldenDir is the directory with the expected output
+	GoldenDir string
+	// Changes are the differences between the golden and the rendered output
+	Changes []*gen.FileChange
+	// Updated is true if the golden directory was updated
+	Updated bool
+	// Err is set when the fixture could not be rendered
+	Err error
+}
+
+// Passed returns true if the rendered output matches the golden directory
+func (r *TestResult) Passed() bool {
+	return r.Err == nil && (r.Updated || len(r.Changes) == 0)
+}
+
+// FindTestFixtures returns the fixture modules inside the template tests folder
+func FindTestFixtures(dir string) ([]string, error) {
+	testsDir := helper.Join(dir, "tests")
+	if !helper.IsDir(testsDir) {
+		return nil, fmt.Errorf("no tests folder found in %s", dir)
+	}
+	entries, err := os.ReadDir(testsDir)
+	if err != nil {
+		return nil, err
+	}
+	fixtures := []string{}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		switch filepath.Ext(e.Name()) {
+		case ".idl", ".yaml", ".yml", ".json":
+			fixtures = append(fixtures, helper.Join(testsDir, e.Name()))
+		}
+	}
+	sort.Strings(fixtures)
+	return fixtures, nil
+}
+
+// fixtureName returns the fixture name without module extensions
+func fixtureName(fixture string) string {
+	name := filepath.Base(fixture)
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	return strings.TrimSuffix(name, ".module")
+}
+
+// RunTests renders all fixtures of the template and compares them with the golden directories
+func RunTests(opts TestOptions) ([]*TestResult, error) {
+	dir, err := filepath.Abs(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(opts.Features) == 0 {
+		opts.Features = []string{"all"}
+	}
+	rulesFile := helper.Join(dir, "rules.yaml")
+	if _, err := gen.ComposeRules(dir, nil); err != nil {
+		return nil, fmt.Errorf("read rules %s: %w", rulesFile, err)
+	}
+	fixtures, err := FindTestFixtures(dir)
+	if err != nil {
+		return nil, err
+	}
+	results := []*TestResult{}
+	for _, fixture := range fixtures {
+		name := fixtureName(fixture)
+		result := &TestResult{
+			Name:      name,
+			Fixture:   fixture,
+			GoldenDir: helper.Join(filepath.Dir(fixture), name+".golden"),
+		}
+		result.Err = runTest(dir, opts, result)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// runTest renders a single fixture in memory and compares or updates the golden directory
+func runTest(dir string, opts TestOptions, result *TestResult) error {
+	system := model.NewSystem(result.Name)
+	if err := sol.ParseInputs(system, []string{result.Fixture}); err != nil {
+		return err
+	}
+	// the layer mimics a solution target for templates using it
+	layer := &spec.SolutionTarget{
+		Name:     result.Name,
+		Inputs:   []string{filepath.Base(result.Fixture)},
+		Output:   filepath.Base(result.GoldenDir),
+		Template: filepath.Base(dir),
+		Features: opts.Features,
+	}
+	meta := map[string]any{
+		"Layer": layer,
+		"App":   cfg.GetBuildInfo("cli"),
+	}
+	system.Meta = meta
+	// rules are composed for every fixture, as processing modifies them
+	composed, err := gen.ComposeRules(dir, nil)
+	if err != nil {
+		return err
+	}
+	out := gen.NewMemoryWriter(result.GoldenDir)
+	g, err := gen.New(gen.Options{
+		OutputDir:    result.GoldenDir,
+		TemplatesDir: helper.Join(dir, "templates"),
+		Layers:       composed.Layers,
+		System:       system,
+		Features:     opts.Features,
+		Force:        true,
+		Output:       out,
+		Meta:         meta,
+	})
+	if err != nil {
+		return err
+	}
+	if err := g.ProcessRules(composed.Doc); err != nil {
+		return err
+	}
+	if opts.Update {
+		result.Updated = true
+		return updateGolden(result.GoldenDir, out)
+	}
+	diff := gen.NewDiffWriter(result.GoldenDir)
+	for _, name := range out.Names() {
+		content, err := out.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		err = diff.Write(content, helper.Join(result.GoldenDir, filepath.FromSlash(name)))
+		if err != nil {
+			return err
+		}
+	}
+	if err := diff.DetectRemoved(result.GoldenDir); err != nil {
+		return err
+	}
+	result.Changes = diff.Changes()
+	return nil
+}
+
+// updateGolden replaces the golden directory with the rendered output
+func updateGolden(goldenDir string, out *gen.MemoryWriter) error {
+	log.Info().Msgf("update golden dir %s", goldenDir)
+	if err := os.RemoveAll(goldenDir); err != nil {
+		return err
+	}
+	for _, name := range out.Names() {
+		content, err := out.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		target := helper.Join(goldenDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, content, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
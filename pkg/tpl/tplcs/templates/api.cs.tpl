@@ -0,0 +1,50 @@
+using System;
+
+namespace {{Dot .Module.Name}}
+{
+{{- range .Module.Externs }}
+    // extern {{.Name}}
+{{- $ns := (csExtern .).Namespace }}
+{{- if $ns }} from {{$ns}}{{ end }}
+{{- end }}
+
+    // enumerations
+{{- range .Module.Enums }}
+    public enum {{Camel .Name}}
+    {
+{{- range .Members }}
+        {{Camel .Name}} = {{.Value}},
+{{- end }}
+    }
+{{- end }}
+
+    // data structures
+{{- range .Module.Structs }}
+    public class {{Camel .Name}}
+    {
+{{- range .Fields }}
+        public {{csType "" .}} {{Camel .Name}} { get; set; } = {{csDefault "" .}};
+{{- end }}
+    }
+{{- end }}
+
+    // interfaces
+{{- range .Module.Interfaces }}
+    public interface I{{Camel .Name}}
+    {
+        // properties
+{{- range .Properties }}
+        {{csType "" .}} {{Camel .Name}} { get; set; }
+        event Action<{{csType "" .}}> {{Camel .Name}}Changed;
+{{- end }}
+        // methods
+{{- range .Operations }}
+        {{csReturn "" .Return}} {{Camel .Name}}({{csParams "" .Params}});
+{{- end }}
+        // signals
+{{- range .Signals }}
+        event Action{{ if .Params }}<{{ range $i, $p := .Params }}{{ if $i }}, {{ end }}{{ csType "" $p }}{{ end }}>{{ end }} {{Camel .Name}};
+{{- end }}
+    }
+{{- end }}
+}
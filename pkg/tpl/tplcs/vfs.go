@@ -0,0 +1,12 @@
+// Package tplcs contains the C# starter template used by 'template create'.
+package tplcs
+
+import _ "embed"
+
+var (
+	//go:embed rules.yaml
+	RulesYaml []byte
+	//go:embed templates/api.cs.tpl
+	ApiTpl     []byte
+	ApiTplName = "api.cs.tpl"
+)
@@ -1,12 +1,21 @@
 package tests
 
 import (
+	"archive/zip"
+	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	gencmd "github.com/apigear-io/cli/pkg/cmd/gen"
+	"github.com/apigear-io/cli/pkg/gen"
 	"github.com/apigear-io/cli/pkg/helper"
+	"github.com/apigear-io/cli/pkg/sol"
+	"github.com/apigear-io/cli/pkg/spec"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -41,3 +50,489 @@ func TestGenerateExpertCmd(t *testing.T) {
 	output := execute(t, "generate expert -i apigear -o out -t tpl")
 	assert.Contains(t, output, "generated 1 files")
 }
+
+// test generate solution command with a bounded number of jobs
+func TestGenerateSolutionJobsCmd(t *testing.T) {
+	setup(t)
+	output := execute(t, "generate solution ./apigear/test.solution.yaml --jobs 2")
+	assert.Contains(t, output, "generated 1 files")
+}
+
+// test generate solution command in diff mode
+func TestGenerateSolutionDiffCmd(t *testing.T) {
+	setup(t)
+	output := execute(t, "generate solution ./apigear/test.solution.yaml --diff")
+	assert.Contains(t, output, "generated output is up to date")
+	assert.NotContains(t, output, "+++")
+	// a missing file is reported as added, but not written
+	assert.NoError(t, os.Remove("apigear/test/test.yaml"))
+	output = execute(t, "generate solution ./apigear/test.solution.yaml --diff --patch out.patch")
+	assert.Contains(t, output, "--- /dev/null\n+++ b/test/test.yaml")
+	assert.Contains(t, output, "generated output is out of date")
+	assert.NoFileExists(t, "apigear/test/test.yaml")
+	assert.FileExists(t, "out.patch")
+	// files not generated are reported as removed on request
+	output = execute(t, "generate solution ./apigear/test.solution.yaml --diff --include-removed")
+	assert.Contains(t, output, "--- a/test/test2.yaml\n+++ /dev/null")
+	// paths are relative to the solution root dir
+	assert.NoError(t, os.MkdirAll("solutions", 0755))
+	solution := `schema: apigear.solution/1.0
+rootDir: apigear
+targets:
+  - name: test
+    inputs: [test.module.yaml]
+    output: test
+    template: ../tpl
+`
+	assert.NoError(t, os.WriteFile("solutions/root.solution.yaml", []byte(solution), 0644))
+	output = execute(t, "generate solution ./solutions/root.solution.yaml --diff")
+	assert.Contains(t, output, "--- /dev/null\n+++ b/test/test.yaml")
+}
+
+// test diff of an invalid solution fails instead of reporting the output as up to date
+func TestGenerateSolutionDiffInvalidCmd(t *testing.T) {
+	setup(t)
+	solution := `schema: apigear.solution/1.0
+targets: invalid
+`
+	assert.NoError(t, os.WriteFile("apigear/invalid.solution.yaml", []byte(solution), 0644))
+	output := execute(t, "generate solution ./apigear/invalid.solution.yaml --diff")
+	assert.Contains(t, output, "Error: ./apigear/invalid.solution.yaml: solution document is not valid")
+	assert.NotContains(t, output, "up to date")
+	assert.NotContains(t, output, "Usage:")
+	var b strings.Builder
+	err := gencmd.RunDiffSolution(&b, "./apigear/invalid.solution.yaml", false, sol.RunOptions{}, "")
+	assert.ErrorIs(t, err, gencmd.ErrSolutionInvalid)
+}
+
+// test generate solution command writing into an archive
+func TestGenerateSolutionArchiveCmd(t *testing.T) {
+	setup(t)
+	assert.NoError(t, os.Remove("apigear/test/test.yaml"))
+	output := execute(t, "generate solution ./apigear/test.solution.yaml --archive sdk.zip")
+	assert.Contains(t, output, "generated 1 files")
+	assert.FileExists(t, "sdk.zip")
+	assert.NoFileExists(t, "apigear/test/test.yaml")
+	r, err := zip.OpenReader("sdk.zip")
+	assert.NoError(t, err)
+	defer r.Close()
+	assert.Len(t, r.File, 1)
+	assert.Equal(t, "test/test.yaml", r.File[0].Name)
+}
+
+// test a failed run discards the partial archive and keeps an existing archive
+func TestGenerateSolutionArchiveFailedCmd(t *testing.T) {
+	setup(t)
+	assert.NoError(t, os.MkdirAll("tpl-broken/templates", 0755))
+	err := os.WriteFile("tpl-broken/rules.yaml", []byte(`features:
+  - name: core
+    scopes:
+      - match: module
+        documents:
+          - { source: "module.yaml.tpl", target: "broken.yaml" }
+`), 0644)
+	assert.NoError(t, err)
+	err = os.WriteFile("tpl-broken/templates/module.yaml.tpl", []byte("{{ .Module.Missing }}\n"), 0644)
+	assert.NoError(t, err)
+	solution := `schema: apigear.solution/1.0
+targets:
+  - name: test
+    inputs: [test.module.yaml]
+    output: test
+    template: ../tpl
+  - name: broken
+    inputs: [test.module.yaml]
+    output: broken
+    template: ../tpl-broken
+    archive: broken.zip
+`
+	assert.NoError(t, os.WriteFile("apigear/broken.solution.yaml", []byte(solution), 0644))
+	output := execute(t, "generate solution ./apigear/broken.solution.yaml --archive sdk.zip --keep-going")
+	assert.Contains(t, output, "target broken: failed")
+	assert.Contains(t, output, "discard partial archive sdk.zip")
+	assert.NoFileExists(t, "sdk.zip")
+	// without a solution archive the target archive is discarded
+	output = execute(t, "generate solution ./apigear/broken.solution.yaml --keep-going")
+	assert.Contains(t, output, "discard partial archive")
+	assert.NoFileExists(t, "apigear/broken.zip")
+	// an archive of a previous run is kept unchanged
+	execute(t, "generate solution ./apigear/test.solution.yaml --archive sdk.zip")
+	assert.FileExists(t, "sdk.zip")
+	output = execute(t, "generate solution ./apigear/broken.solution.yaml --archive sdk.zip --keep-going")
+	assert.Contains(t, output, "discard partial archive, keep existing archive sdk.zip")
+	r, err := zip.OpenReader("sdk.zip")
+	assert.NoError(t, err)
+	defer r.Close()
+	assert.Len(t, r.File, 1)
+	entries, err := os.ReadDir(".")
+	assert.NoError(t, err)
+	for _, e := range entries {
+		assert.False(t, strings.HasPrefix(e.Name(), ".sdk.zip"), "temporary archive %s", e.Name())
+	}
+}
+
+func TestGenerateSolutionOverlayCmd(t *testing.T) {
+	setup(t)
+	err := os.MkdirAll("apigear/overlay/templates", 0755)
+	assert.NoError(t, err)
+	err = os.WriteFile("apigear/overlay/templates/module.yaml.tpl", []byte("overlay: {{.Module.Name}}\n"), 0644)
+	assert.NoError(t, err)
+	solution := `schema: apigear.solution/1.0
+targets:
+  - name: test
+    inputs:
+      - test.module.yaml
+    output: test
+    template: ../tpl
+    overlays:
+      - overlay
+    force: true
+`
+	err = os.WriteFile("apigear/overlay.solution.yaml", []byte(solution), 0644)
+	assert.NoError(t, err)
+	output := execute(t, "generate solution ./apigear/overlay.solution.yaml")
+	assert.Contains(t, output, "generated 1 files")
+	content, err := os.ReadFile("apigear/test/test.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, "overlay: test\n", string(content))
+}
+
+func TestGenerateSolutionParamsCmd(t *testing.T) {
+	setup(t)
+	rules := `params:
+  - { name: namespace, default: demo }
+features:
+  - name: core
+    scopes:
+      - match: module
+        documents:
+          - { source: "module.yaml.tpl", target: "{{dot .Module.Name}}.yaml" }
+`
+	err := os.WriteFile("tpl/rules.yaml", []byte(rules), 0644)
+	assert.NoError(t, err)
+	err = os.WriteFile("tpl/templates/module.yaml.tpl", []byte("namespace: {{.Params.namespace}}\n"), 0644)
+	assert.NoError(t, err)
+	solution := `schema: apigear.solution/1.0
+targets:
+  - inputs: [test.module.yaml]
+    output: test
+    template: ../tpl
+    force: true
+    params:
+      namespace: acme
+`
+	err = os.WriteFile("apigear/params.solution.yaml", []byte(solution), 0644)
+	assert.NoError(t, err)
+	execute(t, "generate solution ./apigear/params.solution.yaml")
+	content, err := os.ReadFile("apigear/test/test.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, "namespace: acme\n", string(content))
+	// unknown params are reported
+	err = os.WriteFile("apigear/params.solution.yaml", []byte(strings.Replace(solution, "namespace: acme", "namespce: acme", 1)), 0644)
+	assert.NoError(t, err)
+	output := execute(t, "generate solution ./apigear/params.solution.yaml")
+	assert.Contains(t, output, "unknown template param namespce")
+}
+
+func TestGenerateSolutionDependsOnCmd(t *testing.T) {
+	setup(t)
+	err := os.MkdirAll("tpl-broken/templates", 0755)
+	assert.NoError(t, err)
+	err = os.WriteFile("tpl-broken/rules.yaml", []byte(`features:
+  - name: core
+    scopes:
+      - match: module
+        documents:
+          - { source: "module.yaml.tpl", target: "broken.yaml" }
+`), 0644)
+	assert.NoError(t, err)
+	err = os.WriteFile("tpl-broken/templates/module.yaml.tpl", []byte("{{ .Module.Missing }}\n"), 0644)
+	assert.NoError(t, err)
+	solution := `schema: apigear.solution/1.0
+targets:
+  - name: api
+    inputs: [test.module.yaml]
+    output: api
+    template: ../tpl
+  - name: impl
+    dependsOn: [api]
+    inputs: [test.module.yaml]
+    output: impl
+    template: ../tpl
+  - name: broken
+    inputs: [test.module.yaml]
+    output: broken
+    template: ../tpl-broken
+  - name: docs
+    dependsOn: [broken, impl]
+    inputs: [test.module.yaml]
+    output: docs
+    template: ../tpl
+`
+	err = os.WriteFile("apigear/deps.solution.yaml", []byte(solution), 0644)
+	assert.NoError(t, err)
+	output := execute(t, "generate solution ./apigear/deps.solution.yaml --keep-going")
+	assert.Contains(t, output, "target api: succeeded")
+	assert.Contains(t, output, "target impl: succeeded")
+	assert.Contains(t, output, "target broken: failed")
+	assert.Contains(t, output, "target docs: skipped")
+	assert.FileExists(t, "apigear/impl/test.yaml")
+	assert.NoFileExists(t, "apigear/docs/test.yaml")
+	// cycles are reported before any target runs
+	solution = strings.Replace(solution, "dependsOn: [api]", "dependsOn: [docs]", 1)
+	err = os.WriteFile("apigear/deps.solution.yaml", []byte(solution), 0644)
+	assert.NoError(t, err)
+	output = execute(t, "generate solution ./apigear/deps.solution.yaml")
+	assert.Contains(t, output, "target dependency cycle: impl -> docs -> impl")
+}
+
+func TestGenerateSolutionIncrementalCmd(t *testing.T) {
+	setup(t)
+	output := execute(t, "generate status ./apigear/test.solution.yaml")
+	assert.Regexp(t, `test\s+new\s+never generated`, output)
+	output = execute(t, "generate solution ./apigear/test.solution.yaml")
+	assert.Contains(t, output, "target test: succeeded")
+	assert.FileExists(t, "apigear/.apigear/state.json")
+	output = execute(t, "generate status ./apigear/test.solution.yaml")
+	assert.Regexp(t, `test\s+up-to-date`, output)
+	// unchanged targets are skipped
+	output = execute(t, "generate solution ./apigear/test.solution.yaml")
+	assert.Contains(t, output, "target test: up-to-date")
+	// template changes make the target stale
+	err := os.WriteFile("tpl/templates/module.yaml.tpl", []byte("changed: {{.Module.Name}}\n"), 0644)
+	assert.NoError(t, err)
+	output = execute(t, "generate status ./apigear/test.solution.yaml")
+	assert.Regexp(t, `test\s+stale\s+templates changed`, output)
+	output = execute(t, "generate solution ./apigear/test.solution.yaml")
+	assert.Contains(t, output, "target test: succeeded")
+	// force generates up-to-date targets
+	output = execute(t, "generate solution ./apigear/test.solution.yaml --force")
+	assert.Contains(t, output, "target test: succeeded")
+	// missing generated files make the target stale
+	err = os.Remove("apigear/test/test.yaml")
+	assert.NoError(t, err)
+	output = execute(t, "generate status ./apigear/test.solution.yaml")
+	assert.Regexp(t, `test\s+stale\s+generated files missing`, output)
+}
+
+func TestGenerateSolutionRestoreMissingCmd(t *testing.T) {
+	setup(t)
+	output := execute(t, "generate solution ./apigear/test.solution.yaml")
+	assert.Contains(t, output, "target test: succeeded")
+	// template changes without output changes skip the documents with the same content
+	err := os.WriteFile("tpl/README.md", []byte("changed\n"), 0644)
+	assert.NoError(t, err)
+	output = execute(t, "generate solution ./apigear/test.solution.yaml")
+	assert.Contains(t, output, "target test: succeeded")
+	// skipped documents are part of the state, so deleted files are generated again
+	err = os.Remove("apigear/test/test.yaml")
+	assert.NoError(t, err)
+	output = execute(t, "generate solution ./apigear/test.solution.yaml")
+	assert.Contains(t, output, "target test: succeeded")
+	assert.FileExists(t, "apigear/test/test.yaml")
+}
+
+func TestGenerateSolutionProfileCmd(t *testing.T) {
+	setup(t)
+	solution := `schema: apigear.solution/1.0
+variables:
+  build: debug
+profiles:
+  release:
+    variables:
+      build: release
+    targets:
+      test:
+        meta: { optimize: true }
+targets:
+  - name: test
+    inputs: [test.module.yaml]
+    output: out/${build}
+    template: ../tpl
+`
+	err := os.WriteFile("apigear/vars.solution.yaml", []byte(solution), 0644)
+	assert.NoError(t, err)
+	execute(t, "generate solution ./apigear/vars.solution.yaml")
+	assert.FileExists(t, "apigear/out/debug/test.yaml")
+	execute(t, "generate solution ./apigear/vars.solution.yaml --profile release")
+	assert.FileExists(t, "apigear/out/release/test.yaml")
+	execute(t, "generate solution ./apigear/vars.solution.yaml --profile release --set build=ci")
+	assert.FileExists(t, "apigear/out/ci/test.yaml")
+	output := execute(t, "generate solution ./apigear/vars.solution.yaml --profile nightly")
+	assert.Contains(t, output, "unknown profile nightly")
+}
+
+func TestRunDocProfile(t *testing.T) {
+	setup(t)
+	rootDir, err := filepath.Abs("apigear")
+	assert.NoError(t, err)
+	doc := &spec.SolutionDoc{
+		RootDir:   rootDir,
+		Variables: map[string]string{"build": "debug"},
+		Profiles: map[string]*spec.SolutionProfile{
+			"release": {Variables: map[string]string{"build": "release"}},
+		},
+		Targets: []*spec.SolutionTarget{
+			{Name: "test", Inputs: []string{"test.module.yaml"}, Output: "out/${build}", Template: "../tpl"},
+		},
+	}
+	// validation keeps the variables for the profile of the runner
+	assert.NoError(t, doc.Validate())
+	runner := sol.NewRunner()
+	runner.Options.Profile = "release"
+	err = runner.RunDoc(context.Background(), doc.RootDir, doc)
+	assert.NoError(t, err)
+	assert.FileExists(t, "apigear/out/release/test.yaml")
+	assert.NoDirExists(t, "apigear/out/debug")
+}
+
+func TestGenerateSolutionHooksCmd(t *testing.T) {
+	setup(t)
+	solution := `schema: apigear.solution/1.0
+variables:
+  greeting: hello
+hooks:
+  after:
+    - name: collect
+      run: cat "$APIGEAR_FILES_LIST" > all.txt
+targets:
+  - name: test
+    inputs: [test.module.yaml]
+    output: out
+    template: ../tpl
+    force: true
+    hooks:
+      before:
+        - run: echo "${greeting} ${APIGEAR_TARGET}" > before.txt
+      after:
+        - run: echo "$APIGEAR_FILES" > files.txt
+        - { name: lint, run: "echo lint problem; exit 3", onFailure: warn }
+`
+	err := os.WriteFile("apigear/hooks.solution.yaml", []byte(solution), 0644)
+	assert.NoError(t, err)
+	output := execute(t, "generate solution ./apigear/hooks.solution.yaml")
+	assert.Contains(t, output, "target test: succeeded")
+	assert.Contains(t, output, "target test after hook lint: exit status 3")
+	// the output dir does not exist before the first generation
+	content, err := os.ReadFile("apigear/before.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello test\n", string(content))
+	content, err = os.ReadFile("apigear/out/files.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "test.yaml\n", string(content))
+	content, err = os.ReadFile("apigear/all.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "out/test.yaml", string(content))
+	// hooks are not run on request
+	assert.NoError(t, os.Remove("apigear/all.txt"))
+	execute(t, "generate solution ./apigear/hooks.solution.yaml --no-hooks")
+	assert.NoFileExists(t, "apigear/all.txt")
+	// failing hooks fail the target and skip the solution hooks
+	solution = strings.Replace(solution, "onFailure: warn", "onFailure: fail", 1)
+	err = os.WriteFile("apigear/hooks.solution.yaml", []byte(solution), 0644)
+	assert.NoError(t, err)
+	output = execute(t, "generate solution ./apigear/hooks.solution.yaml")
+	assert.Contains(t, output, "target test: failed")
+	assert.Contains(t, output, "lint problem")
+	assert.NoFileExists(t, "apigear/all.txt")
+	// hooks are stopped after the timeout
+	solution = strings.Replace(solution, "echo lint problem; exit 3", "sleep 5", 1)
+	solution = strings.Replace(solution, "onFailure: fail", "timeout: 100ms", 1)
+	err = os.WriteFile("apigear/hooks.solution.yaml", []byte(solution), 0644)
+	assert.NoError(t, err)
+	output = execute(t, "generate solution ./apigear/hooks.solution.yaml")
+	assert.Contains(t, output, "target test after hook lint: timed out after 100ms")
+}
+
+func TestGenerateSolutionReportCmd(t *testing.T) {
+	setup(t)
+	rules := `languages: [cpp]
+features:
+  - name: core
+    scopes:
+      - match: module
+        documents:
+          - { source: "module.yaml.tpl", target: "{{dot .Module.Name}}.yaml" }
+`
+	assert.NoError(t, os.WriteFile("tpl/rules.yaml", []byte(rules), 0644))
+	module := `schema: apigear.module/1.0
+name: report
+version: 1.0.0
+interfaces:
+  - name: Counter
+    properties:
+      - { name: default, type: int }
+`
+	assert.NoError(t, os.WriteFile("apigear/report.module.yaml", []byte(module), 0644))
+	solution := `schema: apigear.solution/1.0
+targets:
+  - name: test
+    inputs: [report.module.yaml]
+    output: out
+    template: ../tpl
+    hooks:
+      after:
+        - { name: lint, run: "exit 3", onFailure: warn }
+`
+	assert.NoError(t, os.WriteFile("apigear/report.solution.yaml", []byte(solution), 0644))
+	output := execute(t, "generate solution ./apigear/report.solution.yaml --report build/report.json")
+	assert.Contains(t, output, "report written to build/report.json")
+	data, err := os.ReadFile("build/report.json")
+	assert.NoError(t, err)
+	var report sol.Report
+	assert.NoError(t, json.Unmarshal(data, &report))
+	assert.Equal(t, "succeeded", report.Status)
+	assert.Len(t, report.Targets, 1)
+	target := report.Targets[0]
+	assert.Equal(t, "test", target.Name)
+	assert.Equal(t, "../tpl", target.Template)
+	assert.Equal(t, "out", target.OutputDir)
+	assert.Len(t, target.Documents, 1)
+	assert.Equal(t, "report.yaml", target.Documents[0].Target)
+	assert.Equal(t, "module.yaml.tpl", target.Documents[0].Source)
+	assert.Equal(t, gen.DocumentWritten, target.Documents[0].Action)
+	assert.Equal(t, "new", target.Documents[0].Reason)
+	assert.Equal(t, []sol.TargetWarning{{Rule: sol.WarningReservedKeyword, Message: `type name "default" is a reserved keyword in cpp`}}, target.Warnings)
+	assert.Len(t, target.Hooks, 1)
+	assert.Equal(t, "exit status 3", target.Hooks[0].Error)
+	// up to date targets are skipped test cases
+	execute(t, "generate solution ./apigear/report.solution.yaml --report build/report.xml")
+	data, err = os.ReadFile("build/report.xml")
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `<testsuites name="apigear" tests="1" failures="0" skipped="1"`)
+	assert.Contains(t, string(data), `<skipped message="up-to-date"></skipped>`)
+	execute(t, "generate solution ./apigear/report.solution.yaml --force --report build/report.xml")
+	data, err = os.ReadFile("build/report.xml")
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `<testcase name="out/report.yaml" classname="test"`)
+	assert.Contains(t, string(data), `<failure message="exit status 3">exit 3</failure>`)
+	assert.Contains(t, string(data), `warning [reserved-keyword]: type name &#34;default&#34; is a reserved keyword in cpp`)
+	// warnings and failures are sarif results
+	execute(t, "generate solution ./apigear/report.solution.yaml --force --report build/report.json --report-format sarif")
+	data, err = os.ReadFile("build/report.json")
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"version": "2.1.0"`)
+	assert.Contains(t, string(data), `"ruleId": "reserved-keyword"`)
+	assert.Contains(t, string(data), `"ruleId": "hook-failed"`)
+	// failed runs are reported
+	assert.NoError(t, os.WriteFile("tpl/templates/module.yaml.tpl", []byte("{{ .Module.Unknown }}"), 0644))
+	execute(t, "generate solution ./apigear/report.solution.yaml --report build/report.json")
+	data, err = os.ReadFile("build/report.json")
+	assert.NoError(t, err)
+	report = sol.Report{}
+	assert.NoError(t, json.Unmarshal(data, &report))
+	assert.Equal(t, "failed", report.Status)
+	assert.Equal(t, sol.TargetFailed, report.Targets[0].Status)
+	assert.Equal(t, gen.DocumentFailed, report.Targets[0].Documents[0].Action)
+	assert.Contains(t, report.Targets[0].Documents[0].Error, "Unknown")
+	// runs failing before any target is generated are reported
+	output = execute(t, "generate solution ./apigear/report.solution.yaml --profile nightly --report build/report.json")
+	assert.Contains(t, output, "report written to build/report.json")
+	data, err = os.ReadFile("build/report.json")
+	assert.NoError(t, err)
+	report = sol.Report{}
+	assert.NoError(t, json.Unmarshal(data, &report))
+	assert.Equal(t, "failed", report.Status)
+	assert.Contains(t, report.Error, "unknown profile nightly")
+	assert.Empty(t, report.Targets)
+}
@@ -0,0 +1,232 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apigear-io/cli/pkg/gen"
+	"github.com/apigear-io/cli/pkg/repos"
+	"github.com/apigear-io/cli/pkg/sol"
+	"github.com/apigear-io/cli/pkg/spec"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupTemplateCache replaces the template cache with a temporary cache
+// containing the template repo demo/template-lock@v1.0.0.
+// It returns a function committing a new version of the module template.
+func setupTemplateCache(t *testing.T) func(content string) string {
+	t.Helper()
+	cacheDir := t.TempDir()
+	orig := repos.Cache
+	repos.Cache = repos.New(cacheDir)
+	t.Cleanup(func() {
+		repos.Cache = orig
+	})
+	dir := filepath.Join(cacheDir, "demo", "template-lock@v1.0.0")
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	_, err = repo.CreateRemote(&config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{"https://github.com/demo/template-lock.git"},
+	})
+	require.NoError(t, err)
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	rules := `features:
+  - name: core
+    scopes:
+      - match: module
+        documents:
+          - { source: "module.txt.tpl", target: "{{.Module.Name}}.txt" }
+`
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "templates"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "rules.yaml"), []byte(rules), 0644))
+	commit := func(content string) string {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "templates", "module.txt.tpl"), []byte(content), 0644))
+		_, err := wt.Add(".")
+		require.NoError(t, err)
+		hash, err := wt.Commit(content, &git.CommitOptions{
+			Author: &object.Signature{Name: "demo", Email: "demo@example.com", When: time.Now()},
+		})
+		require.NoError(t, err)
+		return hash.String()
+	}
+	first := commit("v1 {{.Module.Name}}\n")
+	_, err = repo.CreateTag("v1.0.0", plumbing.NewHash(first), nil)
+	require.NoError(t, err)
+	return commit
+}
+
+func TestGenerateSolutionLockCmd(t *testing.T) {
+	setup(t)
+	commit := setupTemplateCache(t)
+	solution := `schema: apigear.solution/1.0
+targets:
+  - name: demo
+    inputs: [test.module.yaml]
+    output: out
+    template: demo/template-lock@v1.0.0
+    force: true
+`
+	err := os.WriteFile("apigear/lock.solution.yaml", []byte(solution), 0644)
+	assert.NoError(t, err)
+	// the locked target is not yet locked
+	output := execute(t, "generate solution ./apigear/lock.solution.yaml --locked")
+	assert.Contains(t, output, "template demo/template-lock@v1.0.0 is not locked")
+	// diffs and archives do not write the lock file
+	output = execute(t, "generate solution ./apigear/lock.solution.yaml --diff")
+	assert.Contains(t, output, "+++ b/out/test.txt")
+	execute(t, "generate solution ./apigear/lock.solution.yaml --archive sdk.zip")
+	assert.FileExists(t, "sdk.zip")
+	assert.NoFileExists(t, "apigear/apigear.lock")
+	execute(t, "generate solution ./apigear/lock.solution.yaml")
+	content, err := os.ReadFile("apigear/apigear.lock")
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "repo: demo/template-lock@v1.0.0")
+	// a new commit in the cached template does not change the locked output
+	commit("v2 {{.Module.Name}}\n")
+	execute(t, "generate solution ./apigear/lock.solution.yaml --locked")
+	out, err := os.ReadFile("apigear/out/test.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1 test\n", string(out))
+	// the locked commit is checked out without changing the cached template
+	dir, err := repos.Cache.GetTemplateDir("demo/template-lock@v1.0.0")
+	assert.NoError(t, err)
+	tpl, err := os.ReadFile(filepath.Join(dir, "templates", "module.txt.tpl"))
+	assert.NoError(t, err)
+	assert.Equal(t, "v2 {{.Module.Name}}\n", string(tpl))
+	// removed targets are pruned from the lock file
+	err = os.WriteFile("apigear/lock.solution.yaml", []byte(strings.Replace(solution, "name: demo", "name: sdk", 1)), 0644)
+	assert.NoError(t, err)
+	output = execute(t, "template lock ./apigear/lock.solution.yaml --update")
+	assert.Contains(t, output, "lock file")
+	assert.Regexp(t, `sdk\s+demo/template-lock@v1.0.0\s+demo/template-lock@v1.0.0`, output)
+	content, err = os.ReadFile("apigear/apigear.lock")
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "demo:")
+}
+
+func TestRunDocLock(t *testing.T) {
+	setup(t)
+	setupTemplateCache(t)
+	rootDir, err := filepath.Abs("apigear")
+	require.NoError(t, err)
+	doc := &spec.SolutionDoc{
+		RootDir: rootDir,
+		Targets: []*spec.SolutionTarget{
+			{Name: "demo", Inputs: []string{"test.module.yaml"}, Output: "out", Template: "demo/template-lock@v1.0.0"},
+		},
+	}
+	// in memory runs do not write the lock file
+	runner := sol.NewRunner()
+	runner.Options.Output = gen.NewMemoryWriter(rootDir)
+	err = runner.RunDoc(context.Background(), doc.RootDir, doc)
+	require.NoError(t, err)
+	assert.NoFileExists(t, "apigear/apigear.lock")
+	err = sol.NewRunner().RunDoc(context.Background(), doc.RootDir, doc)
+	require.NoError(t, err)
+	content, err := os.ReadFile("apigear/apigear.lock")
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "repo: demo/template-lock@v1.0.0")
+}
+
+// setupTemplateRegistry replaces the template registry with a registry
+// listing the given versions of demo/template-lock
+func setupTemplateRegistry(t *testing.T, versions ...string) {
+	t.Helper()
+	dir := t.TempDir()
+	orig := repos.Registry
+	repos.Registry = repos.NewRegistry(dir, "")
+	t.Cleanup(func() {
+		repos.Registry = orig
+	})
+	entries := []string{}
+	for _, v := range versions {
+		entries = append(entries, `{ "name": "`+v+`", "version": "`+v+`" }`)
+	}
+	registry := `{ "name": "test", "entries": [ { "name": "demo/template-lock", "git": "https://github.com/demo/template-lock.git", "versions": [` + strings.Join(entries, ", ") + `] } ] }`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "registry.json"), []byte(registry), 0644))
+}
+
+func TestGenerateSolutionVersionRangeCmd(t *testing.T) {
+	setup(t)
+	setupTemplateCache(t)
+	setupTemplateRegistry(t, "v0.9.0", "v1.0.0", "v2.0.0")
+	solution := `schema: apigear.solution/1.0
+targets:
+  - name: demo
+    inputs: [test.module.yaml]
+    output: out
+    template: demo/template-lock@^1.0
+`
+	err := os.WriteFile("apigear/range.solution.yaml", []byte(solution), 0644)
+	assert.NoError(t, err)
+	execute(t, "generate solution ./apigear/range.solution.yaml")
+	assert.FileExists(t, "apigear/out/test.txt")
+	content, err := os.ReadFile("apigear/apigear.lock")
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "template: demo/template-lock@^1.0")
+	assert.Contains(t, string(content), "repo: demo/template-lock@v1.0.0")
+	// ranges without a matching version are reported
+	output := execute(t, "template install demo/template-lock@~2.1")
+	assert.Contains(t, output, "template demo/template-lock: no version satisfies ~2.1 (available: v0.9.0, v1.0.0, v2.0.0)")
+	output = execute(t, "template install demo/template-lock --version <2")
+	assert.Contains(t, output, "using template demo/template-lock@v1.0.0")
+}
+
+func TestTemplateEnginePolicyCmd(t *testing.T) {
+	setup(t)
+	setupTemplateCache(t)
+	dir, err := repos.Cache.GetTemplateDir("demo/template-lock@v1.0.0")
+	require.NoError(t, err)
+	rules := `engines:
+  filters: [cpp, cobol]
+features:
+  - name: core
+    scopes:
+      - match: module
+        documents:
+          - { source: "module.txt.tpl", target: "{{.Module.Name}}.txt" }
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "rules.yaml"), []byte(rules), 0644))
+	solution := `schema: apigear.solution/1.0
+targets:
+  - name: demo
+    inputs: [test.module.yaml]
+    output: out
+    template: demo/template-lock@v1.0.0
+`
+	err = os.WriteFile("apigear/engine.solution.yaml", []byte(solution), 0644)
+	assert.NoError(t, err)
+	// unmet requirements are warnings by default
+	output := execute(t, "template install demo/template-lock@v1.0.0")
+	assert.Contains(t, output, "template requires filter pack cobol")
+	assert.Contains(t, output, "using template demo/template-lock@v1.0.0")
+	output = execute(t, "generate solution ./apigear/engine.solution.yaml")
+	assert.Contains(t, output, "template requires filter pack cobol")
+	assert.Contains(t, output, "target demo: succeeded")
+	// the solution policy fails the target before writing files
+	assert.NoError(t, os.RemoveAll("apigear/out"))
+	err = os.WriteFile("apigear/engine.solution.yaml", []byte("enginePolicy: error\n"+solution), 0644)
+	assert.NoError(t, err)
+	output = execute(t, "generate solution ./apigear/engine.solution.yaml")
+	assert.Contains(t, output, "target demo: failed")
+	assert.Contains(t, output, "template requires filter pack cobol")
+	assert.NoFileExists(t, "apigear/out/test.txt")
+	// the configured policy applies to the installation
+	t.Setenv("APIGEAR_ENGINE_POLICY", "error")
+	output = execute(t, "template install demo/template-lock@v1.0.0")
+	assert.Contains(t, output, "Error: template demo/template-lock@v1.0.0 is not compatible: template requires filter pack cobol")
+	assert.NotContains(t, output, "using template")
+	// a template installed before is kept
+	assert.DirExists(t, dir)
+}
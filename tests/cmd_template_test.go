@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// test template golden file tests
+func TestTemplateTestCmd(t *testing.T) {
+	setup(t)
+	// missing golden files are reported as added
+	output := execute(t, "template test --dir tpl")
+	assert.Contains(t, output, "FAIL demo")
+	assert.Contains(t, output, "added: test.yaml")
+	// update writes the golden files
+	output = execute(t, "template test --dir tpl --update")
+	assert.Contains(t, output, "UPDATE demo")
+	assert.FileExists(t, "tpl/tests/demo.golden/test.yaml")
+	output = execute(t, "template test --dir tpl")
+	assert.Contains(t, output, "PASS demo")
+	// changed golden files are reported with a diff
+	err := os.WriteFile("tpl/tests/demo.golden/test.yaml", []byte("name: other\n"), 0644)
+	assert.NoError(t, err)
+	output = execute(t, "template test --dir tpl")
+	assert.Contains(t, output, "FAIL demo")
+	assert.Contains(t, output, "-name: other")
+	assert.Contains(t, output, "+name: test")
+}
+
+// test template lint cross-checks rules and executes templates
+func TestTemplateLintCmd(t *testing.T) {
+	setup(t)
+	output := execute(t, "template lint --dir tpl")
+	assert.Contains(t, output, "template dir 'tpl' is valid")
+	// the templates folder resolves the rules document from the parent
+	output = execute(t, "template lint --dir tpl/templates")
+	assert.Contains(t, output, "is valid")
+	// break the rules and the templates
+	rules := `features:
+  - name: core
+    requires: [ unknown ]
+    scopes:
+      - match: struct
+        documents:
+          - source: struct.tpl
+            target: "{{ .Struct.Name }.h"
+          - source: missing.tpl
+`
+	err := os.WriteFile("tpl/rules.yaml", []byte(rules), 0644)
+	assert.NoError(t, err)
+	err = os.WriteFile("tpl/templates/struct.tpl", []byte("{{ .Struct.Name }}\n{{ .Struct.Nope }}\n"), 0644)
+	assert.NoError(t, err)
+	output = execute(t, "template lint --dir tpl")
+	assert.Contains(t, output, "rules.yaml:3:17: error: feature core requires unknown feature unknown")
+	assert.Contains(t, output, "rules.yaml:8:21: error: invalid target expression")
+	assert.Contains(t, output, "rules.yaml:9:21: error: source missing.tpl not found")
+	assert.Contains(t, output, "struct.tpl:2:10: error: executing \"struct.tpl\" at <.Struct.Nope>")
+	assert.Contains(t, output, "module.yaml.tpl: warning: template is not used by any rule")
+	assert.Contains(t, output, "template dir 'tpl' is not valid")
+}
+
+// test template info lists the template params
+func TestTemplateInfoParamsCmd(t *testing.T) {
+	setup(t)
+	rules := `params:
+  - { name: namespace, default: demo, description: "the namespace" }
+  - { name: std, enum: [c++14, c++17], default: c++17 }
+features:
+  - name: core
+    scopes:
+      - match: module
+        documents:
+          - { source: "module.yaml.tpl", target: "{{dot .Module.Name}}.yaml" }
+`
+	err := os.WriteFile("tpl/rules.yaml", []byte(rules), 0644)
+	assert.NoError(t, err)
+	output := execute(t, "template info tpl")
+	assert.Contains(t, output, "Params:")
+	assert.Regexp(t, `namespace\s+string\s+demo\s+the namespace`, output)
+	assert.Regexp(t, `std\s+string\s+c\+\+17\s+\[c\+\+14 c\+\+17\]`, output)
+}
+
+func TestTemplateCreateCsCmd(t *testing.T) {
+	setup(t)
+	output := execute(t, "template create --dir tpl-cs --lang cs")
+	assert.Contains(t, output, "create new template in tpl-cs with language cs support")
+	assert.FileExists(t, "tpl-cs/templates/api.cs.tpl")
+	// the starter template renders the synthetic lint model
+	output = execute(t, "template lint --dir tpl-cs")
+	assert.Contains(t, output, "template dir 'tpl-cs' is valid")
+}